@@ -3,6 +3,8 @@ package main
 import (
 	"database/sql"
 	"log"
+	"time"
+	_ "time/tzdata"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/llravell/simple-cards/config"
@@ -11,13 +13,35 @@ import (
 	"github.com/llravell/simple-cards/internal/usecase"
 	"github.com/llravell/simple-cards/logger"
 	"github.com/llravell/simple-cards/pkg/auth"
+	"github.com/llravell/simple-cards/pkg/pgnotify"
+	"github.com/llravell/simple-cards/pkg/pgqueue"
 	"github.com/llravell/simple-cards/pkg/quizlet"
-	"github.com/llravell/simple-cards/pkg/workerpool"
 )
 
 const (
 	quizletImportWorkersAmount = 4
 	csvImportWorkersAmount     = 4
+	apkgImportWorkersAmount    = 2
+	xlsxImportWorkersAmount    = 2
+	libraryExportWorkersAmount = 2
+
+	quizletImportQueueName = "quizlet_import"
+	csvImportQueueName     = "csv_import"
+	apkgImportQueueName    = "apkg_import"
+	xlsxImportQueueName    = "xlsx_import"
+	libraryExportQueueName = "library_export"
+
+	jobEventsChannel = "job_events"
+
+	importMaxAttempts    = 5
+	importRetryBaseDelay = 10 * time.Second
+	importRetryMaxDelay  = 15 * time.Minute
+
+	exportMaxAttempts    = 3
+	exportRetryBaseDelay = 10 * time.Second
+	exportRetryMaxDelay  = 5 * time.Minute
+
+	exportArchivesCleanupInterval = time.Hour
 )
 
 //nolint:funlen
@@ -47,38 +71,197 @@ func main() {
 	usersRepository := repository.NewUsersRepository(db)
 	modulesRepository := repository.NewModulesRepository(db)
 	cardsRepository := repository.NewCardsRepository(db)
+	statsRepository := repository.NewStatsRepository(db)
+	reviewRepository := repository.NewReviewRepository(db)
+	settingsRepository := repository.NewSettingsRepository(db)
+	sessionsRepository := repository.NewSessionsRepository(db)
+	importJobsRepository := repository.NewImportJobsRepository(db)
+	exportJobsRepository := repository.NewExportJobsRepository(db)
+	queueJobsRepository := repository.NewQueueJobsRepository(db)
 
 	jwtManager := auth.NewJWTManager(cfg.JWTSecret)
-	quizletImportWorkerPool := workerpool.New[*usecase.QuizletImportWork](quizletImportWorkersAmount)
-	csvImportWorkerPool := workerpool.New[*usecase.CSVImportWork](csvImportWorkersAmount)
+	runningImports := usecase.NewRunningImports()
+	jobEventsNotifier := pgnotify.New(db, jobEventsChannel, pgnotify.WithErrorHandler(func(err error) {
+		logger.Error().Err(err).Msg("job events notifier error")
+	}))
+	jobEvents := usecase.NewJobEvents(jobEventsNotifier, &logger)
+	logImportQueueError := pgqueue.WithErrorHandler(func(err error) {
+		logger.Error().Err(err).Msg("import queue error")
+	})
+	logExportQueueError := pgqueue.WithErrorHandler(func(err error) {
+		logger.Error().Err(err).Msg("export queue error")
+	})
+	importRetryPolicy := pgqueue.WithRetryPolicy(pgqueue.RetryPolicy{
+		MaxAttempts: importMaxAttempts,
+		BaseDelay:   importRetryBaseDelay,
+		MaxDelay:    importRetryMaxDelay,
+		IsRetryable: usecase.IsRetryableImportError,
+	})
+	exportRetryPolicy := pgqueue.WithRetryPolicy(pgqueue.RetryPolicy{
+		MaxAttempts: exportMaxAttempts,
+		BaseDelay:   exportRetryBaseDelay,
+		MaxDelay:    exportRetryMaxDelay,
+		IsRetryable: usecase.IsRetryableExportError,
+	})
+	quizletImportQueue := pgqueue.New(
+		db,
+		quizletImportQueueName,
+		usecase.NewQuizletImportCodec(
+			modulesRepository,
+			cardsRepository,
+			importJobsRepository,
+			quizletParser,
+			runningImports,
+			jobEvents,
+			&logger,
+		),
+		quizletImportWorkersAmount,
+		logImportQueueError,
+		importRetryPolicy,
+	)
+	csvImportQueue := pgqueue.New(
+		db,
+		csvImportQueueName,
+		usecase.NewCSVImportCodec(
+			modulesRepository,
+			cardsRepository,
+			importJobsRepository,
+			runningImports,
+			jobEvents,
+			&logger,
+		),
+		csvImportWorkersAmount,
+		logImportQueueError,
+		importRetryPolicy,
+	)
+	apkgImportQueue := pgqueue.New(
+		db,
+		apkgImportQueueName,
+		usecase.NewAPKGImportCodec(
+			modulesRepository,
+			importJobsRepository,
+			runningImports,
+			jobEvents,
+			&logger,
+		),
+		apkgImportWorkersAmount,
+		logImportQueueError,
+		importRetryPolicy,
+	)
+	xlsxImportQueue := pgqueue.New(
+		db,
+		xlsxImportQueueName,
+		usecase.NewXLSXImportCodec(
+			modulesRepository,
+			cardsRepository,
+			importJobsRepository,
+			runningImports,
+			jobEvents,
+			&logger,
+		),
+		xlsxImportWorkersAmount,
+		logImportQueueError,
+		importRetryPolicy,
+	)
+	libraryExportQueue := pgqueue.New(
+		db,
+		libraryExportQueueName,
+		usecase.NewLibraryExportCodec(
+			modulesRepository,
+			cardsRepository,
+			reviewRepository,
+			exportJobsRepository,
+			jobEvents,
+			&logger,
+		),
+		libraryExportWorkersAmount,
+		logExportQueueError,
+		exportRetryPolicy,
+	)
+	exportArchivesCleaner := usecase.NewExportArchivesCleaner(
+		exportJobsRepository,
+		exportArchivesCleanupInterval,
+		&logger,
+	)
 
 	healthUseCase := usecase.NewHealthUseCase(db)
 	authUseCase := usecase.NewAuthUseCase(usersRepository, jwtManager)
 	modulesUseCase := usecase.NewModulesUseCase(
 		modulesRepository,
 		cardsRepository,
+		reviewRepository,
+		importJobsRepository,
+		exportJobsRepository,
 		quizletParser,
-		quizletImportWorkerPool,
-		csvImportWorkerPool,
+		quizletImportQueue,
+		csvImportQueue,
+		apkgImportQueue,
+		xlsxImportQueue,
+		libraryExportQueue,
+		runningImports,
+		jobEvents,
 		&logger,
 	)
 	cardsUseCase := usecase.NewCardsUseCase(cardsRepository)
+	statsUseCase := usecase.NewStatsUseCase(statsRepository, reviewRepository, settingsRepository)
+	reviewUseCase := usecase.NewReviewUseCase(reviewRepository)
+	settingsUseCase := usecase.NewSettingsUseCase(settingsRepository)
+	sessionsUseCase := usecase.NewSessionsUseCase(sessionsRepository)
+	adminUseCase := usecase.NewAdminUseCase(queueJobsRepository)
+
+	jobEventsNotifier.Listen()
+	quizletImportQueue.ProcessQueue()
+	csvImportQueue.ProcessQueue()
+	apkgImportQueue.ProcessQueue()
+	xlsxImportQueue.ProcessQueue()
+	libraryExportQueue.ProcessQueue()
+	exportArchivesCleaner.Run()
+
+	defer func() {
+		jobEventsNotifier.Close()
+		jobEventsNotifier.Wait()
+	}()
 
-	quizletImportWorkerPool.ProcessQueue()
-	csvImportWorkerPool.ProcessQueue()
+	defer func() {
+		quizletImportQueue.Close()
+
+		logger.Info().Msg("quizlet import queue closing...")
+		quizletImportQueue.Wait()
+	}()
+
+	defer func() {
+		csvImportQueue.Close()
+
+		logger.Info().Msg("csv import queue closing...")
+		csvImportQueue.Wait()
+	}()
+
+	defer func() {
+		apkgImportQueue.Close()
+
+		logger.Info().Msg("apkg import queue closing...")
+		apkgImportQueue.Wait()
+	}()
+
+	defer func() {
+		xlsxImportQueue.Close()
+
+		logger.Info().Msg("xlsx import queue closing...")
+		xlsxImportQueue.Wait()
+	}()
 
 	defer func() {
-		quizletImportWorkerPool.Close()
+		libraryExportQueue.Close()
 
-		logger.Info().Msg("quizlet import worker pool closing...")
-		quizletImportWorkerPool.Wait()
+		logger.Info().Msg("library export queue closing...")
+		libraryExportQueue.Wait()
 	}()
 
 	defer func() {
-		csvImportWorkerPool.Close()
+		exportArchivesCleaner.Close()
 
-		logger.Info().Msg("csv import worker pool closing...")
-		csvImportWorkerPool.Wait()
+		logger.Info().Msg("export archives cleaner closing...")
+		exportArchivesCleaner.Wait()
 	}()
 
 	app.New(
@@ -86,8 +269,15 @@ func main() {
 		authUseCase,
 		modulesUseCase,
 		cardsUseCase,
+		statsUseCase,
+		reviewUseCase,
+		settingsUseCase,
+		sessionsUseCase,
+		adminUseCase,
+		jobEvents,
 		jwtManager,
 		logger,
 		app.Addr(cfg.Addr),
+		app.AdminToken(cfg.AdminToken),
 	).Run()
 }
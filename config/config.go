@@ -19,6 +19,7 @@ type Config struct {
 	Addr        string `env:"RUN_ADDRESS"`
 	DatabaseURI string `env:"DATABASE_URI"`
 	JWTSecret   string `env:"JWT_SECRET"`
+	AdminToken  string `env:"ADMIN_TOKEN"`
 }
 
 func NewConfig() (*Config, error) {
@@ -15,107 +15,118 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/modules/": {
+        "/api/admin/queue/dead": {
             "get": {
                 "security": [
                     {
-                        "UsersAuth": []
+                        "AdminAuth": []
                     }
                 ],
+                "description": "Jobs which have run out of attempts or failed with an error which is not worth retrying",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "modules"
+                    "admin"
+                ],
+                "summary": "Get dead queue jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name, e.g. quizlet_import or csv_import",
+                        "name": "queue",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all user's modules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/entity.Module"
+                                "$ref": "#/definitions/entity.QueueJob"
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
+            }
+        },
+        "/api/admin/queue/dead/{job_id}/requeue": {
             "post": {
                 "security": [
                     {
-                        "UsersAuth": []
+                        "AdminAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "The job gets a fresh set of attempts and is done as soon as a worker is free,\nthe failed import or export job of the work is reopened",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "modules"
+                    "admin"
                 ],
-                "summary": "Create new module",
+                "summary": "Requeue dead queue job",
                 "parameters": [
                     {
-                        "description": "Module params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateOrUpdateModuleRequest"
-                        }
+                        "type": "integer",
+                        "description": "Queue job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Module"
+                            "$ref": "#/definitions/entity.QueueJob"
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/modules/import/csv": {
-            "post": {
+        "/api/cards/leeches": {
+            "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "consumes": [
-                    "multipart/form-data"
+                "description": "Leeches are cards which the user keeps failing, most failed first",
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "modules"
-                ],
-                "summary": "Import module from csv file",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "CSV file with max size 1 MB",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    }
+                    "cards"
                 ],
+                "summary": "Get leeches from all user's modules",
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.LeechCard"
+                            }
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -123,37 +134,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/modules/import/quizlet": {
-            "post": {
+        "/api/events": {
+            "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
+                "description": "Server-Sent Events stream of import jobs lifecycle: queued, started, progress, completed, failed\nand canceled. Each event is named after its type and carries entity.JobEvent as json data.\nA comment is sent periodically to keep the connection alive.",
+                "produces": [
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "modules"
-                ],
-                "summary": "Import module from quizlet public module",
-                "parameters": [
-                    {
-                        "description": "Import module params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.QuizletImportRequest"
-                        }
-                    }
+                    "events"
                 ],
+                "summary": "Stream user's job events",
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.JobEvent"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -161,45 +162,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/modules/{module_uuid}/": {
+        "/api/modules/": {
             "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
+                "description": "Each module contains user's learning progress",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "modules"
                 ],
-                "summary": "Get module with cards",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Module UUID",
-                        "name": "module_uuid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Get all user's modules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ModuleWithCards"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Module"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found"
-                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "UsersAuth": []
@@ -214,15 +207,8 @@ const docTemplate = `{
                 "tags": [
                     "modules"
                 ],
-                "summary": "Update module",
+                "summary": "Create new module",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Module UUID",
-                        "name": "module_uuid",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Module params",
                         "name": "request",
@@ -234,8 +220,8 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/entity.Module"
                         }
@@ -243,36 +229,62 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
-                    "404": {
-                        "description": "Not Found"
-                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/modules/export": {
+            "post": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
+                "description": "The archive with a file of the format per module is built asynchronously,\nthe whole library is exported when no module is selected. The processed archive\ncan be downloaded till expires_at of the export job.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "modules"
                 ],
-                "summary": "Delete module",
+                "summary": "Export modules to zip archive",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Module UUID",
-                        "name": "module_uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "Export params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LibraryExportRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Accepted"
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Export job URL"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -280,25 +292,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/modules/{module_uuid}/cards/": {
+        "/api/modules/export/{job_uuid}": {
             "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
+                "description": "Export job with status 'created' | 'processing' | 'processed' | 'failed'",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cards"
+                    "modules"
                 ],
-                "summary": "Get all module's cards",
+                "summary": "Get export job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Module UUID",
-                        "name": "module_uuid",
+                        "description": "Export job UUID",
+                        "name": "job_uuid",
                         "in": "path",
                         "required": true
                     }
@@ -307,15 +320,9 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.Card"
-                            }
+                            "$ref": "#/definitions/entity.ExportJob"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "404": {
                         "description": "Not Found"
                     },
@@ -323,149 +330,272 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/modules/export/{job_uuid}/download": {
+            "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Zip archive of the processed export job, it's gone after the job's expires_at.",
                 "produces": [
-                    "application/json"
+                    "application/zip"
                 ],
                 "tags": [
-                    "cards"
+                    "modules"
                 ],
-                "summary": "Add new card to module",
+                "summary": "Download export archive",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Module UUID",
-                        "name": "module_uuid",
+                        "description": "Export job UUID",
+                        "name": "job_uuid",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found"
                     },
+                    "409": {
+                        "description": "Conflict"
+                    },
+                    "410": {
+                        "description": "Gone"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/import": {
+            "get": {
+                "security": [
                     {
-                        "description": "Card params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateCardRequest"
-                        }
+                        "UsersAuth": []
                     }
                 ],
+                "description": "Latest import jobs first with status 'created' | 'processing' | 'processed' | 'failed' | 'canceled'",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Get user's import jobs",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Card"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ImportJob"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
-                    "404": {
-                        "description": "Not Found"
-                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/modules/{module_uuid}/cards/{card_uuid}": {
-            "put": {
+        "/api/modules/import/apkg": {
+            "post": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
+                "description": "Every deck of the package becomes a new module, media files are skipped.\nPackages exported without \"Support older Anki versions\" option are not supported.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cards"
+                    "modules"
                 ],
-                "summary": "Update card",
+                "summary": "Import modules from anki package",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Module UUID",
-                        "name": "module_uuid",
-                        "in": "path",
+                        "type": "file",
+                        "description": "Anki .apkg file with max size 64 MB",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Card UUID",
-                        "name": "card_uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "Term note field number or name, 1 by default",
+                        "name": "term_field",
+                        "in": "formData"
                     },
                     {
-                        "description": "Card update params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateCardRequest"
-                        }
+                        "type": "string",
+                        "description": "Meaning note field number or name, 2 by default",
+                        "name": "meaning_field",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/entity.Card"
+                            "$ref": "#/definitions/entity.ImportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Import job URL"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "413": {
+                        "description": "Request Entity Too Large"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
                     },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/modules/import/csv": {
+            "post": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "cards"
+                    "modules"
                 ],
-                "summary": "Delete card",
+                "summary": "Import module from csv file",
                 "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file with max size 1 MB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Module UUID",
+                        "description": "Existing module to merge cards into",
                         "name": "module_uuid",
-                        "in": "path",
-                        "required": true
+                        "in": "formData"
                     },
                     {
+                        "enum": [
+                            "append",
+                            "skip-duplicates",
+                            "update-existing"
+                        ],
                         "type": "string",
-                        "description": "Card UUID",
-                        "name": "card_uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "Merge strategy",
+                        "name": "strategy",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "comma",
+                            "semicolon",
+                            "tab",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "Delimiter, comma by default",
+                        "name": "delimiter",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "none",
+                            "present",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "First row header, none by default",
+                        "name": "header",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Term column number or header name, 1 by default",
+                        "name": "term_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Meaning column number or header name, 2 by default",
+                        "name": "meaning_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Swap terms and meanings",
+                        "name": "swap",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow quotes inside unquoted fields",
+                        "name": "lazy_quotes",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "utf-8",
+                            "utf-16",
+                            "utf-16le",
+                            "utf-16be",
+                            "windows-1251",
+                            "koi8-r",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "Encoding",
+                        "name": "encoding",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip malformed rows instead of failing the import",
+                        "name": "lenient",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Accepted"
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Import job URL"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -473,41 +603,56 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found"
                     },
+                    "413": {
+                        "description": "Request Entity Too Large"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/modules/{module_uuid}/export/csv": {
-            "get": {
+        "/api/modules/import/json": {
+            "post": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
+                "description": "The archived module is restored as a new module with its settings, cards and review states.\nArchives of older versions are migrated to the latest one.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "text/csv"
+                    "application/json"
                 ],
                 "tags": [
                     "modules"
                 ],
-                "summary": "Export module to csv file",
+                "summary": "Import module from json archive",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Module UUID",
-                        "name": "module_uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "Module archive with max size 64 MB",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ModuleArchive"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Module"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "413": {
+                        "description": "Request Entity Too Large"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -515,23 +660,112 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/user/login": {
+        "/api/modules/import/preview": {
             "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Parses first cards of a csv file sent as multipart form or of a quizlet module sent as json\nand returns them with warnings, nothing is stored. Delimiter, encoding and header of the csv file\nare detected unless they are passed, the returned dialect can be sent with the csv import.",
                 "consumes": [
+                    "multipart/form-data",
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "modules"
                 ],
-                "summary": "Verify user creds and login",
+                "summary": "Preview import",
                 "parameters": [
                     {
-                        "description": "User creds",
+                        "type": "integer",
+                        "description": "Max cards amount, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file with max size 1 MB",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "comma",
+                            "semicolon",
+                            "tab",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "Delimiter, detected by default",
+                        "name": "delimiter",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "none",
+                            "present",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "First row header, detected by default",
+                        "name": "header",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Term column number or header name, 1 by default",
+                        "name": "term_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Meaning column number or header name, 2 by default",
+                        "name": "meaning_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Swap terms and meanings",
+                        "name": "swap",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow quotes inside unquoted fields",
+                        "name": "lazy_quotes",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "utf-8",
+                            "utf-16",
+                            "utf-16le",
+                            "utf-16be",
+                            "windows-1251",
+                            "koi8-r",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "Encoding",
+                        "name": "encoding",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip malformed rows",
+                        "name": "lenient",
+                        "in": "formData"
+                    },
+                    {
+                        "description": "Quizlet module to preview",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthRequest"
+                            "$ref": "#/definitions/dto.QuizletImportPreviewRequest"
                         }
                     }
                 ],
@@ -539,156 +773,2447 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthResponse"
+                            "$ref": "#/definitions/entity.ImportPreview"
                         }
                     },
                     "400": {
-                        "description": "invalid data"
+                        "description": "Bad Request"
                     },
-                    "401": {
-                        "description": "verification failed"
+                    "413": {
+                        "description": "Request Entity Too Large"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
                     },
                     "500": {
-                        "description": "token building error"
+                        "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/user/register": {
+        "/api/modules/import/quizlet": {
             "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Cards are merged into the existing module with the strategy when module_uuid is passed",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "auth"
+                    "modules"
                 ],
-                "summary": "Register new user",
+                "summary": "Import module from quizlet public module",
                 "parameters": [
                     {
-                        "description": "User creds",
+                        "description": "Import module params",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthRequest"
+                            "$ref": "#/definitions/dto.QuizletImportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthResponse"
+                            "$ref": "#/definitions/entity.ImportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Import job URL"
+                            }
                         }
                     },
                     "400": {
-                        "description": "invalid data"
+                        "description": "Bad Request"
                     },
-                    "409": {
-                        "description": "user with same login already exists"
+                    "404": {
+                        "description": "Not Found"
                     },
                     "500": {
-                        "description": "token building error"
+                        "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/ping": {
-            "get": {
+        "/api/modules/import/xlsx": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Cards are read from rows of the first sheet or of the selected one, numbers and dates are read\nas they are stored. Cards are merged into the existing module with the strategy\nwhen module_uuid is passed",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "health"
+                    "modules"
+                ],
+                "summary": "Import module from xlsx workbook",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Excel .xlsx file with max size 16 MB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Existing module to merge cards into",
+                        "name": "module_uuid",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "append",
+                            "skip-duplicates",
+                            "update-existing"
+                        ],
+                        "type": "string",
+                        "description": "Merge strategy",
+                        "name": "strategy",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sheet name or number, the first sheet by default",
+                        "name": "sheet",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "none",
+                            "present",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "First row header, none by default",
+                        "name": "header",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Term column number or header name, 1 by default",
+                        "name": "term_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Meaning column number or header name, 2 by default",
+                        "name": "meaning_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Swap terms and meanings",
+                        "name": "swap",
+                        "in": "formData"
+                    }
                 ],
-                "summary": "Check database connection",
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Import job URL"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
                     },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AuthRequest": {
-            "type": "object",
-            "required": [
-                "login",
-                "password"
-            ],
-            "properties": {
-                "login": {
+        },
+        "/api/modules/import/{job_uuid}": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Get import job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job UUID",
+                        "name": "job_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Cancels a queued import or interrupts a running one. Finished jobs can't be canceled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Cancel import job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job UUID",
+                        "name": "job_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/import/{job_uuid}/errors": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Rows skipped by the import with line numbers, reasons and row contents",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Get import job row errors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job UUID",
+                        "name": "job_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ImportRowError"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/import/{job_uuid}/errors/csv": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Download import job row errors report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job UUID",
+                        "name": "job_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Get module with cards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ModuleWithCards"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Update module",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Module params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateOrUpdateModuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Module"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Delete module",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/cards/": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Leeches filter returns cards which the user keeps failing with their review states",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Get all module's cards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "leeches"
+                        ],
+                        "type": "string",
+                        "description": "Cards filter",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Card"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Add new card to module",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Card params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateCardRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Card"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/cards/{card_uuid}": {
+            "put": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Update card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Card UUID",
+                        "name": "card_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Card update params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateCardRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Card"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Delete card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Card UUID",
+                        "name": "card_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/cards/{card_uuid}/check": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Answer is compared with the card's meaning by default ignoring letter case, diacritics\nand punctuation. Articles are ignored too when the language of the side is given.\nVariants of the expected text can be separated with \";\", \"/\" or \", \" not followed by a digit.\nAn answer with a few typos is almost correct. Recorded answer is saved like a sent one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Check typed answer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Card UUID",
+                        "name": "card_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answer params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckAnswerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AnswerCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/cards/{card_uuid}/suspend": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Suspended card does not appear in due cards until it is resumed or edited.\nOnly reviewed cards can be suspended.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Suspend card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Card UUID",
+                        "name": "card_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewState"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Resume suspended card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Card UUID",
+                        "name": "card_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewState"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/due": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Returns overdue cards first, then cards which have never been reviewed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get module's cards due for review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max cards amount, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DueCard"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/export/apkg": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "The module becomes a deck of \"Basic\" notes, or of \"Basic (and reversed card)\" notes when reversed.\nReview states of the cards are exported as anki scheduling data, reversed cards are new.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Export module to anki package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add reversed cards",
+                        "name": "reversed",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/export/csv": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Export module to csv file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/export/json": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "The archive is a lossless backup of the module with its settings, cards and review states.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Export module to json archive",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ModuleArchive"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/export/xlsx": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Cards are written to a single sheet named after the module, terms are in the first column\nand meanings are in the second one. All cells are text.",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "modules"
+                ],
+                "summary": "Export module to xlsx workbook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/sessions/": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Returns the latest sessions first, only finished sessions have a summary",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get module's study sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.StudySession"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Answers linked to the session are included in its summary",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Start study session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StudySession"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/sessions/{session_uuid}/finish": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Returns the session with its summary",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Finish study session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session UUID",
+                        "name": "session_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StudySession"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/modules/{module_uuid}/settings": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get module's settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ModuleSettings"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Empty scheduler means that the user's one is used",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update module's settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settings params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateModuleSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ModuleSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/review/due": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Returns overdue cards first, then cards which have never been reviewed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get cards due for review from all user's modules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max cards amount, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DueCard"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/stats/": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get user's module stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Module UUID",
+                        "name": "module_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period start, date or RFC3339 timestamp",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period end, date or RFC3339 timestamp",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.CardStats"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Explicit grade from 0 to 5 overrides is_correct, grade 3 and higher is a correct answer.\nAnswers may be linked to an active study session of the card's module.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Save user's answers",
+                "parameters": [
+                    {
+                        "description": "Answers batch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveAnswersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Answer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/stats/activity": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Returns days with answers from all modules, the last year by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get user's daily review activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Period start date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period end date, inclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of day boundaries, the user's one by default",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DailyActivity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/stats/sync": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Every answer has a client generated ID, answers with already uploaded IDs are reported\nas duplicates and skipped, so a failed batch can be retried. Answers are applied to review\nstates in chronological order of their client timestamps, even if they are older than\nanswers uploaded before.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Sync answers of an offline client",
+                "parameters": [
+                    {
+                        "description": "Answers batch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SyncAnswersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SyncResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/user/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Verify user creds and login",
+                "parameters": [
+                    {
+                        "description": "User creds",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid data"
+                    },
+                    "401": {
+                        "description": "verification failed"
+                    },
+                    "500": {
+                        "description": "token building error"
+                    }
+                }
+            }
+        },
+        "/api/user/register": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "User creds",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid data"
+                    },
+                    "409": {
+                        "description": "user with same login already exists"
+                    },
+                    "500": {
+                        "description": "token building error"
+                    }
+                }
+            }
+        },
+        "/api/user/settings": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get user's settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserSettings"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Only passed settings are updated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update user's settings",
+                "parameters": [
+                    {
+                        "description": "Settings params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/user/streak": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Days are counted in the user's time zone, progress is measured in daily goal units",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get user's study streak",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StudyStreak"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "tags": [
+                    "health"
+                ],
+                "summary": "Check database connection",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "csvcards.Dialect": {
+            "type": "object",
+            "properties": {
+                "delimiter": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "header": {
+                    "type": "string"
+                },
+                "lazy_quotes": {
+                    "type": "boolean"
+                },
+                "lenient": {
+                    "type": "boolean"
+                },
+                "meaning_column": {
+                    "type": "string"
+                },
+                "swap": {
+                    "type": "boolean"
+                },
+                "term_column": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AnswerRequest": {
+            "type": "object",
+            "required": [
+                "answered_at",
+                "card_uuid",
+                "module_uuid"
+            ],
+            "properties": {
+                "answered_at": {
+                    "type": "string"
+                },
+                "card_uuid": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 0
+                },
+                "is_correct": {
+                    "type": "boolean"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "response_time_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "session_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AuthRequest": {
+            "type": "object",
+            "required": [
+                "login",
+                "password"
+            ],
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CheckAnswerRequest": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "de",
+                        "fr",
+                        "es"
+                    ]
+                },
+                "record": {
+                    "type": "boolean"
+                },
+                "response_time_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "session_uuid": {
+                    "type": "string"
+                },
+                "side": {
+                    "type": "string",
+                    "enum": [
+                        "term",
+                        "meaning"
+                    ]
+                }
+            }
+        },
+        "dto.CreateCardRequest": {
+            "type": "object",
+            "required": [
+                "meaning",
+                "term"
+            ],
+            "properties": {
+                "meaning": {
+                    "type": "string"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateOrUpdateModuleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.LibraryExportRequest": {
+            "type": "object",
+            "required": [
+                "format"
+            ],
+            "properties": {
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "csv",
+                        "xlsx",
+                        "apkg",
+                        "json"
+                    ]
+                },
+                "module_uuids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.QuizletImportPreviewRequest": {
+            "type": "object",
+            "required": [
+                "quizlet_module_id"
+            ],
+            "properties": {
+                "quizlet_module_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.QuizletImportRequest": {
+            "type": "object",
+            "required": [
+                "quizlet_module_id"
+            ],
+            "properties": {
+                "module_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "quizlet_module_id": {
+                    "type": "string"
+                },
+                "strategy": {
+                    "type": "string",
+                    "enum": [
+                        "append",
+                        "skip-duplicates",
+                        "update-existing"
+                    ]
+                }
+            }
+        },
+        "dto.SaveAnswersRequest": {
+            "type": "object",
+            "required": [
+                "answers"
+            ],
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.AnswerRequest"
+                    }
+                }
+            }
+        },
+        "dto.SyncAnswerRequest": {
+            "type": "object",
+            "required": [
+                "answered_at",
+                "card_uuid",
+                "client_id",
+                "module_uuid"
+            ],
+            "properties": {
+                "answered_at": {
+                    "type": "string"
+                },
+                "card_uuid": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "grade": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 0
+                },
+                "is_correct": {
+                    "type": "boolean"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "response_time_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "session_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SyncAnswersRequest": {
+            "type": "object",
+            "required": [
+                "answers"
+            ],
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.SyncAnswerRequest"
+                    }
+                }
+            }
+        },
+        "dto.UpdateCardRequest": {
+            "type": "object",
+            "properties": {
+                "meaning": {
+                    "type": "string"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdateModuleSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "scheduler": {
+                    "type": "string",
+                    "enum": [
+                        "sm2",
+                        "fsrs"
+                    ]
+                }
+            }
+        },
+        "dto.UpdateUserSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "daily_goal_type": {
+                    "type": "string",
+                    "enum": [
+                        "cards",
+                        "minutes"
+                    ]
+                },
+                "daily_goal_value": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1
+                },
+                "desired_retention": {
+                    "type": "number",
+                    "maximum": 0.99,
+                    "minimum": 0.7
+                },
+                "leech_threshold": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "scheduler": {
+                    "type": "string",
+                    "enum": [
+                        "sm2",
+                        "fsrs"
+                    ]
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Answer": {
+            "type": "object",
+            "properties": {
+                "answered_at": {
+                    "type": "string"
+                },
+                "card_uuid": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "interval_after": {
+                    "type": "integer"
+                },
+                "interval_before": {
+                    "type": "integer"
+                },
+                "is_correct": {
+                    "type": "boolean"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "response_time_ms": {
+                    "type": "integer"
+                },
+                "session_uuid": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AnswerCheck": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "$ref": "#/definitions/entity.Answer"
+                },
+                "distance": {
+                    "type": "integer"
+                },
+                "expected": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "verdict": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ArchivedCard": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "meaning": {
+                    "type": "string"
+                },
+                "review_state": {
+                    "$ref": "#/definitions/entity.ArchivedReviewState"
+                },
+                "term": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ArchivedModule": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ArchivedCard"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "settings": {
+                    "$ref": "#/definitions/entity.ModuleSettings"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ArchivedReviewState": {
+            "type": "object",
+            "properties": {
+                "difficulty": {
+                    "type": "number"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "ease_factor": {
+                    "type": "number"
+                },
+                "interval_days": {
+                    "type": "integer"
+                },
+                "is_leech": {
+                    "type": "boolean"
+                },
+                "is_suspended": {
+                    "type": "boolean"
+                },
+                "lapses": {
+                    "type": "integer"
+                },
+                "last_reviewed_at": {
+                    "type": "string"
+                },
+                "repetitions": {
+                    "type": "integer"
+                },
+                "stability": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.Card": {
+            "type": "object",
+            "properties": {
+                "meaning": {
+                    "type": "string"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "term": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CardStats": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "card_uuid": {
+                    "type": "string"
+                },
+                "correct_count": {
+                    "type": "integer"
+                },
+                "incorrect_count": {
+                    "type": "integer"
+                },
+                "last_reviewed_at": {
+                    "type": "string"
+                },
+                "meaning": {
+                    "type": "string"
+                },
+                "streak": {
+                    "type": "integer"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DailyActivity": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "answers_count": {
+                    "type": "integer"
+                },
+                "correct_count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "time_spent_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DueCard": {
+            "type": "object",
+            "properties": {
+                "meaning": {
+                    "type": "string"
+                },
+                "module_uuid": {
                     "type": "string"
                 },
-                "password": {
+                "review_state": {
+                    "$ref": "#/definitions/entity.ReviewState"
+                },
+                "term": {
+                    "type": "string"
+                },
+                "uuid": {
                     "type": "string"
                 }
             }
         },
-        "dto.AuthResponse": {
+        "entity.ExportJob": {
             "type": "object",
             "properties": {
-                "token": {
+                "archive_size": {
+                    "type": "integer"
+                },
+                "cards_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "module_uuids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "modules_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "uuid": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreateCardRequest": {
+        "entity.ImportJob": {
             "type": "object",
-            "required": [
-                "meaning",
-                "term"
-            ],
             "properties": {
-                "meaning": {
+                "cards_count": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "term": {
+                "error_message": {
+                    "type": "string"
+                },
+                "errors_count": {
+                    "type": "integer"
+                },
+                "module_name": {
+                    "type": "string"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "module_uuids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "skipped_cards_count": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_cards_count": {
+                    "type": "integer"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "uuid": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreateOrUpdateModuleRequest": {
+        "entity.ImportPreview": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 100
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ImportPreviewCard"
+                    }
+                },
+                "dialect": {
+                    "$ref": "#/definitions/csvcards.Dialect"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "dto.QuizletImportRequest": {
+        "entity.ImportPreviewCard": {
             "type": "object",
-            "required": [
-                "module_name",
-                "quizlet_module_id"
-            ],
             "properties": {
-                "module_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "meaning": {
+                    "type": "string"
                 },
-                "quizlet_module_id": {
+                "term": {
                     "type": "string"
                 }
             }
         },
-        "dto.UpdateCardRequest": {
+        "entity.ImportRowError": {
             "type": "object",
             "properties": {
-                "meaning": {
+                "line": {
+                    "type": "integer"
+                },
+                "raw": {
                     "type": "string"
                 },
-                "term": {
+                "reason": {
                     "type": "string"
                 }
             }
         },
-        "entity.Card": {
+        "entity.JobEvent": {
+            "type": "object",
+            "properties": {
+                "job_kind": {
+                    "type": "string"
+                },
+                "job_uuid": {
+                    "type": "string"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.LeechCard": {
             "type": "object",
             "properties": {
                 "meaning": {
@@ -697,6 +3222,9 @@ const docTemplate = `{
                 "module_uuid": {
                     "type": "string"
                 },
+                "review_state": {
+                    "$ref": "#/definitions/entity.ReviewState"
+                },
                 "term": {
                     "type": "string"
                 },
@@ -711,6 +3239,9 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "progress": {
+                    "$ref": "#/definitions/entity.ModuleProgress"
+                },
                 "user_uuid": {
                     "type": "string"
                 },
@@ -719,6 +3250,54 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.ModuleArchive": {
+            "type": "object",
+            "properties": {
+                "exported_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "module": {
+                    "$ref": "#/definitions/entity.ArchivedModule"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ModuleProgress": {
+            "type": "object",
+            "properties": {
+                "cards_count": {
+                    "type": "integer"
+                },
+                "due_today_count": {
+                    "type": "integer"
+                },
+                "last_studied_at": {
+                    "type": "string"
+                },
+                "learning_count": {
+                    "type": "integer"
+                },
+                "mastered_count": {
+                    "type": "integer"
+                },
+                "new_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ModuleSettings": {
+            "type": "object",
+            "properties": {
+                "scheduler": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.ModuleWithCards": {
             "type": "object",
             "properties": {
@@ -731,6 +3310,9 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "progress": {
+                    "$ref": "#/definitions/entity.ModuleProgress"
+                },
                 "user_uuid": {
                     "type": "string"
                 },
@@ -738,9 +3320,196 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "entity.QueueJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "string"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReviewState": {
+            "type": "object",
+            "properties": {
+                "card_uuid": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "number"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "ease_factor": {
+                    "type": "number"
+                },
+                "interval_days": {
+                    "type": "integer"
+                },
+                "is_leech": {
+                    "type": "boolean"
+                },
+                "is_suspended": {
+                    "type": "boolean"
+                },
+                "lapses": {
+                    "type": "integer"
+                },
+                "last_reviewed_at": {
+                    "type": "string"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "repetitions": {
+                    "type": "integer"
+                },
+                "stability": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.SessionSummary": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "answers_count": {
+                    "type": "integer"
+                },
+                "cards_seen": {
+                    "type": "integer"
+                },
+                "correct_count": {
+                    "type": "integer"
+                },
+                "demoted_count": {
+                    "type": "integer"
+                },
+                "promoted_count": {
+                    "type": "integer"
+                },
+                "time_spent_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.StudySession": {
+            "type": "object",
+            "properties": {
+                "finished_at": {
+                    "type": "string"
+                },
+                "module_uuid": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/entity.SessionSummary"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.StudyStreak": {
+            "type": "object",
+            "properties": {
+                "current_streak": {
+                    "type": "integer"
+                },
+                "daily_goal_type": {
+                    "type": "string"
+                },
+                "daily_goal_value": {
+                    "type": "integer"
+                },
+                "goal_reached": {
+                    "type": "boolean"
+                },
+                "longest_streak": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "today_progress": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.SyncResult": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Answer"
+                    }
+                },
+                "duplicates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.UserSettings": {
+            "type": "object",
+            "properties": {
+                "daily_goal_type": {
+                    "type": "string"
+                },
+                "daily_goal_value": {
+                    "type": "integer"
+                },
+                "desired_retention": {
+                    "type": "number"
+                },
+                "leech_threshold": {
+                    "type": "integer"
+                },
+                "scheduler": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "AdminAuth": {
+            "type": "apiKey",
+            "name": "X-Admin-Token",
+            "in": "header"
+        },
         "UsersAuth": {
             "type": "apiKey",
             "name": "Authorization",
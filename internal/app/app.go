@@ -10,11 +10,17 @@ import (
 	"github.com/go-chi/chi/v5"
 	_ "github.com/llravell/simple-cards/docs" //nolint:blank-imports
 	httpCommon "github.com/llravell/simple-cards/internal/controller/http"
+	"github.com/llravell/simple-cards/internal/controller/http/admin"
 	"github.com/llravell/simple-cards/internal/controller/http/auth"
 	"github.com/llravell/simple-cards/internal/controller/http/cards"
+	"github.com/llravell/simple-cards/internal/controller/http/events"
 	"github.com/llravell/simple-cards/internal/controller/http/health"
 	"github.com/llravell/simple-cards/internal/controller/http/middleware"
 	"github.com/llravell/simple-cards/internal/controller/http/modules"
+	"github.com/llravell/simple-cards/internal/controller/http/review"
+	"github.com/llravell/simple-cards/internal/controller/http/sessions"
+	"github.com/llravell/simple-cards/internal/controller/http/settings"
+	"github.com/llravell/simple-cards/internal/controller/http/stats"
 	"github.com/rs/zerolog"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
@@ -33,14 +39,21 @@ func startServer(addr string, handler http.Handler) error {
 type Option func(app *App)
 
 type App struct {
-	healthUseCase  httpCommon.HealthUseCase
-	authUseCase    httpCommon.AuthUseCase
-	modulesUseCase httpCommon.ModulesUseCase
-	cardsUseCase   httpCommon.CardsUseCase
-	jwtParser      middleware.JWTParser
-	router         chi.Router
-	log            zerolog.Logger
-	addr           string
+	healthUseCase   httpCommon.HealthUseCase
+	authUseCase     httpCommon.AuthUseCase
+	modulesUseCase  httpCommon.ModulesUseCase
+	cardsUseCase    httpCommon.CardsUseCase
+	statsUseCase    httpCommon.StatsUseCase
+	reviewUseCase   httpCommon.ReviewUseCase
+	settingsUseCase httpCommon.SettingsUseCase
+	sessionsUseCase httpCommon.SessionsUseCase
+	adminUseCase    httpCommon.AdminUseCase
+	eventsUseCase   httpCommon.EventsUseCase
+	jwtParser       middleware.JWTParser
+	router          chi.Router
+	log             zerolog.Logger
+	addr            string
+	adminToken      string
 }
 
 func Addr(addr string) Option {
@@ -49,23 +62,42 @@ func Addr(addr string) Option {
 	}
 }
 
+// AdminToken enables admin routes for requests with the token.
+func AdminToken(token string) Option {
+	return func(app *App) {
+		app.adminToken = token
+	}
+}
+
 func New(
 	healthUseCase httpCommon.HealthUseCase,
 	authUseCase httpCommon.AuthUseCase,
 	modulesUseCase httpCommon.ModulesUseCase,
 	cardsUseCase httpCommon.CardsUseCase,
+	statsUseCase httpCommon.StatsUseCase,
+	reviewUseCase httpCommon.ReviewUseCase,
+	settingsUseCase httpCommon.SettingsUseCase,
+	sessionsUseCase httpCommon.SessionsUseCase,
+	adminUseCase httpCommon.AdminUseCase,
+	eventsUseCase httpCommon.EventsUseCase,
 	jwtParser middleware.JWTParser,
 	log zerolog.Logger,
 	opts ...Option,
 ) *App {
 	app := &App{
-		healthUseCase:  healthUseCase,
-		authUseCase:    authUseCase,
-		modulesUseCase: modulesUseCase,
-		cardsUseCase:   cardsUseCase,
-		jwtParser:      jwtParser,
-		log:            log,
-		router:         chi.NewRouter(),
+		healthUseCase:   healthUseCase,
+		authUseCase:     authUseCase,
+		modulesUseCase:  modulesUseCase,
+		cardsUseCase:    cardsUseCase,
+		statsUseCase:    statsUseCase,
+		reviewUseCase:   reviewUseCase,
+		settingsUseCase: settingsUseCase,
+		sessionsUseCase: sessionsUseCase,
+		adminUseCase:    adminUseCase,
+		eventsUseCase:   eventsUseCase,
+		jwtParser:       jwtParser,
+		log:             log,
+		router:          chi.NewRouter(),
 	}
 
 	for _, opt := range opts {
@@ -81,7 +113,13 @@ func (app *App) setupRoutes() {
 	healthRoutes := health.NewRoutes(app.healthUseCase, app.log)
 	authRoutes := auth.NewRoutes(app.authUseCase, app.log)
 	modulesRoutes := modules.NewRoutes(app.modulesUseCase, app.log)
-	cardsRoutes := cards.NewRoutes(app.modulesUseCase, app.cardsUseCase, app.log)
+	cardsRoutes := cards.NewRoutes(app.modulesUseCase, app.cardsUseCase, app.statsUseCase, app.log)
+	statsRoutes := stats.NewRoutes(app.modulesUseCase, app.statsUseCase, app.log)
+	reviewRoutes := review.NewRoutes(app.modulesUseCase, app.reviewUseCase, app.log)
+	settingsRoutes := settings.NewRoutes(app.settingsUseCase, app.log)
+	sessionsRoutes := sessions.NewRoutes(app.modulesUseCase, app.sessionsUseCase, app.log)
+	adminRoutes := admin.NewRoutes(app.adminUseCase, app.log)
+	eventsRoutes := events.NewRoutes(app.eventsUseCase, app.log)
 
 	app.router.Use(middleware.LoggerMiddleware(app.log))
 	healthRoutes.Apply(app.router)
@@ -92,6 +130,17 @@ func (app *App) setupRoutes() {
 
 		modulesRoutes.Apply(r)
 		cardsRoutes.Apply(r)
+		statsRoutes.Apply(r)
+		reviewRoutes.Apply(r)
+		settingsRoutes.Apply(r)
+		sessionsRoutes.Apply(r)
+		eventsRoutes.Apply(r)
+	})
+
+	app.router.Group(func(r chi.Router) {
+		r.Use(middleware.NewAdminMiddleware(app.adminToken, app.log))
+
+		adminRoutes.Apply(r)
 	})
 
 	app.router.Get("/swagger/*", httpSwagger.Handler())
@@ -106,6 +155,10 @@ func (app *App) setupRoutes() {
 // @securityDefinitions.apikey UsersAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey AdminAuth
+// @in header
+// @name X-Admin-Token
 func (app *App) Run() {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
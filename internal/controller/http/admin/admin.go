@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	httpCommon "github.com/llravell/simple-cards/internal/controller/http"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/rs/zerolog"
+)
+
+type Routes struct {
+	log     zerolog.Logger
+	adminUC httpCommon.AdminUseCase
+}
+
+func NewRoutes(adminUC httpCommon.AdminUseCase, log zerolog.Logger) *Routes {
+	return &Routes{
+		log:     log,
+		adminUC: adminUC,
+	}
+}
+
+func (routes *Routes) jsonResponse(w http.ResponseWriter, v any) {
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		routes.log.Err(err).Msg("response write has been failed")
+	}
+}
+
+// Swagger spec:
+// @Summary      Get dead queue jobs
+// @Description  Jobs which have run out of attempts or failed with an error which is not worth retrying
+// @Security     AdminAuth
+// @Tags         admin
+// @Produce      json
+// @Param        queue query string false "Queue name, e.g. quizlet_import or csv_import"
+// @Success      200  {array}  entity.QueueJob
+// @Failure      403
+// @Failure      500
+// @Router       /api/admin/queue/dead [get]
+func (routes *Routes) getDeadJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := routes.adminUC.GetDeadJobs(r.Context(), r.URL.Query().Get("queue"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("dead queue jobs fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, jobs)
+}
+
+// Swagger spec:
+// @Summary      Requeue dead queue job
+// @Description  The job gets a fresh set of attempts and is done as soon as a worker is free,
+// @Description  the failed import or export job of the work is reopened
+// @Security     AdminAuth
+// @Tags         admin
+// @Produce      json
+// @Param        job_id path int true "Queue job ID"
+// @Success      200  {object}  entity.QueueJob
+// @Failure      400
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /api/admin/queue/dead/{job_id}/requeue [post]
+func (routes *Routes) requeueDeadJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := strconv.ParseInt(r.PathValue("job_id"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	job, err := routes.adminUC.RequeueDeadJob(r.Context(), jobID)
+	if err != nil {
+		var notFoundErr *entity.QueueJobNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("dead queue job requeue failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, job)
+}
+
+func (routes *Routes) Apply(r chi.Router) {
+	r.Route("/api/admin/queue/dead", func(r chi.Router) {
+		r.Get("/", routes.getDeadJobs)
+		r.Post("/{job_id}/requeue", routes.requeueDeadJob)
+	})
+}
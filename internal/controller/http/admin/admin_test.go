@@ -0,0 +1,161 @@
+package admin_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	testutils "github.com/llravell/simple-cards/internal"
+	"github.com/llravell/simple-cards/internal/controller/http/admin"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/mocks"
+	"github.com/llravell/simple-cards/internal/usecase"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testCase struct {
+	name         string
+	mock         func()
+	path         string
+	expectedCode int
+	expectedBody string
+}
+
+var testRunAt = time.Date(2024, time.December, 26, 10, 0, 0, 0, time.UTC)
+
+func prepareTestServer(t *testing.T, queueJobsRepo usecase.QueueJobsRepository) *httptest.Server {
+	t.Helper()
+
+	adminUseCase := usecase.NewAdminUseCase(queueJobsRepo)
+	router := chi.NewRouter()
+	routes := admin.NewRoutes(adminUseCase, zerolog.Nop())
+
+	routes.Apply(router)
+
+	return httptest.NewServer(router)
+}
+
+func TestGetDeadJobs(t *testing.T) {
+	queueJobsRepo := mocks.NewMockQueueJobsRepository(gomock.NewController(t))
+	ts := prepareTestServer(t, queueJobsRepo)
+
+	defer ts.Close()
+
+	deadJobs := []*entity.QueueJob{
+		{
+			ID:        1,
+			Queue:     "quizlet_import",
+			Status:    entity.QueueJobStatusDead,
+			Attempts:  5,
+			LastError: "module \"123\" fetching failed",
+			RunAt:     testRunAt,
+			CreatedAt: testRunAt,
+			UpdatedAt: testRunAt,
+		},
+	}
+
+	testCases := []testCase{
+		{
+			name: "repo error",
+			mock: func() {
+				queueJobsRepo.EXPECT().
+					GetDeadJobs(gomock.Any(), "").
+					Return(nil, errors.New("boom"))
+			},
+			path:         "/api/admin/queue/dead",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "dead jobs of the queue",
+			mock: func() {
+				queueJobsRepo.EXPECT().
+					GetDeadJobs(gomock.Any(), "quizlet_import").
+					Return(deadJobs, nil)
+			},
+			path:         "/api/admin/queue/dead?queue=quizlet_import",
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, deadJobs),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, tc.path, http.NoBody, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRequeueDeadJob(t *testing.T) {
+	queueJobsRepo := mocks.NewMockQueueJobsRepository(gomock.NewController(t))
+	ts := prepareTestServer(t, queueJobsRepo)
+
+	defer ts.Close()
+
+	requeuedJob := &entity.QueueJob{
+		ID:        1,
+		Queue:     "csv_import",
+		Status:    entity.QueueJobStatusQueued,
+		RunAt:     testRunAt,
+		CreatedAt: testRunAt,
+		UpdatedAt: testRunAt,
+	}
+
+	testCases := []testCase{
+		{
+			name:         "invalid job id",
+			mock:         func() {},
+			path:         "/api/admin/queue/dead/abc/requeue",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "job not found",
+			mock: func() {
+				queueJobsRepo.EXPECT().
+					RequeueDeadJob(gomock.Any(), int64(2)).
+					Return(nil, &entity.QueueJobNotFoundError{ID: 2})
+			},
+			path:         "/api/admin/queue/dead/2/requeue",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "job requeued successfully",
+			mock: func() {
+				queueJobsRepo.EXPECT().
+					RequeueDeadJob(gomock.Any(), int64(1)).
+					Return(requeuedJob, nil)
+			},
+			path:         "/api/admin/queue/dead/1/requeue",
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, requeuedJob),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodPost, tc.path, http.NoBody, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
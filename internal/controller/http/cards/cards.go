@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -15,22 +16,27 @@ import (
 	"github.com/rs/zerolog"
 )
 
+const leechesFilter = "leeches"
+
 type Routes struct {
 	log       zerolog.Logger
 	modulesUC httpCommon.ModulesUseCase
 	cardsUC   httpCommon.CardsUseCase
+	statsUC   httpCommon.StatsUseCase
 	validator *validator.Validate
 }
 
 func NewRoutes(
 	modulesUC httpCommon.ModulesUseCase,
 	cardsUC httpCommon.CardsUseCase,
+	statsUC httpCommon.StatsUseCase,
 	log zerolog.Logger,
 ) *Routes {
 	return &Routes{
 		log:       log,
 		modulesUC: modulesUC,
 		cardsUC:   cardsUC,
+		statsUC:   statsUC,
 		validator: validator.New(),
 	}
 }
@@ -68,16 +74,30 @@ func (routes *Routes) jsonResponse(w http.ResponseWriter, v any) {
 
 // Swagger spec:
 // @Summary      Get all module's cards
+// @Description  Leeches filter returns cards which the user keeps failing with their review states
 // @Security     UsersAuth
 // @Tags         cards
 // @Produce      json
 // @Param        module_uuid path string true "Module UUID"
+// @Param        filter query string false "Cards filter" Enums(leeches)
 // @Success      200  {array}  entity.Card
 // @Failure      400
 // @Failure      404
 // @Failure      500
 // @Router       /api/modules/{module_uuid}/cards/ [get]
 func (routes *Routes) getCards(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Query().Get("filter") {
+	case "":
+	case leechesFilter:
+		routes.getModuleLeeches(w, r)
+
+		return
+	default:
+		http.Error(w, "unknown filter", http.StatusBadRequest)
+
+		return
+	}
+
 	cards, err := routes.cardsUC.GetModuleCards(r.Context(), r.PathValue("module_uuid"))
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -216,7 +236,197 @@ func (routes *Routes) deleteCard(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 }
 
+func (routes *Routes) getModuleLeeches(w http.ResponseWriter, r *http.Request) {
+	cards, err := routes.cardsUC.GetModuleLeeches(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("module leeches fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, cards)
+}
+
+// Swagger spec:
+// @Summary      Get leeches from all user's modules
+// @Description  Leeches are cards which the user keeps failing, most failed first
+// @Security     UsersAuth
+// @Tags         cards
+// @Produce      json
+// @Success      200  {array}  entity.LeechCard
+// @Failure      500
+// @Router       /api/cards/leeches [get]
+func (routes *Routes) getLeeches(w http.ResponseWriter, r *http.Request) {
+	cards, err := routes.cardsUC.GetLeeches(r.Context(), middleware.GetUserUUIDFromRequest(r))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("leeches fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, cards)
+}
+
+// Swagger spec:
+// @Summary      Suspend card
+// @Description  Suspended card does not appear in due cards until it is resumed or edited.
+// @Description  Only reviewed cards can be suspended.
+// @Security     UsersAuth
+// @Tags         cards
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Param        card_uuid path string true "Card UUID"
+// @Success      200  {object}  entity.ReviewState
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/cards/{card_uuid}/suspend [post]
+func (routes *Routes) suspendCard(w http.ResponseWriter, r *http.Request) {
+	state, err := routes.cardsUC.SuspendCard(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+		r.PathValue("card_uuid"),
+	)
+	if err != nil {
+		routes.suspensionError(w, err)
+
+		return
+	}
+
+	routes.jsonResponse(w, state)
+}
+
+// Swagger spec:
+// @Summary      Resume suspended card
+// @Security     UsersAuth
+// @Tags         cards
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Param        card_uuid path string true "Card UUID"
+// @Success      200  {object}  entity.ReviewState
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/cards/{card_uuid}/suspend [delete]
+func (routes *Routes) resumeCard(w http.ResponseWriter, r *http.Request) {
+	state, err := routes.cardsUC.ResumeCard(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+		r.PathValue("card_uuid"),
+	)
+	if err != nil {
+		routes.suspensionError(w, err)
+
+		return
+	}
+
+	routes.jsonResponse(w, state)
+}
+
+func (routes *Routes) suspensionError(w http.ResponseWriter, err error) {
+	var notFoundErr *entity.CardNotFoundError
+
+	if errors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	routes.log.Error().Err(err).Msg("card suspension changing failed")
+}
+
+// Swagger spec:
+// @Summary      Check typed answer
+// @Description  Answer is compared with the card's meaning by default ignoring letter case, diacritics
+// @Description  and punctuation. Articles are ignored too when the language of the side is given.
+// @Description  Variants of the expected text can be separated with ";", "/" or ", " not followed by a digit.
+// @Description  An answer with a few typos is almost correct. Recorded answer is saved like a sent one.
+// @Security     UsersAuth
+// @Tags         cards
+// @Accept       json
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Param        card_uuid path string true "Card UUID"
+// @Param        request body dto.CheckAnswerRequest true "Answer params"
+// @Success      200  {object}  entity.AnswerCheck
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/cards/{card_uuid}/check [post]
+func (routes *Routes) checkAnswer(w http.ResponseWriter, r *http.Request) {
+	var req dto.CheckAnswerRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	moduleUUID := r.PathValue("module_uuid")
+	cardUUID := r.PathValue("card_uuid")
+
+	check, err := routes.cardsUC.CheckAnswer(r.Context(), moduleUUID, cardUUID, req.Side, req.Answer, req.Language)
+	if err != nil {
+		var notFoundErr *entity.CardNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("answer checking failed")
+
+		return
+	}
+
+	if req.Record {
+		answer := &entity.Answer{
+			CardUUID:       cardUUID,
+			ModuleUUID:     moduleUUID,
+			SessionUUID:    strings.TrimSpace(req.SessionUUID),
+			IsCorrect:      check.Grade >= entity.PassingAnswerGrade,
+			Grade:          check.Grade,
+			ResponseTimeMs: req.ResponseTimeMs,
+			AnsweredAt:     time.Now(),
+		}
+
+		err = routes.statsUC.SaveAnswers(r.Context(), middleware.GetUserUUIDFromRequest(r), []*entity.Answer{answer})
+		if err != nil {
+			var notFoundErr *entity.SessionNotFoundError
+
+			if errors.As(err, &notFoundErr) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+
+			routes.log.Error().Err(err).Msg("checked answer recording failed")
+
+			return
+		}
+
+		check.Answer = answer
+	}
+
+	routes.jsonResponse(w, check)
+}
+
 func (routes *Routes) Apply(r chi.Router) {
+	r.Get("/api/cards/leeches", routes.getLeeches)
+
 	r.Route("/api/modules/{module_uuid}/cards", func(r chi.Router) {
 		r.Use(routes.checkModuleMiddleware)
 
@@ -226,6 +436,9 @@ func (routes *Routes) Apply(r chi.Router) {
 		r.Route("/{card_uuid}", func(r chi.Router) {
 			r.Put("/", routes.updateCard)
 			r.Delete("/", routes.deleteCard)
+			r.Post("/suspend", routes.suspendCard)
+			r.Delete("/suspend", routes.resumeCard)
+			r.Post("/check", routes.checkAnswer)
 		})
 	})
 }
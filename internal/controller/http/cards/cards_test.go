@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	testutils "github.com/llravell/simple-cards/internal"
@@ -34,6 +35,20 @@ var testCard = entity.Card{
 	ModuleUUID: "module-uuid",
 }
 
+var testLeechCard = entity.LeechCard{
+	Card: testCard,
+	ReviewState: &entity.ReviewState{
+		CardUUID:       "card-uuid",
+		ModuleUUID:     "module-uuid",
+		EaseFactor:     1.3,
+		IntervalDays:   1,
+		Lapses:         8,
+		DueAt:          time.Date(2024, time.December, 22, 10, 0, 0, 0, time.UTC),
+		LastReviewedAt: time.Date(2024, time.December, 21, 10, 0, 0, 0, time.UTC),
+		IsLeech:        true,
+	},
+}
+
 func prepareTestServer(
 	t *testing.T,
 	modulesRepo usecase.ModulesRepository,
@@ -42,21 +57,57 @@ func prepareTestServer(
 ) *httptest.Server {
 	t.Helper()
 
+	return prepareTestServerWithStats(
+		t,
+		modulesRepo,
+		cardsRepo,
+		quizletModuleParser,
+		mocks.NewMockStatsRepository(gomock.NewController(t)),
+		mocks.NewMockReviewRepository(gomock.NewController(t)),
+		mocks.NewMockSettingsRepository(gomock.NewController(t)),
+	)
+}
+
+func prepareTestServerWithStats(
+	t *testing.T,
+	modulesRepo usecase.ModulesRepository,
+	cardsRepo usecase.CardsRepository,
+	quizletModuleParser usecase.QuizletModuleParser,
+	statsRepo usecase.StatsRepository,
+	reviewRepo usecase.ReviewRepository,
+	settingsRepo usecase.SettingsRepository,
+) *httptest.Server {
+	t.Helper()
+
 	log := zerolog.Nop()
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
 	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
 	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
 
 	modulesUseCase := usecase.NewModulesUseCase(
 		modulesRepo,
 		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
 		quizletModuleParser,
 		quizletImportWP,
 		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+		usecase.NewRunningImports(),
+		usecase.NewJobEvents(mocks.NewMockJobEventsNotifier(gomock.NewController(t)), &log),
 		&log,
 	)
 	cardsUseCase := usecase.NewCardsUseCase(cardsRepo)
+	statsUseCase := usecase.NewStatsUseCase(statsRepo, reviewRepo, settingsRepo)
 	router := chi.NewRouter()
-	routes := cards.NewRoutes(modulesUseCase, cardsUseCase, log)
+	routes := cards.NewRoutes(modulesUseCase, cardsUseCase, statsUseCase, log)
 
 	routes.Apply(router)
 
@@ -461,3 +512,470 @@ func TestDeleteCard(t *testing.T) {
 		})
 	}
 }
+
+//nolint:funlen
+func TestGetLeeches(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, cardsRepo, quizletModuleParser)
+
+	defer ts.Close()
+
+	testCases := []struct {
+		testCase
+		path string
+	}{
+		{
+			testCase: testCase{
+				name: "unknown filter",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+				},
+				expectedCode: http.StatusBadRequest,
+			},
+			path: "/api/modules/module-uuid/cards?filter=easy",
+		},
+		{
+			testCase: testCase{
+				name: "module leeches fetching error",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					cardsRepo.EXPECT().
+						GetLeeches(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(nil, errors.New("boom"))
+				},
+				expectedCode: http.StatusInternalServerError,
+			},
+			path: "/api/modules/module-uuid/cards?filter=leeches",
+		},
+		{
+			testCase: testCase{
+				name: "module leeches returned successfully",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					cardsRepo.EXPECT().
+						GetLeeches(gomock.Any(), gomock.Any(), "module-uuid").
+						Return([]*entity.LeechCard{&testLeechCard}, nil)
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: testutils.ToJSON(t, []entity.LeechCard{testLeechCard}),
+			},
+			path: "/api/modules/module-uuid/cards?filter=leeches",
+		},
+		{
+			testCase: testCase{
+				name: "leeches of all modules returned successfully",
+				mock: func() {
+					cardsRepo.EXPECT().
+						GetLeeches(gomock.Any(), gomock.Any(), "").
+						Return([]*entity.LeechCard{&testLeechCard}, nil)
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: testutils.ToJSON(t, []entity.LeechCard{testLeechCard}),
+			},
+			path: "/api/cards/leeches",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, tc.path, tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestSuspendCard(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, cardsRepo, quizletModuleParser)
+
+	defer ts.Close()
+
+	suspendedState := *testLeechCard.ReviewState
+	suspendedState.IsSuspended = true
+
+	testCases := []struct {
+		testCase
+		method string
+	}{
+		{
+			testCase: testCase{
+				name: "card has never been reviewed",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					cardsRepo.EXPECT().
+						SetCardSuspended(gomock.Any(), gomock.Any(), "module-uuid", "card-uuid", true).
+						Return(nil, &entity.CardNotFoundError{UUID: "card-uuid"})
+				},
+				expectedCode: http.StatusNotFound,
+			},
+			method: http.MethodPost,
+		},
+		{
+			testCase: testCase{
+				name: "card suspended successfully",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					cardsRepo.EXPECT().
+						SetCardSuspended(gomock.Any(), gomock.Any(), "module-uuid", "card-uuid", true).
+						Return(&suspendedState, nil)
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: testutils.ToJSON(t, suspendedState),
+			},
+			method: http.MethodPost,
+		},
+		{
+			testCase: testCase{
+				name: "card resuming error",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					cardsRepo.EXPECT().
+						SetCardSuspended(gomock.Any(), gomock.Any(), "module-uuid", "card-uuid", false).
+						Return(nil, errors.New("boom"))
+				},
+				expectedCode: http.StatusInternalServerError,
+			},
+			method: http.MethodDelete,
+		},
+		{
+			testCase: testCase{
+				name: "card resumed successfully",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					cardsRepo.EXPECT().
+						SetCardSuspended(gomock.Any(), gomock.Any(), "module-uuid", "card-uuid", false).
+						Return(testLeechCard.ReviewState, nil)
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: testutils.ToJSON(t, testLeechCard.ReviewState),
+			},
+			method: http.MethodDelete,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, tc.method,
+				"/api/modules/module-uuid/cards/card-uuid/suspend", tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen,maintidx
+func TestCheckAnswer(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	statsRepo := mocks.NewMockStatsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServerWithStats(
+		t,
+		modulesRepo,
+		cardsRepo,
+		quizletModuleParser,
+		statsRepo,
+		reviewRepo,
+		settingsRepo,
+	)
+
+	defer ts.Close()
+
+	frenchCard := entity.Card{
+		UUID:       "card-uuid",
+		Term:       "l'éléphant",
+		Meaning:    "слон; элефант",
+		ModuleUUID: "module-uuid",
+	}
+
+	expectCard := func(card entity.Card) {
+		modulesRepo.EXPECT().
+			ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+			Return(true, nil)
+
+		cardsRepo.EXPECT().
+			GetCard(gomock.Any(), "module-uuid", "card-uuid").
+			Return(&card, nil)
+	}
+
+	testCases := []testCase{
+		{
+			name: "unexpected side",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(true, nil)
+			},
+			body:         strings.NewReader(`{"answer":"слон","side":"back"}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "card not found",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(true, nil)
+
+				cardsRepo.EXPECT().
+					GetCard(gomock.Any(), "module-uuid", "card-uuid").
+					Return(nil, &entity.CardNotFoundError{UUID: "card-uuid"})
+			},
+			body:         strings.NewReader(`{"answer":"слон"}`),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "alternative of meaning is correct",
+			mock:         func() { expectCard(frenchCard) },
+			body:         strings.NewReader(`{"answer":"  Элефант! "}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictCorrect,
+				Grade:    entity.DefaultCorrectAnswerGrade,
+				Expected: "слон; элефант",
+			}),
+		},
+		{
+			name:         "article and diacritics are ignored",
+			mock:         func() { expectCard(frenchCard) },
+			body:         strings.NewReader(`{"answer":"Elephant","side":"term","language":"fr"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictCorrect,
+				Grade:    entity.DefaultCorrectAnswerGrade,
+				Expected: "l'éléphant",
+			}),
+		},
+		{
+			name: "unknown language",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(true, nil)
+			},
+			body:         strings.NewReader(`{"answer":"Elephant","side":"term","language":"xx"}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "article isn't ignored without language",
+			mock:         func() { expectCard(frenchCard) },
+			body:         strings.NewReader(`{"answer":"Elephant","side":"term"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictAlmostCorrect,
+				Grade:    entity.AlmostCorrectAnswerGrade,
+				Expected: "l'éléphant",
+				Distance: 2,
+			}),
+		},
+		{
+			name: "article of another language isn't ignored",
+			mock: func() {
+				expectCard(entity.Card{
+					UUID:       "card-uuid",
+					Term:       "die",
+					Meaning:    "умирать",
+					ModuleUUID: "module-uuid",
+				})
+			},
+			body:         strings.NewReader(`{"answer":"the die","side":"term","language":"de"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictIncorrect,
+				Grade:    entity.DefaultIncorrectAnswerGrade,
+				Expected: "die",
+				Distance: 4,
+			}),
+		},
+		{
+			name: "comma in number doesn't separate variants",
+			mock: func() {
+				expectCard(entity.Card{
+					UUID:       "card-uuid",
+					Term:       "thousand",
+					Meaning:    "1,000; 1, 5",
+					ModuleUUID: "module-uuid",
+				})
+			},
+			body:         strings.NewReader(`{"answer":"1"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictIncorrect,
+				Grade:    entity.DefaultIncorrectAnswerGrade,
+				Expected: "1,000; 1, 5",
+				Distance: 2,
+			}),
+		},
+		{
+			name: "variants are separated with slash and comma",
+			mock: func() {
+				expectCard(entity.Card{
+					UUID:       "card-uuid",
+					Term:       "cat",
+					Meaning:    "кот/кошка, котёнок",
+					ModuleUUID: "module-uuid",
+				})
+			},
+			body:         strings.NewReader(`{"answer":"кошка"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictCorrect,
+				Grade:    entity.DefaultCorrectAnswerGrade,
+				Expected: "кот/кошка, котёнок",
+			}),
+		},
+		{
+			name: "yo and ye are equal",
+			mock: func() {
+				expectCard(entity.Card{
+					UUID:       "card-uuid",
+					Term:       "hedgehog",
+					Meaning:    "ёжик",
+					ModuleUUID: "module-uuid",
+				})
+			},
+			body:         strings.NewReader(`{"answer":"ежик"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictCorrect,
+				Grade:    entity.DefaultCorrectAnswerGrade,
+				Expected: "ёжик",
+			}),
+		},
+		{
+			name:         "answer with a typo is almost correct",
+			mock:         func() { expectCard(frenchCard) },
+			body:         strings.NewReader(`{"answer":"elefant","side":"term","language":"fr"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictAlmostCorrect,
+				Grade:    entity.AlmostCorrectAnswerGrade,
+				Expected: "l'éléphant",
+				Distance: 2,
+			}),
+		},
+		{
+			name:         "short answer with a typo is almost correct",
+			mock:         func() { expectCard(frenchCard) },
+			body:         strings.NewReader(`{"answer":"слан"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictAlmostCorrect,
+				Grade:    entity.AlmostCorrectAnswerGrade,
+				Expected: "слон; элефант",
+				Distance: 1,
+			}),
+		},
+		{
+			name:         "wrong answer is incorrect",
+			mock:         func() { expectCard(frenchCard) },
+			body:         strings.NewReader(`{"answer":"кит"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.AnswerCheck{
+				Verdict:  entity.AnswerVerdictIncorrect,
+				Grade:    entity.DefaultIncorrectAnswerGrade,
+				Expected: "слон; элефант",
+				Distance: 4,
+			}),
+		},
+		{
+			name: "checked answer is recorded",
+			mock: func() {
+				expectCard(frenchCard)
+
+				settingsRepo.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Any()).
+					Return(&entity.UserSettings{
+						Scheduler:        entity.DefaultScheduler,
+						DesiredRetention: entity.DefaultDesiredRetention,
+						LeechThreshold:   entity.DefaultLeechThreshold,
+					}, nil)
+
+				settingsRepo.EXPECT().
+					GetModuleSchedulers(gomock.Any(), gomock.Any(), []string{"module-uuid"}).
+					Return(map[string]string{}, nil)
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, _ string, answers []*entity.Answer, _ []*entity.ReviewState) ([]string, error) {
+						assert.Len(t, answers, 1)
+						assert.Equal(t, "session-uuid", answers[0].SessionUUID)
+						assert.Equal(t, entity.DefaultCorrectAnswerGrade, answers[0].Grade)
+						assert.True(t, answers[0].IsCorrect)
+
+						return nil, nil
+					})
+			},
+			body:         strings.NewReader(`{"answer":"слон","record":true,"session_uuid":"session-uuid"}`),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodPost,
+				"/api/modules/module-uuid/cards/card-uuid/check", tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	httpCommon "github.com/llravell/simple-cards/internal/controller/http"
+	"github.com/llravell/simple-cards/internal/controller/http/middleware"
+	"github.com/rs/zerolog"
+)
+
+const keepAliveInterval = 15 * time.Second
+
+type Routes struct {
+	log      zerolog.Logger
+	eventsUC httpCommon.EventsUseCase
+}
+
+func NewRoutes(eventsUC httpCommon.EventsUseCase, log zerolog.Logger) *Routes {
+	return &Routes{
+		log:      log,
+		eventsUC: eventsUC,
+	}
+}
+
+// Swagger spec:
+// @Summary      Stream user's job events
+// @Description  Server-Sent Events stream of import jobs lifecycle: queued, started, progress, completed, failed
+// @Description  and canceled. Each event is named after its type and carries entity.JobEvent as json data.
+// @Description  A comment is sent periodically to keep the connection alive.
+// @Security     UsersAuth
+// @Tags         events
+// @Produce      text/event-stream
+// @Success      200  {object}  entity.JobEvent
+// @Failure      500
+// @Router       /api/events [get]
+func (routes *Routes) streamEvents(w http.ResponseWriter, r *http.Request) {
+	responseController := http.NewResponseController(w)
+
+	// The stream lives longer than the server's write timeout.
+	err := responseController.SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("events stream write deadline resetting failed")
+
+		return
+	}
+
+	events := routes.eventsUC.Subscribe(r.Context(), middleware.GetUserUUIDFromRequest(r))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err = responseController.Flush(); err != nil {
+		routes.log.Error().Err(err).Msg("events stream flushing failed")
+
+		return
+	}
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			var data []byte
+
+			data, err = json.Marshal(event)
+			if err == nil {
+				_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			}
+		}
+
+		if err == nil {
+			err = responseController.Flush()
+		}
+
+		if err != nil {
+			routes.log.Error().Err(err).Msg("events stream writing failed")
+
+			return
+		}
+	}
+}
+
+func (routes *Routes) Apply(r chi.Router) {
+	r.Get("/api/events", routes.streamEvents)
+}
@@ -0,0 +1,113 @@
+package events_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	testutils "github.com/llravell/simple-cards/internal"
+	"github.com/llravell/simple-cards/internal/controller/http/events"
+	"github.com/llravell/simple-cards/internal/controller/http/middleware"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/mocks"
+	"github.com/llravell/simple-cards/internal/usecase"
+	"github.com/llravell/simple-cards/pkg/auth"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func prepareTestServer(t *testing.T, notifier usecase.JobEventsNotifier) *httptest.Server {
+	t.Helper()
+
+	log := zerolog.Nop()
+	eventsUseCase := usecase.NewJobEvents(notifier, &log)
+	router := chi.NewRouter()
+	routes := events.NewRoutes(eventsUseCase, log)
+
+	router.Use(middleware.NewAuthMiddleware(auth.NewJWTManager(testutils.JWTSecretKey), log))
+	routes.Apply(router)
+
+	return httptest.NewServer(router)
+}
+
+func toPayload(t *testing.T, event *entity.JobEvent) []byte {
+	t.Helper()
+
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	return payload
+}
+
+func TestStreamEvents(t *testing.T) {
+	notifier := mocks.NewMockJobEventsNotifier(gomock.NewController(t))
+	ts := prepareTestServer(t, notifier)
+
+	defer ts.Close()
+
+	startedEvent := &entity.JobEvent{
+		Type:     entity.JobEventStarted,
+		JobKind:  entity.JobKindImport,
+		JobUUID:  "job-uuid",
+		UserUUID: testutils.UserUUID,
+	}
+	otherUserEvent := &entity.JobEvent{
+		Type:     entity.JobEventStarted,
+		JobKind:  entity.JobKindImport,
+		JobUUID:  "other-job-uuid",
+		UserUUID: "other-user-uuid",
+	}
+	completedEvent := &entity.JobEvent{
+		Type:       entity.JobEventCompleted,
+		JobKind:    entity.JobKindImport,
+		JobUUID:    "job-uuid",
+		UserUUID:   testutils.UserUUID,
+		Progress:   100,
+		ModuleUUID: "module-uuid",
+	}
+
+	t.Run("unauthorized request", func(t *testing.T) {
+		res, _ := testutils.SendTestRequest(t, ts, http.MethodGet, "/api/events", http.NoBody, map[string]string{})
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("user's events are streamed", func(t *testing.T) {
+		notifications := make(chan []byte, 4)
+		unsubscribed := false
+
+		notifications <- toPayload(t, startedEvent)
+		notifications <- toPayload(t, otherUserEvent)
+		notifications <- []byte("invalid payload")
+		notifications <- toPayload(t, completedEvent)
+		close(notifications)
+
+		notifier.EXPECT().
+			Subscribe().
+			Return(notifications, func() { unsubscribed = true })
+
+		res, body := testutils.SendTestRequest(
+			t, ts, http.MethodGet, "/api/events", http.NoBody, testutils.AuthHeaders(t),
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+		assert.Equal(
+			t,
+			fmt.Sprintf(
+				"event: started\ndata: %s\n\nevent: completed\ndata: %s\n\n",
+				toPayload(t, startedEvent),
+				toPayload(t, completedEvent),
+			),
+			string(body),
+		)
+		assert.True(t, unsubscribed)
+	})
+}
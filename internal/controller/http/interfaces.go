@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/pkg/apkg"
+	"github.com/llravell/simple-cards/pkg/csvcards"
 )
 
 type HealthUseCase interface {
@@ -25,8 +27,59 @@ type ModulesUseCase interface {
 	UpdateModule(ctx context.Context, userUUID string, moduleUUID string, moduleName string) (*entity.Module, error)
 	DeleteModule(ctx context.Context, userUUID string, moduleUUID string) error
 	ModuleExists(ctx context.Context, userUUID string, moduleUUID string) (bool, error)
-	QueueQuizletModuleImport(module *entity.Module, quizletModuleID string) error
-	QueueCSVModuleImport(module *entity.Module, reader io.ReadCloser) error
+	QueueQuizletModuleImport(
+		ctx context.Context,
+		module *entity.Module,
+		strategy string,
+		quizletModuleID string,
+	) (*entity.ImportJob, error)
+	QueueCSVModuleImport(
+		ctx context.Context,
+		module *entity.Module,
+		strategy string,
+		dialect csvcards.Dialect,
+		reader io.ReadCloser,
+	) (*entity.ImportJob, error)
+	ExportModuleToAPKG(
+		ctx context.Context,
+		userUUID string,
+		moduleUUID string,
+		reversed bool,
+	) (*entity.Module, []byte, error)
+	QueueXLSXModuleImport(
+		ctx context.Context,
+		module *entity.Module,
+		strategy string,
+		sheet string,
+		dialect csvcards.Dialect,
+		reader io.ReaderAt,
+		size int64,
+	) (*entity.ImportJob, error)
+	ExportModuleToXLSX(ctx context.Context, userUUID string, moduleUUID string) (*entity.Module, []byte, error)
+	ExportModuleToArchive(ctx context.Context, userUUID string, moduleUUID string) (*entity.ModuleArchive, error)
+	ImportModuleFromArchive(ctx context.Context, userUUID string, archive []byte) (*entity.Module, error)
+	QueueAPKGModuleImport(
+		ctx context.Context,
+		userUUID string,
+		packageName string,
+		mapping apkg.FieldMapping,
+		reader io.ReaderAt,
+		size int64,
+	) (*entity.ImportJob, error)
+	PreviewCSVImport(dialect csvcards.Dialect, reader io.Reader, limit int) (*entity.ImportPreview, error)
+	PreviewQuizletImport(ctx context.Context, quizletModuleID string, limit int) (*entity.ImportPreview, error)
+	GetImportJobs(ctx context.Context, userUUID string) ([]*entity.ImportJob, error)
+	GetImportJob(ctx context.Context, userUUID string, jobUUID string) (*entity.ImportJob, error)
+	CancelImportJob(ctx context.Context, userUUID string, jobUUID string) (*entity.ImportJob, error)
+	GetImportJobErrors(ctx context.Context, userUUID string, jobUUID string) ([]*entity.ImportRowError, error)
+	QueueLibraryExport(
+		ctx context.Context,
+		userUUID string,
+		format string,
+		moduleUUIDs []string,
+	) (*entity.ExportJob, error)
+	GetExportJob(ctx context.Context, userUUID string, jobUUID string) (*entity.ExportJob, error)
+	GetExportArchive(ctx context.Context, userUUID string, jobUUID string) (*entity.ExportJob, []byte, error)
 }
 
 type CardsUseCase interface {
@@ -34,4 +87,68 @@ type CardsUseCase interface {
 	CreateCard(ctx context.Context, card *entity.Card) (*entity.Card, error)
 	SaveCard(ctx context.Context, card *entity.Card) (*entity.Card, error)
 	DeleteCard(ctx context.Context, moduleUUID string, cardUUID string) error
+	GetLeeches(ctx context.Context, userUUID string) ([]*entity.LeechCard, error)
+	GetModuleLeeches(ctx context.Context, userUUID string, moduleUUID string) ([]*entity.LeechCard, error)
+	SuspendCard(ctx context.Context, userUUID string, moduleUUID string, cardUUID string) (*entity.ReviewState, error)
+	ResumeCard(ctx context.Context, userUUID string, moduleUUID string, cardUUID string) (*entity.ReviewState, error)
+	CheckAnswer(
+		ctx context.Context,
+		moduleUUID string,
+		cardUUID string,
+		side string,
+		answer string,
+		language string,
+	) (*entity.AnswerCheck, error)
+}
+
+type StatsUseCase interface {
+	SaveAnswers(ctx context.Context, userUUID string, answers []*entity.Answer) error
+	SyncAnswers(ctx context.Context, userUUID string, answers []*entity.Answer) (*entity.SyncResult, error)
+	GetModuleStats(
+		ctx context.Context,
+		userUUID string,
+		moduleUUID string,
+		from time.Time,
+		to time.Time,
+	) ([]*entity.CardStats, error)
+	GetActivity(
+		ctx context.Context,
+		userUUID string,
+		timezone string,
+		from time.Time,
+		to time.Time,
+	) ([]*entity.DailyActivity, error)
+	GetStreak(ctx context.Context, userUUID string) (*entity.StudyStreak, error)
+}
+
+type ReviewUseCase interface {
+	GetDueCards(ctx context.Context, userUUID string, limit int) ([]*entity.DueCard, error)
+	GetModuleDueCards(ctx context.Context, userUUID string, moduleUUID string, limit int) ([]*entity.DueCard, error)
+}
+
+type SettingsUseCase interface {
+	GetUserSettings(ctx context.Context, userUUID string) (*entity.UserSettings, error)
+	SaveUserSettings(ctx context.Context, userUUID string, settings *entity.UserSettings) (*entity.UserSettings, error)
+	GetModuleSettings(ctx context.Context, userUUID string, moduleUUID string) (*entity.ModuleSettings, error)
+	SaveModuleSettings(
+		ctx context.Context,
+		userUUID string,
+		moduleUUID string,
+		settings *entity.ModuleSettings,
+	) (*entity.ModuleSettings, error)
+}
+
+type SessionsUseCase interface {
+	StartSession(ctx context.Context, userUUID string, moduleUUID string) (*entity.StudySession, error)
+	FinishSession(ctx context.Context, userUUID string, moduleUUID string, sessionUUID string) (*entity.StudySession, error)
+	GetModuleSessions(ctx context.Context, userUUID string, moduleUUID string) ([]*entity.StudySession, error)
+}
+
+type AdminUseCase interface {
+	GetDeadJobs(ctx context.Context, queue string) ([]*entity.QueueJob, error)
+	RequeueDeadJob(ctx context.Context, id int64) (*entity.QueueJob, error)
+}
+
+type EventsUseCase interface {
+	Subscribe(ctx context.Context, userUUID string) <-chan *entity.JobEvent
 }
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+const adminTokenHeader = "X-Admin-Token"
+
+type adminAuthenticator struct {
+	token string
+	log   zerolog.Logger
+}
+
+func (auth *adminAuthenticator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(adminTokenHeader)
+
+		if auth.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(auth.token)) != 1 {
+			auth.log.Error().Msg("admin token mismatch")
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NewAdminMiddleware allows requests with the admin token only,
+// all requests are forbidden when the token isn't configured.
+func NewAdminMiddleware(token string, log zerolog.Logger) func(next http.Handler) http.Handler {
+	auth := &adminAuthenticator{
+		token: token,
+		log:   log,
+	}
+
+	return auth.Handler
+}
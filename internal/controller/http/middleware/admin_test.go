@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	testutils "github.com/llravell/simple-cards/internal"
+	"github.com/llravell/simple-cards/internal/controller/http/middleware"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	prepareServer := func(token string) *httptest.Server {
+		router := chi.NewRouter()
+
+		router.Use(middleware.NewAdminMiddleware(token, zerolog.Nop()))
+		router.Post("/", echoHandler(t))
+
+		return httptest.NewServer(router)
+	}
+
+	t.Run("Middleware return forbidden status code if token does not exist", func(t *testing.T) {
+		ts := prepareServer("secret")
+		defer ts.Close()
+
+		res, _ := testutils.SendTestRequest(t, ts, http.MethodPost, "/", http.NoBody, map[string]string{})
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("Middleware return forbidden status code if token is wrong", func(t *testing.T) {
+		ts := prepareServer("secret")
+		defer ts.Close()
+
+		res, _ := testutils.SendTestRequest(
+			t, ts, http.MethodPost, "/", http.NoBody, map[string]string{"X-Admin-Token": "wrong"},
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("Middleware return forbidden status code if admin token is not configured", func(t *testing.T) {
+		ts := prepareServer("")
+		defer ts.Close()
+
+		res, _ := testutils.SendTestRequest(
+			t, ts, http.MethodPost, "/", http.NoBody, map[string]string{"X-Admin-Token": ""},
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("Middleware call original handler if token matches", func(t *testing.T) {
+		ts := prepareServer("secret")
+		defer ts.Close()
+
+		res, _ := testutils.SendTestRequest(
+			t, ts, http.MethodPost, "/", http.NoBody, map[string]string{"X-Admin-Token": "secret"},
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
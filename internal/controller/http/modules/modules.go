@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -15,10 +19,23 @@ import (
 	"github.com/llravell/simple-cards/internal/controller/http/middleware"
 	"github.com/llravell/simple-cards/internal/entity"
 	"github.com/llravell/simple-cards/internal/entity/dto"
+	"github.com/llravell/simple-cards/pkg/apkg"
+	"github.com/llravell/simple-cards/pkg/csvcards"
+	"github.com/llravell/simple-cards/pkg/quizlet"
+	"github.com/llravell/simple-cards/pkg/xlsx"
 	"github.com/rs/zerolog"
 )
 
-const maxCSVImportFileSize = 1 << 20
+const (
+	maxCSVImportFileSize  = 1 << 20
+	maxAPKGImportFileSize = 64 << 20
+	maxArchiveImportSize  = 64 << 20
+	maxXLSXImportFileSize = 16 << 20
+	maxMultipartMemory    = 1 << 20
+
+	defaultImportPreviewLimit = 20
+	maxImportPreviewLimit     = 100
+)
 
 type Routes struct {
 	log       zerolog.Logger
@@ -43,6 +60,7 @@ func (routes *Routes) jsonResponse(w http.ResponseWriter, v any) {
 
 // Swagger spec:
 // @Summary      Get all user's modules
+// @Description  Each module contains user's learning progress
 // @Security     UsersAuth
 // @Tags         modules
 // @Produce      json
@@ -109,12 +127,16 @@ func (routes *Routes) createModule(w http.ResponseWriter, r *http.Request) {
 
 // Swagger spec:
 // @Summary      Import module from quizlet public module
+// @Description  Cards are merged into the existing module with the strategy when module_uuid is passed
 // @Security     UsersAuth
 // @Tags         modules
 // @Accept       json
+// @Produce      json
 // @Param        request body dto.QuizletImportRequest true "Import module params"
-// @Success      200
+// @Success      202  {object}  entity.ImportJob
+// @Header       202  {string}  Location  "Import job URL"
 // @Failure      400
+// @Failure      404
 // @Failure      500
 // @Router       /api/modules/import/quizlet [post]
 func (routes *Routes) importModuleFromQuizlet(w http.ResponseWriter, r *http.Request) {
@@ -136,17 +158,20 @@ func (routes *Routes) importModuleFromQuizlet(w http.ResponseWriter, r *http.Req
 	}
 
 	module := &entity.Module{
+		UUID:     req.ModuleUUID,
 		UserUUID: middleware.GetUserUUIDFromRequest(r),
 		Name:     req.ModuleName,
 	}
 
-	err := routes.modulesUC.QueueQuizletModuleImport(module, req.QuizletModuleID)
+	job, err := routes.modulesUC.QueueQuizletModuleImport(r.Context(), module, req.Strategy, req.QuizletModuleID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		routes.importQueueFailed(w, err)
 		routes.log.Error().Err(err).Msg("quizlet module import queue failed")
 
 		return
 	}
+
+	routes.importJobAccepted(w, job)
 }
 
 // Swagger spec:
@@ -154,22 +179,42 @@ func (routes *Routes) importModuleFromQuizlet(w http.ResponseWriter, r *http.Req
 // @Security     UsersAuth
 // @Tags         modules
 // @Accept       mpfd
+// @Produce      json
 // @Param        file  formData  file  true  "CSV file with max size 1 MB"
-// @Success      200
+// @Param        module_uuid  formData  string  false  "Existing module to merge cards into"
+// @Param        strategy  formData  string  false  "Merge strategy" Enums(append, skip-duplicates, update-existing)
+// @Param        delimiter  formData  string  false  "Delimiter, comma by default" Enums(comma, semicolon, tab, auto)
+// @Param        header  formData  string  false  "First row header, none by default" Enums(none, present, auto)
+// @Param        term_column  formData  string  false  "Term column number or header name, 1 by default"
+// @Param        meaning_column  formData  string  false  "Meaning column number or header name, 2 by default"
+// @Param        swap  formData  boolean  false  "Swap terms and meanings"
+// @Param        lazy_quotes  formData  boolean  false  "Allow quotes inside unquoted fields"
+// @Param        encoding  formData  string  false  "Encoding" Enums(utf-8, utf-16, utf-16le, utf-16be, windows-1251, koi8-r, auto)
+// @Param        lenient  formData  boolean  false  "Skip malformed rows instead of failing the import"
+// @Success      202  {object}  entity.ImportJob
+// @Header       202  {string}  Location  "Import job URL"
 // @Failure      400
+// @Failure      404
+// @Failure      413
 // @Failure      500
 // @Router       /api/modules/import/csv [post]
 func (routes *Routes) importModuleFromCSV(w http.ResponseWriter, r *http.Request) {
-	err := r.ParseMultipartForm(maxCSVImportFileSize)
+	r.Body = http.MaxBytesReader(w, r.Body, maxCSVImportFileSize)
+
+	err := r.ParseMultipartForm(maxMultipartMemory)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		routes.log.Error().Err(err).Msg("parse multipart form failed")
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			routes.log.Error().Err(err).Msg("parse multipart form failed")
+		}
 
 		return
 	}
 
-	r.Body = http.MaxBytesReader(w, r.Body, maxCSVImportFileSize)
-
 	file, multipartFileHeader, err := r.FormFile("file")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -178,191 +223,1112 @@ func (routes *Routes) importModuleFromCSV(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	req := csvImportRequest(r)
+
+	if err = routes.validator.Struct(req); err != nil {
+		file.Close()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
 	module := &entity.Module{
+		UUID:     req.ModuleUUID,
 		Name:     strings.TrimSuffix(multipartFileHeader.Filename, filepath.Ext(multipartFileHeader.Filename)),
 		UserUUID: middleware.GetUserUUIDFromRequest(r),
 	}
 
-	err = routes.modulesUC.QueueCSVModuleImport(module, file)
+	job, err := routes.modulesUC.QueueCSVModuleImport(r.Context(), module, req.Strategy, csvDialect(req), file)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		routes.importQueueFailed(w, err)
 		routes.log.Error().Err(err).Msg("csv module import queue failed")
 
 		return
 	}
+
+	routes.importJobAccepted(w, job)
 }
 
 // Swagger spec:
-// @Summary      Update module
+// @Summary      Import modules from anki package
+// @Description  Every deck of the package becomes a new module, media files are skipped.
+// @Description  Packages exported without "Support older Anki versions" option are not supported.
 // @Security     UsersAuth
 // @Tags         modules
-// @Accept       json
+// @Accept       mpfd
 // @Produce      json
-// @Param        module_uuid path string true "Module UUID"
-// @Param        request body dto.CreateOrUpdateModuleRequest true "Module params"
-// @Success      200  {object}  entity.Module
+// @Param        file  formData  file  true  "Anki .apkg file with max size 64 MB"
+// @Param        term_field  formData  string  false  "Term note field number or name, 1 by default"
+// @Param        meaning_field  formData  string  false  "Meaning note field number or name, 2 by default"
+// @Success      202  {object}  entity.ImportJob
+// @Header       202  {string}  Location  "Import job URL"
 // @Failure      400
-// @Failure      404
+// @Failure      413
+// @Failure      422
 // @Failure      500
-// @Router       /api/modules/{module_uuid}/ [put]
-func (routes *Routes) updateModule(w http.ResponseWriter, r *http.Request) {
-	var req dto.CreateOrUpdateModuleRequest
+// @Router       /api/modules/import/apkg [post]
+func (routes *Routes) importModulesFromAPKG(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxAPKGImportFileSize)
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	err := r.ParseMultipartForm(maxMultipartMemory)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			routes.log.Error().Err(err).Msg("parse multipart form failed")
+		}
 
 		return
 	}
 
-	req.Name = strings.TrimSpace(req.Name)
+	file, multipartFileHeader, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("getting form file failed")
 
-	if err := routes.validator.Struct(req); err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	req := dto.APKGImportRequest{
+		TermField:    strings.TrimSpace(r.FormValue("term_field")),
+		MeaningField: strings.TrimSpace(r.FormValue("meaning_field")),
+	}
+
+	if err = routes.validator.Struct(req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 
 		return
 	}
 
-	module, err := routes.modulesUC.UpdateModule(
+	job, err := routes.modulesUC.QueueAPKGModuleImport(
 		r.Context(),
 		middleware.GetUserUUIDFromRequest(r),
-		r.PathValue("module_uuid"),
-		req.Name,
+		strings.TrimSuffix(multipartFileHeader.Filename, filepath.Ext(multipartFileHeader.Filename)),
+		apkg.FieldMapping{TermField: req.TermField, MeaningField: req.MeaningField},
+		file,
+		multipartFileHeader.Size,
 	)
 	if err != nil {
-		var notFoundErr *entity.ModuleNotFoundError
+		if errors.Is(err, apkg.ErrNotPackage) ||
+			errors.Is(err, apkg.ErrUnsupportedPackage) ||
+			errors.Is(err, apkg.ErrCollectionTooLarge) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 
-		if errors.As(err, &notFoundErr) {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
+			return
 		}
 
-		routes.log.Error().Err(err).Msg("module creating failed")
+		routes.importQueueFailed(w, err)
+		routes.log.Error().Err(err).Msg("anki package import queue failed")
 
 		return
 	}
 
-	routes.jsonResponse(w, module)
+	routes.importJobAccepted(w, job)
 }
 
 // Swagger spec:
-// @Summary      Delete module
+// @Summary      Import module from xlsx workbook
+// @Description  Cards are read from rows of the first sheet or of the selected one, numbers and dates are read
+// @Description  as they are stored. Cards are merged into the existing module with the strategy
+// @Description  when module_uuid is passed
 // @Security     UsersAuth
 // @Tags         modules
-// @Param        module_uuid path string true "Module UUID"
-// @Success      202
+// @Accept       mpfd
+// @Produce      json
+// @Param        file  formData  file  true  "Excel .xlsx file with max size 16 MB"
+// @Param        module_uuid  formData  string  false  "Existing module to merge cards into"
+// @Param        strategy  formData  string  false  "Merge strategy" Enums(append, skip-duplicates, update-existing)
+// @Param        sheet  formData  string  false  "Sheet name or number, the first sheet by default"
+// @Param        header  formData  string  false  "First row header, none by default" Enums(none, present, auto)
+// @Param        term_column  formData  string  false  "Term column number or header name, 1 by default"
+// @Param        meaning_column  formData  string  false  "Meaning column number or header name, 2 by default"
+// @Param        swap  formData  boolean  false  "Swap terms and meanings"
+// @Success      202  {object}  entity.ImportJob
+// @Header       202  {string}  Location  "Import job URL"
+// @Failure      400
+// @Failure      404
+// @Failure      413
+// @Failure      422
 // @Failure      500
-// @Router       /api/modules/{module_uuid}/ [delete]
-func (routes *Routes) deleteModule(w http.ResponseWriter, r *http.Request) {
-	err := routes.modulesUC.DeleteModule(
+// @Router       /api/modules/import/xlsx [post]
+func (routes *Routes) importModuleFromXLSX(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxXLSXImportFileSize)
+
+	err := r.ParseMultipartForm(maxMultipartMemory)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			routes.log.Error().Err(err).Msg("parse multipart form failed")
+		}
+
+		return
+	}
+
+	file, multipartFileHeader, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("getting form file failed")
+
+		return
+	}
+
+	defer file.Close()
+
+	req := dto.XLSXImportRequest{
+		ModuleUUID:    strings.TrimSpace(r.FormValue("module_uuid")),
+		Strategy:      strings.TrimSpace(r.FormValue("strategy")),
+		Sheet:         strings.TrimSpace(r.FormValue("sheet")),
+		Header:        strings.TrimSpace(r.FormValue("header")),
+		TermColumn:    strings.TrimSpace(r.FormValue("term_column")),
+		MeaningColumn: strings.TrimSpace(r.FormValue("meaning_column")),
+		Swap:          strings.TrimSpace(r.FormValue("swap")),
+	}
+
+	if err = routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	swap, _ := strconv.ParseBool(req.Swap)
+
+	module := &entity.Module{
+		UUID:     req.ModuleUUID,
+		Name:     strings.TrimSuffix(multipartFileHeader.Filename, filepath.Ext(multipartFileHeader.Filename)),
+		UserUUID: middleware.GetUserUUIDFromRequest(r),
+	}
+
+	job, err := routes.modulesUC.QueueXLSXModuleImport(
 		r.Context(),
-		middleware.GetUserUUIDFromRequest(r),
-		r.PathValue("module_uuid"),
+		module,
+		req.Strategy,
+		req.Sheet,
+		csvcards.Dialect{
+			Header:        req.Header,
+			TermColumn:    req.TermColumn,
+			MeaningColumn: req.MeaningColumn,
+			Swap:          swap,
+		},
+		file,
+		multipartFileHeader.Size,
 	)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		routes.log.Error().Err(err).Msg("module deleting failed")
+		var sheetNotFoundErr *xlsx.SheetNotFoundError
+
+		if errors.Is(err, xlsx.ErrNotWorkbook) ||
+			errors.Is(err, xlsx.ErrWorkbookTooLarge) ||
+			errors.Is(err, xlsx.ErrCorrupted) ||
+			errors.As(err, &sheetNotFoundErr) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		routes.importQueueFailed(w, err)
+		routes.log.Error().Err(err).Msg("xlsx module import queue failed")
 
 		return
 	}
 
-	w.WriteHeader(http.StatusAccepted)
+	routes.importJobAccepted(w, job)
 }
 
 // Swagger spec:
-// @Summary      Get module with cards
+// @Summary      Import module from json archive
+// @Description  The archived module is restored as a new module with its settings, cards and review states.
+// @Description  Archives of older versions are migrated to the latest one.
 // @Security     UsersAuth
 // @Tags         modules
+// @Accept       json
 // @Produce      json
-// @Param        module_uuid path string true "Module UUID"
-// @Success      200  {object}  entity.ModuleWithCards
-// @Failure      404
+// @Param        request body entity.ModuleArchive true "Module archive with max size 64 MB"
+// @Success      201  {object}  entity.Module
+// @Failure      413
+// @Failure      422
 // @Failure      500
-// @Router       /api/modules/{module_uuid}/ [get]
-func (routes *Routes) getModuleWithCards(w http.ResponseWriter, r *http.Request) {
-	moduleWithCards, err := routes.modulesUC.GetModuleWithCards(
+// @Router       /api/modules/import/json [post]
+func (routes *Routes) importModuleFromArchive(w http.ResponseWriter, r *http.Request) {
+	archive, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxArchiveImportSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			routes.log.Error().Err(err).Msg("reading module archive failed")
+		}
+
+		return
+	}
+
+	module, err := routes.modulesUC.ImportModuleFromArchive(
 		r.Context(),
 		middleware.GetUserUUIDFromRequest(r),
-		r.PathValue("module_uuid"),
+		archive,
 	)
 	if err != nil {
-		var notFoundErr *entity.ModuleNotFoundError
+		var archiveErr *entity.ModuleArchiveError
 
-		if errors.As(err, &notFoundErr) {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
+		if errors.As(err, &archiveErr) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
 		}
 
-		routes.log.Error().Err(err).Msg("module searching failed")
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("module importing from archive failed")
 
 		return
 	}
 
-	routes.jsonResponse(w, moduleWithCards)
+	w.WriteHeader(http.StatusCreated)
+
+	routes.jsonResponse(w, module)
+}
+
+func csvImportRequest(r *http.Request) dto.CSVImportRequest {
+	return dto.CSVImportRequest{
+		ModuleUUID:    strings.TrimSpace(r.FormValue("module_uuid")),
+		Strategy:      strings.TrimSpace(r.FormValue("strategy")),
+		Delimiter:     strings.TrimSpace(r.FormValue("delimiter")),
+		Header:        strings.TrimSpace(r.FormValue("header")),
+		TermColumn:    strings.TrimSpace(r.FormValue("term_column")),
+		MeaningColumn: strings.TrimSpace(r.FormValue("meaning_column")),
+		Swap:          strings.TrimSpace(r.FormValue("swap")),
+		LazyQuotes:    strings.TrimSpace(r.FormValue("lazy_quotes")),
+		Encoding:      strings.ToLower(strings.TrimSpace(r.FormValue("encoding"))),
+		Lenient:       strings.TrimSpace(r.FormValue("lenient")),
+	}
+}
+
+// csvDialect converts the validated request, so its flags are parsed without errors.
+func csvDialect(req dto.CSVImportRequest) csvcards.Dialect {
+	swap, _ := strconv.ParseBool(req.Swap)
+	lazyQuotes, _ := strconv.ParseBool(req.LazyQuotes)
+	lenient, _ := strconv.ParseBool(req.Lenient)
+
+	return csvcards.Dialect{
+		Delimiter:     req.Delimiter,
+		Header:        req.Header,
+		TermColumn:    req.TermColumn,
+		MeaningColumn: req.MeaningColumn,
+		Swap:          swap,
+		LazyQuotes:    lazyQuotes,
+		Encoding:      req.Encoding,
+		Lenient:       lenient,
+	}
 }
 
 // Swagger spec:
-// @Summary      Export module to csv file
+// @Summary      Preview import
+// @Description  Parses first cards of a csv file sent as multipart form or of a quizlet module sent as json
+// @Description  and returns them with warnings, nothing is stored. Delimiter, encoding and header of the csv file
+// @Description  are detected unless they are passed, the returned dialect can be sent with the csv import.
 // @Security     UsersAuth
 // @Tags         modules
-// @Param        module_uuid path string true "Module UUID"
-// @Produce      text/csv
-// @Success      200
-// @Failure      404
+// @Accept       mpfd,json
+// @Produce      json
+// @Param        limit query int false "Max cards amount, 20 by default, 100 at most"
+// @Param        file  formData  file  false  "CSV file with max size 1 MB"
+// @Param        delimiter  formData  string  false  "Delimiter, detected by default" Enums(comma, semicolon, tab, auto)
+// @Param        header  formData  string  false  "First row header, detected by default" Enums(none, present, auto)
+// @Param        term_column  formData  string  false  "Term column number or header name, 1 by default"
+// @Param        meaning_column  formData  string  false  "Meaning column number or header name, 2 by default"
+// @Param        swap  formData  boolean  false  "Swap terms and meanings"
+// @Param        lazy_quotes  formData  boolean  false  "Allow quotes inside unquoted fields"
+// @Param        encoding  formData  string  false  "Encoding" Enums(utf-8, utf-16, utf-16le, utf-16be, windows-1251, koi8-r, auto)
+// @Param        lenient  formData  boolean  false  "Skip malformed rows"
+// @Param        request body dto.QuizletImportPreviewRequest false "Quizlet module to preview"
+// @Success      200  {object}  entity.ImportPreview
+// @Failure      400
+// @Failure      413
+// @Failure      422
 // @Failure      500
-// @Router       /api/modules/{module_uuid}/export/csv [get]
-func (routes *Routes) exportModuleToCSV(w http.ResponseWriter, r *http.Request) {
-	moduleWithCards, err := routes.modulesUC.GetModuleWithCards(
-		r.Context(),
-		middleware.GetUserUUIDFromRequest(r),
-		r.PathValue("module_uuid"),
-	)
+// @Router       /api/modules/import/preview [post]
+func (routes *Routes) previewImport(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parsePreviewLimit(r)
+	if !ok {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		routes.previewCSVImport(w, r, limit)
+
+		return
+	}
+
+	var req dto.QuizletImportPreviewRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	req.QuizletModuleID = strings.TrimSpace(req.QuizletModuleID)
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	preview, err := routes.modulesUC.PreviewQuizletImport(r.Context(), req.QuizletModuleID, limit)
 	if err != nil {
-		var notFoundErr *entity.ModuleNotFoundError
+		var (
+			fetchingErr *quizlet.ModuleFetchingError
+			parsingErr  *quizlet.ModuleParsingError
+		)
 
-		if errors.As(err, &notFoundErr) {
-			w.WriteHeader(http.StatusNotFound)
+		if errors.As(err, &fetchingErr) || errors.As(err, &parsingErr) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 
-		routes.log.Error().Err(err).Msg("module searching failed")
+		routes.log.Error().Err(err).Msg("quizlet import preview failed")
 
 		return
 	}
 
-	fileName := fmt.Sprintf("%s.%s", moduleWithCards.Name, "csv")
+	routes.jsonResponse(w, preview)
+}
 
-	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+func (routes *Routes) previewCSVImport(w http.ResponseWriter, r *http.Request, limit int) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCSVImportFileSize)
 
-	csvRecords := make([][]string, 0)
+	err := r.ParseMultipartForm(maxMultipartMemory)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
 
-	for _, card := range moduleWithCards.Cards {
-		record := []string{card.Term, card.Meaning}
-		csvRecords = append(csvRecords, record)
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			routes.log.Error().Err(err).Msg("parse multipart form failed")
+		}
+
+		return
 	}
 
-	csvWritter := csv.NewWriter(w)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 
-	err = csvWritter.WriteAll(csvRecords)
+		return
+	}
+
+	defer file.Close()
+
+	req := csvImportRequest(r)
+
+	if err = routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	preview, err := routes.modulesUC.PreviewCSVImport(csvDialect(req), file, limit)
 	if err != nil {
-		routes.log.Error().Err(err).Msg("csv writing failed")
 		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("csv import preview failed")
 
 		return
 	}
+
+	routes.jsonResponse(w, preview)
 }
 
-func (routes *Routes) Apply(r chi.Router) {
-	r.Route("/api/modules", func(r chi.Router) {
-		r.Get("/", routes.getAllModules)
-		r.Post("/", routes.createModule)
+func parsePreviewLimit(r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultImportPreviewLimit, true
+	}
 
-		r.Route("/import", func(r chi.Router) {
-			r.Post("/quizlet", routes.importModuleFromQuizlet)
-			r.Post("/csv", routes.importModuleFromCSV)
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxImportPreviewLimit {
+		return 0, false
+	}
+
+	return limit, true
+}
+
+func (routes *Routes) importQueueFailed(w http.ResponseWriter, err error) {
+	var notFoundErr *entity.ModuleNotFoundError
+
+	if errors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (routes *Routes) importJobAccepted(w http.ResponseWriter, job *entity.ImportJob) {
+	w.Header().Set("Location", "/api/modules/import/"+job.UUID)
+	w.WriteHeader(http.StatusAccepted)
+
+	routes.jsonResponse(w, job)
+}
+
+// Swagger spec:
+// @Summary      Get user's import jobs
+// @Description  Latest import jobs first with status 'created' | 'processing' | 'processed' | 'failed' | 'canceled'
+// @Security     UsersAuth
+// @Tags         modules
+// @Produce      json
+// @Success      200  {array}  entity.ImportJob
+// @Failure      500
+// @Router       /api/modules/import [get]
+func (routes *Routes) getImportJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := routes.modulesUC.GetImportJobs(r.Context(), middleware.GetUserUUIDFromRequest(r))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("import jobs fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, jobs)
+}
+
+// Swagger spec:
+// @Summary      Get import job
+// @Security     UsersAuth
+// @Tags         modules
+// @Produce      json
+// @Param        job_uuid path string true "Import job UUID"
+// @Success      200  {object}  entity.ImportJob
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/import/{job_uuid} [get]
+func (routes *Routes) getImportJob(w http.ResponseWriter, r *http.Request) {
+	job, err := routes.modulesUC.GetImportJob(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("job_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ImportJobNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("import job fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, job)
+}
+
+// Swagger spec:
+// @Summary      Get import job row errors
+// @Description  Rows skipped by the import with line numbers, reasons and row contents
+// @Security     UsersAuth
+// @Tags         modules
+// @Produce      json
+// @Param        job_uuid path string true "Import job UUID"
+// @Success      200  {array}  entity.ImportRowError
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/import/{job_uuid}/errors [get]
+func (routes *Routes) getImportJobErrors(w http.ResponseWriter, r *http.Request) {
+	rowErrors, ok := routes.importJobErrors(w, r)
+	if !ok {
+		return
+	}
+
+	routes.jsonResponse(w, rowErrors)
+}
+
+// Swagger spec:
+// @Summary      Download import job row errors report
+// @Security     UsersAuth
+// @Tags         modules
+// @Produce      text/csv
+// @Param        job_uuid path string true "Import job UUID"
+// @Success      200
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/import/{job_uuid}/errors/csv [get]
+func (routes *Routes) exportImportJobErrorsToCSV(w http.ResponseWriter, r *http.Request) {
+	rowErrors, ok := routes.importJobErrors(w, r)
+	if !ok {
+		return
+	}
+
+	fileName := fmt.Sprintf("import-%s-errors.csv", r.PathValue("job_uuid"))
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+
+	csvRecords := make([][]string, 0, len(rowErrors)+1)
+	csvRecords = append(csvRecords, []string{"line", "reason", "raw"})
+
+	for _, rowErr := range rowErrors {
+		csvRecords = append(csvRecords, []string{strconv.Itoa(rowErr.Line), rowErr.Reason, rowErr.Raw})
+	}
+
+	err := csv.NewWriter(w).WriteAll(csvRecords)
+	if err != nil {
+		routes.log.Error().Err(err).Msg("csv writing failed")
+	}
+}
+
+func (routes *Routes) importJobErrors(w http.ResponseWriter, r *http.Request) ([]*entity.ImportRowError, bool) {
+	rowErrors, err := routes.modulesUC.GetImportJobErrors(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("job_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ImportJobNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("import job errors fetching failed")
+
+		return nil, false
+	}
+
+	return rowErrors, true
+}
+
+// Swagger spec:
+// @Summary      Cancel import job
+// @Description  Cancels a queued import or interrupts a running one. Finished jobs can't be canceled
+// @Security     UsersAuth
+// @Tags         modules
+// @Produce      json
+// @Param        job_uuid path string true "Import job UUID"
+// @Success      200  {object}  entity.ImportJob
+// @Failure      404
+// @Failure      409
+// @Failure      500
+// @Router       /api/modules/import/{job_uuid} [delete]
+func (routes *Routes) cancelImportJob(w http.ResponseWriter, r *http.Request) {
+	job, err := routes.modulesUC.CancelImportJob(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("job_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ImportJobNotFoundError
+
+		switch {
+		case errors.As(err, &notFoundErr):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, entity.ErrImportJobFinished):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("import job canceling failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, job)
+}
+
+// Swagger spec:
+// @Summary      Update module
+// @Security     UsersAuth
+// @Tags         modules
+// @Accept       json
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Param        request body dto.CreateOrUpdateModuleRequest true "Module params"
+// @Success      200  {object}  entity.Module
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/ [put]
+func (routes *Routes) updateModule(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateOrUpdateModuleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	module, err := routes.modulesUC.UpdateModule(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+		req.Name,
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module creating failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, module)
+}
+
+// Swagger spec:
+// @Summary      Delete module
+// @Security     UsersAuth
+// @Tags         modules
+// @Param        module_uuid path string true "Module UUID"
+// @Success      202
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/ [delete]
+func (routes *Routes) deleteModule(w http.ResponseWriter, r *http.Request) {
+	err := routes.modulesUC.DeleteModule(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("module deleting failed")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Swagger spec:
+// @Summary      Get module with cards
+// @Security     UsersAuth
+// @Tags         modules
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Success      200  {object}  entity.ModuleWithCards
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/ [get]
+func (routes *Routes) getModuleWithCards(w http.ResponseWriter, r *http.Request) {
+	moduleWithCards, err := routes.modulesUC.GetModuleWithCards(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module searching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, moduleWithCards)
+}
+
+// Swagger spec:
+// @Summary      Export module to csv file
+// @Security     UsersAuth
+// @Tags         modules
+// @Param        module_uuid path string true "Module UUID"
+// @Produce      text/csv
+// @Success      200
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/export/csv [get]
+func (routes *Routes) exportModuleToCSV(w http.ResponseWriter, r *http.Request) {
+	moduleWithCards, err := routes.modulesUC.GetModuleWithCards(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module searching failed")
+
+		return
+	}
+
+	fileName := fmt.Sprintf("%s.%s", moduleWithCards.Name, "csv")
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+
+	csvRecords := make([][]string, 0)
+
+	for _, card := range moduleWithCards.Cards {
+		record := []string{card.Term, card.Meaning}
+		csvRecords = append(csvRecords, record)
+	}
+
+	csvWritter := csv.NewWriter(w)
+
+	err = csvWritter.WriteAll(csvRecords)
+	if err != nil {
+		routes.log.Error().Err(err).Msg("csv writing failed")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+}
+
+// Swagger spec:
+// @Summary      Export module to xlsx workbook
+// @Description  Cards are written to a single sheet named after the module, terms are in the first column
+// @Description  and meanings are in the second one. All cells are text.
+// @Security     UsersAuth
+// @Tags         modules
+// @Param        module_uuid path string true "Module UUID"
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Success      200
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/export/xlsx [get]
+func (routes *Routes) exportModuleToXLSX(w http.ResponseWriter, r *http.Request) {
+	module, workbook, err := routes.modulesUC.ExportModuleToXLSX(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module exporting to xlsx failed")
+
+		return
+	}
+
+	fileName := fmt.Sprintf("%s.%s", module.Name, "xlsx")
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+
+	_, err = w.Write(workbook)
+	if err != nil {
+		routes.log.Error().Err(err).Msg("xlsx writing failed")
+	}
+}
+
+// Swagger spec:
+// @Summary      Export module to json archive
+// @Description  The archive is a lossless backup of the module with its settings, cards and review states.
+// @Security     UsersAuth
+// @Tags         modules
+// @Param        module_uuid path string true "Module UUID"
+// @Produce      json
+// @Success      200  {object}  entity.ModuleArchive
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/export/json [get]
+func (routes *Routes) exportModuleToArchive(w http.ResponseWriter, r *http.Request) {
+	archive, err := routes.modulesUC.ExportModuleToArchive(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module exporting to archive failed")
+
+		return
+	}
+
+	fileName := fmt.Sprintf("%s.%s", archive.Module.Name, "json")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	err = encoder.Encode(archive)
+	if err != nil {
+		routes.log.Error().Err(err).Msg("module archive writing failed")
+	}
+}
+
+// Swagger spec:
+// @Summary      Export module to anki package
+// @Description  The module becomes a deck of "Basic" notes, or of "Basic (and reversed card)" notes when reversed.
+// @Description  Review states of the cards are exported as anki scheduling data, reversed cards are new.
+// @Security     UsersAuth
+// @Tags         modules
+// @Param        module_uuid path string true "Module UUID"
+// @Param        reversed query boolean false "Add reversed cards"
+// @Produce      octet-stream
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/export/apkg [get]
+func (routes *Routes) exportModuleToAPKG(w http.ResponseWriter, r *http.Request) {
+	reversed := false
+
+	if value := r.URL.Query().Get("reversed"); value != "" {
+		var err error
+
+		reversed, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "reversed must be a boolean", http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	module, apkgPackage, err := routes.modulesUC.ExportModuleToAPKG(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+		reversed,
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module exporting to anki package failed")
+
+		return
+	}
+
+	fileName := fmt.Sprintf("%s.%s", module.Name, "apkg")
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+
+	_, err = w.Write(apkgPackage)
+	if err != nil {
+		routes.log.Error().Err(err).Msg("anki package writing failed")
+	}
+}
+
+// Swagger spec:
+// @Summary      Export modules to zip archive
+// @Description  The archive with a file of the format per module is built asynchronously,
+// @Description  the whole library is exported when no module is selected. The processed archive
+// @Description  can be downloaded till expires_at of the export job.
+// @Security     UsersAuth
+// @Tags         modules
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.LibraryExportRequest true "Export params"
+// @Success      202  {object}  entity.ExportJob
+// @Header       202  {string}  Location  "Export job URL"
+// @Failure      400
+// @Failure      404
+// @Failure      422
+// @Failure      500
+// @Router       /api/modules/export [post]
+func (routes *Routes) exportLibrary(w http.ResponseWriter, r *http.Request) {
+	var req dto.LibraryExportRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	job, err := routes.modulesUC.QueueLibraryExport(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		req.Format,
+		req.ModuleUUIDs,
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		switch {
+		case errors.As(err, &notFoundErr):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, entity.ErrNoModulesToExport):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("library export queue failed")
+
+		return
+	}
+
+	w.Header().Set("Location", "/api/modules/export/"+job.UUID)
+	w.WriteHeader(http.StatusAccepted)
+
+	routes.jsonResponse(w, job)
+}
+
+// Swagger spec:
+// @Summary      Get export job
+// @Description  Export job with status 'created' | 'processing' | 'processed' | 'failed'
+// @Security     UsersAuth
+// @Tags         modules
+// @Produce      json
+// @Param        job_uuid path string true "Export job UUID"
+// @Success      200  {object}  entity.ExportJob
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/export/{job_uuid} [get]
+func (routes *Routes) getExportJob(w http.ResponseWriter, r *http.Request) {
+	job, err := routes.modulesUC.GetExportJob(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("job_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ExportJobNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("export job fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, job)
+}
+
+// Swagger spec:
+// @Summary      Download export archive
+// @Description  Zip archive of the processed export job, it's gone after the job's expires_at.
+// @Security     UsersAuth
+// @Tags         modules
+// @Param        job_uuid path string true "Export job UUID"
+// @Produce      application/zip
+// @Success      200
+// @Failure      404
+// @Failure      409
+// @Failure      410
+// @Failure      500
+// @Router       /api/modules/export/{job_uuid}/download [get]
+func (routes *Routes) downloadExportArchive(w http.ResponseWriter, r *http.Request) {
+	job, archive, err := routes.modulesUC.GetExportArchive(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("job_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ExportJobNotFoundError
+
+		switch {
+		case errors.As(err, &notFoundErr):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, entity.ErrExportNotReady):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, entity.ErrExportArchiveExpired):
+			http.Error(w, err.Error(), http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("export archive fetching failed")
+
+		return
+	}
+
+	fileName := fmt.Sprintf("modules-%s-%s.zip", job.Format, job.CreatedAt.Format(time.DateOnly))
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+
+	_, err = w.Write(archive)
+	if err != nil {
+		routes.log.Error().Err(err).Msg("export archive writing failed")
+	}
+}
+
+func (routes *Routes) Apply(r chi.Router) {
+	r.Route("/api/modules", func(r chi.Router) {
+		r.Get("/", routes.getAllModules)
+		r.Post("/", routes.createModule)
+
+		r.Route("/import", func(r chi.Router) {
+			r.Get("/", routes.getImportJobs)
+			r.Get("/{job_uuid}", routes.getImportJob)
+			r.Delete("/{job_uuid}", routes.cancelImportJob)
+			r.Get("/{job_uuid}/errors", routes.getImportJobErrors)
+			r.Get("/{job_uuid}/errors/csv", routes.exportImportJobErrorsToCSV)
+			r.Post("/quizlet", routes.importModuleFromQuizlet)
+			r.Post("/csv", routes.importModuleFromCSV)
+			r.Post("/apkg", routes.importModulesFromAPKG)
+			r.Post("/xlsx", routes.importModuleFromXLSX)
+			r.Post("/json", routes.importModuleFromArchive)
+			r.Post("/preview", routes.previewImport)
+		})
+
+		r.Route("/export", func(r chi.Router) {
+			r.Post("/", routes.exportLibrary)
+			r.Get("/{job_uuid}", routes.getExportJob)
+			r.Get("/{job_uuid}/download", routes.downloadExportArchive)
 		})
 
 		r.Route("/{module_uuid}", func(r chi.Router) {
@@ -372,6 +1338,9 @@ func (routes *Routes) Apply(r chi.Router) {
 
 			r.Route("/export", func(r chi.Router) {
 				r.Get("/csv", routes.exportModuleToCSV)
+				r.Get("/apkg", routes.exportModuleToAPKG)
+				r.Get("/xlsx", routes.exportModuleToXLSX)
+				r.Get("/json", routes.exportModuleToArchive)
 			})
 		})
 	})
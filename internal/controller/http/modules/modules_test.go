@@ -1,12 +1,20 @@
 package modules_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	testutils "github.com/llravell/simple-cards/internal"
@@ -14,9 +22,17 @@ import (
 	"github.com/llravell/simple-cards/internal/entity"
 	"github.com/llravell/simple-cards/internal/mocks"
 	"github.com/llravell/simple-cards/internal/usecase"
+	"github.com/llravell/simple-cards/pkg/apkg"
+	"github.com/llravell/simple-cards/pkg/csvcards"
+	"github.com/llravell/simple-cards/pkg/quizlet"
+	"github.com/llravell/simple-cards/pkg/sqlitefile"
+	"github.com/llravell/simple-cards/pkg/xlsx"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
 )
 
 type testCase struct {
@@ -27,12 +43,17 @@ type testCase struct {
 	expectedBody string
 }
 
+// maxNotificationPayloadSize is the limit of a Postgres notification payload.
+const maxNotificationPayloadSize = 8000
+
 var testModule = entity.Module{
 	UUID:     "some-uuid",
 	Name:     "module for testing",
 	UserUUID: "some-user-uuid",
 }
 
+var testLastStudiedAt = time.Date(2024, time.December, 20, 10, 0, 0, 0, time.UTC)
+
 var testCard = entity.Card{
 	UUID:       "card-uuid",
 	Term:       "term",
@@ -44,20 +65,43 @@ func prepareTestServer(
 	t *testing.T,
 	modulesRepo usecase.ModulesRepository,
 	cardsRepo usecase.CardsRepository,
+	reviewRepo usecase.ReviewRepository,
+	importJobsRepo usecase.ImportJobsRepository,
+	exportJobsRepo usecase.ExportJobsRepository,
 	quizletModuleParser usecase.QuizletModuleParser,
 	quizletImportWP usecase.QuizletImportWorkerPool,
 	csvImportWP usecase.CSVImportWorkerPool,
+	apkgImportWP usecase.APKGImportWorkerPool,
+	xlsxImportWP usecase.XLSXImportWorkerPool,
+	libraryExportWP usecase.LibraryExportWorkerPool,
 ) *httptest.Server {
 	t.Helper()
 
 	log := zerolog.Nop()
+	jobEventsNotifier := mocks.NewMockJobEventsNotifier(gomock.NewController(t))
+
+	jobEventsNotifier.EXPECT().
+		Notify(gomock.Any(), gomock.Any()).
+		Do(func(_ any, payload []byte) {
+			assert.Less(t, len(payload), maxNotificationPayloadSize)
+		}).
+		Return(nil).
+		AnyTimes()
 
 	modulesUseCase := usecase.NewModulesUseCase(
 		modulesRepo,
 		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
 		quizletModuleParser,
 		quizletImportWP,
 		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+		usecase.NewRunningImports(),
+		usecase.NewJobEvents(jobEventsNotifier, &log),
 		&log,
 	)
 	router := chi.NewRouter()
@@ -71,11 +115,30 @@ func prepareTestServer(
 func TestGetAllModules(t *testing.T) {
 	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
 	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
 	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
 	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
 	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
 
-	ts := prepareTestServer(t, modulesRepo, cardsRepo, quizletModuleParser, quizletImportWP, csvImportWP)
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
 
 	defer ts.Close()
 
@@ -99,6 +162,42 @@ func TestGetAllModules(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedBody: testutils.ToJSON(t, []entity.Module{testModule}),
 		},
+		{
+			name: "modules returned with progress",
+			mock: func() {
+				modulesRepo.EXPECT().
+					GetAllModules(gomock.Any(), gomock.Any()).
+					Return([]*entity.Module{
+						{
+							UUID:     "some-uuid",
+							Name:     "module for testing",
+							UserUUID: "some-user-uuid",
+							Progress: &entity.ModuleProgress{
+								CardsCount:    10,
+								NewCount:      4,
+								LearningCount: 5,
+								MasteredCount: 1,
+								DueTodayCount: 3,
+								LastStudiedAt: &testLastStudiedAt,
+							},
+						},
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `[{
+				"uuid": "some-uuid",
+				"name": "module for testing",
+				"user_uuid": "some-user-uuid",
+				"progress": {
+					"cards_count": 10,
+					"new_count": 4,
+					"learning_count": 5,
+					"mastered_count": 1,
+					"due_today_count": 3,
+					"last_studied_at": "2024-12-20T10:00:00Z"
+				}
+			}]`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -121,11 +220,30 @@ func TestGetAllModules(t *testing.T) {
 func TestCreateModule(t *testing.T) {
 	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
 	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
 	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
 	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
 	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
 
-	ts := prepareTestServer(t, modulesRepo, cardsRepo, quizletModuleParser, quizletImportWP, csvImportWP)
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
 
 	defer ts.Close()
 
@@ -199,11 +317,30 @@ func TestCreateModule(t *testing.T) {
 func TestUpdateModule(t *testing.T) {
 	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
 	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
 	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
 	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
 	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
 
-	ts := prepareTestServer(t, modulesRepo, cardsRepo, quizletModuleParser, quizletImportWP, csvImportWP)
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
 
 	defer ts.Close()
 
@@ -279,11 +416,30 @@ func TestUpdateModule(t *testing.T) {
 func TestDeleteModule(t *testing.T) {
 	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
 	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
 	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
 	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
 	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
 
-	ts := prepareTestServer(t, modulesRepo, cardsRepo, quizletModuleParser, quizletImportWP, csvImportWP)
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
 
 	defer ts.Close()
 
@@ -331,11 +487,30 @@ func TestDeleteModule(t *testing.T) {
 func TestGetModuleWithCards(t *testing.T) {
 	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
 	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
 	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
 	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
 	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
 
-	ts := prepareTestServer(t, modulesRepo, cardsRepo, quizletModuleParser, quizletImportWP, csvImportWP)
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
 
 	defer ts.Close()
 
@@ -410,3 +585,2990 @@ func TestGetModuleWithCards(t *testing.T) {
 		})
 	}
 }
+
+//nolint:funlen
+func TestExportModuleToAPKG(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	newCard := entity.Card{UUID: "new-card-uuid", Term: "new term", Meaning: "new meaning", ModuleUUID: "module-uuid"}
+	reviewState := &entity.ReviewState{
+		CardUUID:     "card-uuid",
+		ModuleUUID:   "module-uuid",
+		EaseFactor:   2.5,
+		IntervalDays: 6,
+		Repetitions:  3,
+		Lapses:       1,
+		DueAt:        time.Now().Add(72 * time.Hour),
+	}
+
+	expectModule := func() {
+		modulesRepo.EXPECT().
+			GetModule(gomock.Any(), gomock.Any(), "module-uuid").
+			Return(&testModule, nil)
+
+		cardsRepo.EXPECT().
+			GetModuleCards(gomock.Any(), "module-uuid").
+			Return([]*entity.Card{&testCard, &newCard}, nil)
+	}
+
+	// cardsRows reads the exported cards table, anki cards have type 0 when they are new and 2 when reviewed
+	cardsRows := func(t *testing.T, body []byte) []sqlitefile.Row {
+		t.Helper()
+
+		collection, err := apkg.ExtractCollection(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+
+		decks, noteErrors, err := apkg.ReadDecks(context.Background(), collection, apkg.FieldMapping{})
+		require.NoError(t, err)
+		assert.Empty(t, noteErrors)
+		require.Len(t, decks, 1)
+		assert.Equal(t, testModule.Name, decks[0].Name)
+		assert.Equal(t, []apkg.Card{
+			{Term: testCard.Term, Meaning: testCard.Meaning},
+			{Term: newCard.Term, Meaning: newCard.Meaning},
+		}, decks[0].Cards)
+
+		db, err := sqlitefile.Open(collection)
+		require.NoError(t, err)
+
+		rows, err := db.Rows(context.Background(), "cards")
+		require.NoError(t, err)
+
+		return rows
+	}
+
+	testCases := []struct {
+		name         string
+		mock         func()
+		query        string
+		expectedCode int
+		checkPackage func(t *testing.T, body []byte)
+	}{
+		{
+			name:         "send invalid reversed flag",
+			mock:         func() {},
+			query:        "?reversed=maybe",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "module not found",
+			mock: func() {
+				modulesRepo.EXPECT().
+					GetModule(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(nil, &entity.ModuleNotFoundError{})
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "review repo error",
+			mock: func() {
+				expectModule()
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid", "new-card-uuid"}).
+					Return(nil, errors.New("boom"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "review states are exported as anki scheduling data",
+			mock: func() {
+				expectModule()
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid", "new-card-uuid"}).
+					Return(map[string]*entity.ReviewState{"card-uuid": reviewState}, nil)
+			},
+			expectedCode: http.StatusOK,
+			checkPackage: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				rows := cardsRows(t, body)
+				require.Len(t, rows, 2)
+
+				assert.Equal(t, int64(2), rows[0].Int("type"))
+				assert.Equal(t, int64(6), rows[0].Int("ivl"))
+				assert.Equal(t, int64(2500), rows[0].Int("factor"))
+				assert.Equal(t, int64(3), rows[0].Int("reps"))
+				assert.Equal(t, int64(1), rows[0].Int("lapses"))
+				assert.Equal(t, int64(0), rows[1].Int("type"))
+			},
+		},
+		{
+			name: "reversed cards are added",
+			mock: func() {
+				expectModule()
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(map[string]*entity.ReviewState{}, nil)
+			},
+			query:        "?reversed=true",
+			expectedCode: http.StatusOK,
+			checkPackage: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				rows := cardsRows(t, body)
+				require.Len(t, rows, 4)
+
+				assert.Equal(t, int64(0), rows[0].Int("ord"))
+				assert.Equal(t, int64(1), rows[1].Int("ord"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodGet,
+				"/api/modules/module-uuid/export/apkg"+tc.query, http.NoBody, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.checkPackage != nil {
+				assert.Equal(
+					t,
+					"attachment; filename=\"module for testing.apkg\"",
+					res.Header.Get("Content-Disposition"),
+				)
+				tc.checkPackage(t, body)
+			}
+		})
+	}
+}
+
+func TestExportModuleToXLSX(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	t.Run("module not found", func(t *testing.T) {
+		modulesRepo.EXPECT().
+			GetModule(gomock.Any(), gomock.Any(), "module-uuid").
+			Return(nil, &entity.ModuleNotFoundError{})
+
+		res, _ := testutils.SendTestRequest(
+			t, ts, http.MethodGet, "/api/modules/module-uuid/export/xlsx", http.NoBody, map[string]string{},
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("cards are exported to sheet", func(t *testing.T) {
+		multilineCard := entity.Card{UUID: "multiline-card-uuid", Term: "007", Meaning: "agent\n<secret> & \"spy\""}
+
+		modulesRepo.EXPECT().
+			GetModule(gomock.Any(), gomock.Any(), "module-uuid").
+			Return(&testModule, nil)
+
+		cardsRepo.EXPECT().
+			GetModuleCards(gomock.Any(), "module-uuid").
+			Return([]*entity.Card{&testCard, &multilineCard}, nil)
+
+		res, body := testutils.SendTestRequest(
+			t, ts, http.MethodGet, "/api/modules/module-uuid/export/xlsx", http.NoBody, map[string]string{},
+		)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "attachment; filename=\"module for testing.xlsx\"", res.Header.Get("Content-Disposition"))
+
+		workbook, err := xlsx.Open(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{testModule.Name}, workbook.SheetNames())
+
+		rows, err := workbook.Rows("")
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{testCard.Term, testCard.Meaning},
+			{multilineCard.Term, multilineCard.Meaning},
+		}, rows)
+	})
+}
+
+var testArchivedModule = entity.ArchivedModule{
+	UUID:      "module-uuid",
+	Name:      "module for testing",
+	CreatedAt: time.Date(2024, time.December, 10, 9, 30, 0, 0, time.UTC),
+	Settings:  entity.ModuleSettings{Scheduler: entity.SchedulerFSRS},
+	Cards: []*entity.ArchivedCard{
+		{
+			UUID:      "card-uuid",
+			Term:      "term",
+			Meaning:   "meaning\nwith two lines",
+			CreatedAt: time.Date(2024, time.December, 10, 9, 31, 0, 0, time.UTC),
+			ReviewState: &entity.ArchivedReviewState{
+				EaseFactor:     2.36,
+				Stability:      12.5,
+				Difficulty:     4.75,
+				IntervalDays:   13,
+				Repetitions:    4,
+				Lapses:         1,
+				DueAt:          time.Date(2024, time.December, 30, 9, 0, 0, 0, time.UTC),
+				LastReviewedAt: time.Date(2024, time.December, 17, 9, 0, 0, 0, time.UTC),
+				IsSuspended:    true,
+			},
+		},
+		{
+			UUID:      "new-card-uuid",
+			Term:      "new term",
+			Meaning:   "new meaning",
+			CreatedAt: time.Date(2024, time.December, 10, 9, 32, 0, 0, time.UTC),
+		},
+	},
+}
+
+//nolint:funlen
+func TestExportModuleToArchive(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	t.Run("module not found", func(t *testing.T) {
+		modulesRepo.EXPECT().
+			GetArchivedModule(gomock.Any(), gomock.Any(), "module-uuid").
+			Return(nil, &entity.ModuleNotFoundError{})
+
+		res, _ := testutils.SendTestRequest(
+			t, ts, http.MethodGet, "/api/modules/module-uuid/export/json", http.NoBody, map[string]string{},
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		modulesRepo.EXPECT().
+			GetArchivedModule(gomock.Any(), gomock.Any(), "module-uuid").
+			Return(nil, errors.New("boom"))
+
+		res, _ := testutils.SendTestRequest(
+			t, ts, http.MethodGet, "/api/modules/module-uuid/export/json", http.NoBody, map[string]string{},
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("exported archive is imported without losses", func(t *testing.T) {
+		archivedModule := testArchivedModule
+
+		modulesRepo.EXPECT().
+			GetArchivedModule(gomock.Any(), gomock.Any(), "module-uuid").
+			Return(&archivedModule, nil)
+
+		res, body := testutils.SendTestRequest(
+			t, ts, http.MethodGet, "/api/modules/module-uuid/export/json", http.NoBody, map[string]string{},
+		)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		assert.Equal(t, "attachment; filename=\"module for testing.json\"", res.Header.Get("Content-Disposition"))
+
+		var archive entity.ModuleArchive
+
+		require.NoError(t, json.Unmarshal(body, &archive))
+		assert.Equal(t, entity.ModuleArchiveFormat, archive.Format)
+		assert.Equal(t, entity.ModuleArchiveVersion, archive.Version)
+		assert.False(t, archive.ExportedAt.IsZero())
+
+		modulesRepo.EXPECT().
+			CreateModuleFromArchive(gomock.Any(), gomock.Any(), &testArchivedModule).
+			Return(&entity.Module{UUID: "restored-module-uuid", Name: testArchivedModule.Name}, nil)
+
+		res, _ = testutils.SendTestRequest(
+			t, ts, http.MethodPost, "/api/modules/import/json", bytes.NewReader(body), map[string]string{},
+		)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+	})
+}
+
+//nolint:funlen
+func TestImportModuleFromArchive(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name:         "send invalid json",
+			mock:         func() {},
+			body:         strings.NewReader("{"),
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "send json of another format",
+			mock:         func() {},
+			body:         strings.NewReader(`{"format":"other","version":1}`),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "invalid module archive: format must be \"simple-cards.module\"\n",
+		},
+		{
+			name:         "send archive of newer version",
+			mock:         func() {},
+			body:         strings.NewReader(`{"format":"simple-cards.module","version":2,"module":{"name":"module"}}`),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "invalid module archive: version 2 is not supported, the latest version is 1\n",
+		},
+		{
+			name:         "send archive without module",
+			mock:         func() {},
+			body:         strings.NewReader(`{"format":"simple-cards.module","version":1}`),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "invalid module archive: module is missing\n",
+		},
+		{
+			name: "send archive with unknown scheduler",
+			mock: func() {},
+			body: strings.NewReader(
+				`{"format":"simple-cards.module","version":1,"module":{"name":"module","settings":{"scheduler":"x"}}}`,
+			),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "invalid module archive: scheduler \"x\" is unknown\n",
+		},
+		{
+			name: "send archive with empty card",
+			mock: func() {},
+			body: strings.NewReader(
+				`{"format":"simple-cards.module","version":1,"module":{"name":"module","cards":[{"term":"a"}]}}`,
+			),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "invalid module archive: card 1: term or meaning is empty\n",
+		},
+		{
+			name: "repo error",
+			mock: func() {
+				modulesRepo.EXPECT().
+					CreateModuleFromArchive(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("boom"))
+			},
+			body:         strings.NewReader(`{"format":"simple-cards.module","version":1,"module":{"name":"module"}}`),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "module is created",
+			mock: func() {
+				modulesRepo.EXPECT().
+					CreateModuleFromArchive(gomock.Any(), gomock.Any(), &entity.ArchivedModule{
+						Name: "module",
+						Cards: []*entity.ArchivedCard{
+							{Term: "term", Meaning: "meaning"},
+						},
+					}).
+					Return(&testModule, nil)
+			},
+			body: strings.NewReader(
+				`{"format":"simple-cards.module","version":1,"future_field":true,` +
+					`"module":{"name":"module","cards":[{"term":"term","meaning":"meaning"}]}}`,
+			),
+			expectedCode: http.StatusCreated,
+			expectedBody: `{"uuid":"some-uuid","name":"module for testing","user_uuid":"some-user-uuid"}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodPost, "/api/modules/import/json", tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+var testImportJob = entity.ImportJob{
+	UUID:       "job-uuid",
+	UserUUID:   "some-user-uuid",
+	Source:     entity.ImportSourceQuizlet,
+	Status:     entity.ImportStatusCreated,
+	ModuleName: "imported module",
+	CreatedAt:  time.Date(2024, time.December, 24, 10, 0, 0, 0, time.UTC),
+	UpdatedAt:  time.Date(2024, time.December, 24, 10, 0, 0, 0, time.UTC),
+}
+
+//nolint:funlen,maintidx
+func TestImportModuleFromQuizlet(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	validBody := `{"module_name":"imported module","quizlet_module_id":"123"}`
+
+	expectJobCreation := func() {
+		importJobsRepo.EXPECT().
+			CreateImportJob(gomock.Any(), &entity.ImportJob{
+				Source:     entity.ImportSourceQuizlet,
+				ModuleName: "imported module",
+			}).
+			DoAndReturn(func(_ any, _ *entity.ImportJob) (*entity.ImportJob, error) {
+				job := testImportJob
+
+				return &job, nil
+			})
+	}
+
+	expectJobStatuses := func(statuses ...string) {
+		calls := make([]any, 0, len(statuses))
+
+		for _, status := range statuses {
+			calls = append(calls, importJobsRepo.EXPECT().
+				UpdateImportJob(gomock.Any(), gomock.Any()).
+				Do(func(_ any, job *entity.ImportJob) {
+					assert.Equal(t, status, job.Status)
+				}).
+				Return(nil))
+		}
+
+		gomock.InOrder(calls...)
+	}
+
+	// expectStoredModuleOfCanceledJob stores the module, but the job turns out to be canceled when it's finished.
+	expectStoredModuleOfCanceledJob := func() {
+		quizletModuleParser.EXPECT().
+			Parse(gomock.Any(), "123").
+			Return([]quizlet.Card{{Front: "term", Back: "meaning"}}, nil)
+
+		importJobsRepo.EXPECT().
+			GetImportJob(gomock.Any(), "some-user-uuid", "job-uuid").
+			Return(&testImportJob, nil)
+
+		modulesRepo.EXPECT().
+			CreateNewModuleWithCards(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, moduleWithCards *entity.ModuleWithCards) error {
+				moduleWithCards.UUID = "module-uuid"
+
+				return nil
+			})
+
+		gomock.InOrder(
+			importJobsRepo.EXPECT().
+				UpdateImportJob(gomock.Any(), gomock.Any()).
+				Return(nil),
+			importJobsRepo.EXPECT().
+				UpdateImportJob(gomock.Any(), gomock.Any()).
+				Return(entity.ErrImportJobCanceled),
+		)
+	}
+
+	type importTestCase struct {
+		testCase
+		expectedLocation string
+	}
+
+	testCases := []importTestCase{
+		{
+			testCase: testCase{
+				name:         "send invalid params",
+				mock:         func() {},
+				body:         strings.NewReader(`{"module_name":"imported module"}`),
+				expectedCode: http.StatusBadRequest,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "job creation error",
+				mock: func() {
+					importJobsRepo.EXPECT().
+						CreateImportJob(gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("boom"))
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "queue error fails job",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Return(errors.New("boom"))
+
+					expectJobStatuses(entity.ImportStatusFailed)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "long failure reason fits into job event",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Return(errors.New(strings.Repeat("<queue is full> ", 1000)))
+
+					expectJobStatuses(entity.ImportStatusFailed)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "empty quizlet module fails job",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							quizletModuleParser.EXPECT().
+								Parse(gomock.Any(), "123").
+								Return([]quizlet.Card{}, nil)
+
+							expectJobStatuses(entity.ImportStatusProcessing, entity.ImportStatusFailed)
+
+							assert.NoError(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+				expectedBody: testutils.ToJSON(t, testImportJob),
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "quizlet fetching error is returned to retry the work",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							quizletModuleParser.EXPECT().
+								Parse(gomock.Any(), "123").
+								Return(nil, &quizlet.ModuleFetchingError{ID: "123"})
+
+							expectJobStatuses(entity.ImportStatusProcessing)
+
+							assert.True(t, usecase.IsRetryableImportError(work.Do(context.Background())))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "canceled job is skipped",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							importJobsRepo.EXPECT().
+								UpdateImportJob(gomock.Any(), gomock.Any()).
+								Return(entity.ErrImportJobCanceled)
+
+							assert.NoError(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "redelivered work of finished job is skipped",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							importJobsRepo.EXPECT().
+								UpdateImportJob(gomock.Any(), gomock.Any()).
+								Return(entity.ErrImportJobFinished)
+
+							assert.NoError(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "module of job canceled while parsing is not stored",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							quizletModuleParser.EXPECT().
+								Parse(gomock.Any(), "123").
+								Return([]quizlet.Card{{Front: "term", Back: "meaning"}}, nil)
+
+							expectJobStatuses(entity.ImportStatusProcessing)
+
+							canceledJob := testImportJob
+							canceledJob.Status = entity.ImportStatusCanceled
+
+							importJobsRepo.EXPECT().
+								GetImportJob(gomock.Any(), "some-user-uuid", "job-uuid").
+								Return(&canceledJob, nil)
+
+							assert.NoError(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "module of job canceled while storing is removed",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							expectStoredModuleOfCanceledJob()
+
+							modulesRepo.EXPECT().
+								DeleteModule(gomock.Any(), "some-user-uuid", "module-uuid").
+								Return(nil)
+
+							assert.NoError(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "failed removal of module of job canceled while storing is returned",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							expectStoredModuleOfCanceledJob()
+
+							modulesRepo.EXPECT().
+								DeleteModule(gomock.Any(), "some-user-uuid", "module-uuid").
+								Return(errors.New("violates foreign key constraint"))
+
+							assert.Error(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "module imported successfully",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							quizletModuleParser.EXPECT().
+								Parse(gomock.Any(), "123").
+								Return([]quizlet.Card{{Front: "term", Back: "meaning"}}, nil)
+
+							importJobsRepo.EXPECT().
+								GetImportJob(gomock.Any(), "some-user-uuid", "job-uuid").
+								Return(&testImportJob, nil)
+
+							modulesRepo.EXPECT().
+								CreateNewModuleWithCards(gomock.Any(), gomock.Any()).
+								DoAndReturn(func(_ any, moduleWithCards *entity.ModuleWithCards) error {
+									moduleWithCards.UUID = "module-uuid"
+
+									return nil
+								})
+
+							gomock.InOrder(
+								importJobsRepo.EXPECT().
+									UpdateImportJob(gomock.Any(), gomock.Any()).
+									Return(nil),
+								importJobsRepo.EXPECT().
+									UpdateImportJob(gomock.Any(), gomock.Any()).
+									Do(func(_ any, job *entity.ImportJob) {
+										assert.Equal(t, entity.ImportStatusProcessed, job.Status)
+										assert.Equal(t, "module-uuid", job.ModuleUUID)
+										assert.Equal(t, 1, job.CardsCount)
+									}).
+									Return(nil),
+							)
+
+							assert.NoError(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "failed finishing of job is returned to retry the work",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							quizletModuleParser.EXPECT().
+								Parse(gomock.Any(), "123").
+								Return([]quizlet.Card{{Front: "term", Back: "meaning"}}, nil)
+
+							importJobsRepo.EXPECT().
+								GetImportJob(gomock.Any(), "some-user-uuid", "job-uuid").
+								Return(&testImportJob, nil)
+
+							modulesRepo.EXPECT().
+								CreateNewModuleWithCards(gomock.Any(), gomock.Any()).
+								Return(nil)
+
+							gomock.InOrder(
+								importJobsRepo.EXPECT().
+									UpdateImportJob(gomock.Any(), gomock.Any()).
+									Return(nil),
+								importJobsRepo.EXPECT().
+									UpdateImportJob(gomock.Any(), gomock.Any()).
+									Return(errors.New("boom")),
+							)
+
+							assert.Error(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "requeued dead work imports module",
+				mock: func() {
+					expectJobCreation()
+
+					quizletImportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.QuizletImportWork) {
+							fetchingErr := &quizlet.ModuleFetchingError{ID: "123"}
+
+							gomock.InOrder(
+								quizletModuleParser.EXPECT().
+									Parse(gomock.Any(), "123").
+									Return(nil, fetchingErr),
+								quizletModuleParser.EXPECT().
+									Parse(gomock.Any(), "123").
+									Return([]quizlet.Card{{Front: "term", Back: "meaning"}}, nil),
+							)
+
+							importJobsRepo.EXPECT().
+								GetImportJob(gomock.Any(), "some-user-uuid", "job-uuid").
+								Return(&testImportJob, nil)
+
+							modulesRepo.EXPECT().
+								CreateNewModuleWithCards(gomock.Any(), gomock.Any()).
+								Return(nil)
+
+							// the requeued job is reopened, so the redelivered work can start it again
+							expectJobStatuses(
+								entity.ImportStatusProcessing,
+								entity.ImportStatusFailed,
+								entity.ImportStatusProcessing,
+								entity.ImportStatusProcessed,
+							)
+
+							err := work.Do(context.Background())
+							assert.ErrorIs(t, err, fetchingErr)
+
+							work.Dead(context.Background(), err)
+
+							assert.NoError(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(validBody),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t,
+				ts,
+				http.MethodPost,
+				"/api/modules/import/quizlet",
+				tc.body,
+				map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+			assert.Equal(t, tc.expectedLocation, res.Header.Get("Location"))
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen,maintidx
+func TestImportModuleFromQuizletIntoExistingModule(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	const moduleUUID = "8b8e4d0c-1f4e-4d3a-9a63-6f2b8f0f2a11"
+
+	existingModule := &entity.Module{
+		UUID:     moduleUUID,
+		Name:     "vocabulary",
+		UserUUID: "some-user-uuid",
+	}
+
+	expectMerge := func(
+		strategy string,
+		expectedJob func(job *entity.ImportJob),
+		expectedCards func(newCards, updatedCards []*entity.Card),
+	) {
+		modulesRepo.EXPECT().
+			GetModule(gomock.Any(), gomock.Any(), moduleUUID).
+			Return(existingModule, nil)
+
+		importJobsRepo.EXPECT().
+			CreateImportJob(gomock.Any(), &entity.ImportJob{
+				Source:     entity.ImportSourceQuizlet,
+				ModuleName: "vocabulary",
+				ModuleUUID: moduleUUID,
+				Strategy:   strategy,
+				UserUUID:   "some-user-uuid",
+			}).
+			DoAndReturn(func(_ any, job *entity.ImportJob) (*entity.ImportJob, error) {
+				createdJob := *job
+				createdJob.UUID = "job-uuid"
+
+				return &createdJob, nil
+			})
+
+		quizletImportWP.EXPECT().
+			QueueWork(gomock.Any()).
+			Do(func(work *usecase.QuizletImportWork) {
+				quizletModuleParser.EXPECT().
+					Parse(gomock.Any(), "123").
+					Return([]quizlet.Card{
+						{Front: "Cät", Back: "кошка"},
+						{Front: "dog", Back: "собака"},
+						{Front: "Dog!", Back: "пёс"},
+					}, nil)
+
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), "some-user-uuid", "job-uuid").
+					Return(&testImportJob, nil)
+
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), "some-user-uuid", moduleUUID).
+					Return(true, nil)
+
+				cardsRepo.EXPECT().
+					GetModuleCards(gomock.Any(), moduleUUID).
+					Return([]*entity.Card{
+						{UUID: "cat-uuid", Term: "cat", Meaning: "кот", ModuleUUID: moduleUUID},
+					}, nil)
+
+				modulesRepo.EXPECT().
+					AddCardsToModule(gomock.Any(), moduleUUID, gomock.Any(), gomock.Any()).
+					Do(func(_ any, _ string, newCards, updatedCards []*entity.Card) {
+						expectedCards(newCards, updatedCards)
+					}).
+					Return(nil)
+
+				gomock.InOrder(
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Return(nil),
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Do(func(_ any, job *entity.ImportJob) {
+							assert.Equal(t, entity.ImportStatusProcessed, job.Status)
+							assert.Equal(t, moduleUUID, job.ModuleUUID)
+							expectedJob(job)
+						}).
+						Return(nil),
+				)
+
+				assert.NoError(t, work.Do(context.Background()))
+			}).
+			Return(nil)
+	}
+
+	type importTestCase struct {
+		testCase
+		expectedLocation string
+	}
+
+	testCases := []importTestCase{
+		{
+			testCase: testCase{
+				name: "send invalid strategy",
+				mock: func() {},
+				body: strings.NewReader(
+					`{"quizlet_module_id":"123","module_uuid":"` + moduleUUID + `","strategy":"merge"}`,
+				),
+				expectedCode: http.StatusBadRequest,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "module not found",
+				mock: func() {
+					modulesRepo.EXPECT().
+						GetModule(gomock.Any(), gomock.Any(), moduleUUID).
+						Return(nil, &entity.ModuleNotFoundError{UUID: moduleUUID})
+				},
+				body:         strings.NewReader(`{"quizlet_module_id":"123","module_uuid":"` + moduleUUID + `"}`),
+				expectedCode: http.StatusNotFound,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "all cards appended by default",
+				mock: func() {
+					expectMerge(
+						entity.ImportStrategyAppend,
+						func(job *entity.ImportJob) {
+							assert.Equal(t, 3, job.CardsCount)
+							assert.Equal(t, 0, job.UpdatedCardsCount)
+							assert.Equal(t, 0, job.SkippedCardsCount)
+						},
+						func(newCards, updatedCards []*entity.Card) {
+							assert.Len(t, newCards, 3)
+							assert.Empty(t, updatedCards)
+						},
+					)
+				},
+				body:         strings.NewReader(`{"quizlet_module_id":"123","module_uuid":"` + moduleUUID + `"}`),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "duplicates skipped",
+				mock: func() {
+					expectMerge(
+						entity.ImportStrategySkipDuplicates,
+						func(job *entity.ImportJob) {
+							assert.Equal(t, 1, job.CardsCount)
+							assert.Equal(t, 0, job.UpdatedCardsCount)
+							assert.Equal(t, 2, job.SkippedCardsCount)
+						},
+						func(newCards, updatedCards []*entity.Card) {
+							assert.Equal(t, []*entity.Card{{Term: "dog", Meaning: "собака"}}, newCards)
+							assert.Empty(t, updatedCards)
+						},
+					)
+				},
+				body: strings.NewReader(
+					`{"quizlet_module_id":"123","module_uuid":"` + moduleUUID + `","strategy":"skip-duplicates"}`,
+				),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "existing cards updated",
+				mock: func() {
+					expectMerge(
+						entity.ImportStrategyUpdateExisting,
+						func(job *entity.ImportJob) {
+							assert.Equal(t, 1, job.CardsCount)
+							assert.Equal(t, 1, job.UpdatedCardsCount)
+							assert.Equal(t, 0, job.SkippedCardsCount)
+						},
+						func(newCards, updatedCards []*entity.Card) {
+							assert.Equal(t, []*entity.Card{{Term: "dog", Meaning: "пёс"}}, newCards)
+							assert.Equal(t, []*entity.Card{
+								{UUID: "cat-uuid", Term: "cat", Meaning: "кошка", ModuleUUID: moduleUUID},
+							}, updatedCards)
+						},
+					)
+				},
+				body: strings.NewReader(
+					`{"quizlet_module_id":"123","module_uuid":"` + moduleUUID + `","strategy":"update-existing"}`,
+				),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/import/job-uuid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, _ := testutils.SendTestRequest(
+				t,
+				ts,
+				http.MethodPost,
+				"/api/modules/import/quizlet",
+				tc.body,
+				map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+			assert.Equal(t, tc.expectedLocation, res.Header.Get("Location"))
+		})
+	}
+}
+
+func csvImportForm(t *testing.T, content string, fields map[string]string) (io.Reader, map[string]string) {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+
+	file, err := writer.CreateFormFile("file", "words.csv")
+	require.NoError(t, err)
+
+	_, err = file.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return &body, map[string]string{"Content-Type": writer.FormDataContentType()}
+}
+
+//nolint:funlen
+func TestImportModuleFromCSV(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	expectImport := func(expectedStatus string, expectedRowErrors []*entity.ImportRowError, expectCards func()) {
+		importJobsRepo.EXPECT().
+			CreateImportJob(gomock.Any(), &entity.ImportJob{
+				Source:     entity.ImportSourceCSV,
+				ModuleName: "words",
+			}).
+			DoAndReturn(func(_ any, _ *entity.ImportJob) (*entity.ImportJob, error) {
+				job := testImportJob
+
+				return &job, nil
+			})
+
+		csvImportWP.EXPECT().
+			QueueWork(gomock.Any()).
+			Do(func(work *usecase.CSVImportWork) {
+				expectCards()
+
+				if len(expectedRowErrors) > 0 {
+					importJobsRepo.EXPECT().
+						SaveImportJobErrors(gomock.Any(), "job-uuid", expectedRowErrors).
+						Return(nil)
+				}
+
+				gomock.InOrder(
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Return(nil),
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Do(func(_ any, job *entity.ImportJob) {
+							assert.Equal(t, expectedStatus, job.Status)
+							assert.Equal(t, len(expectedRowErrors), job.ErrorsCount)
+						}).
+						Return(nil),
+				)
+
+				assert.NoError(t, work.Do(context.Background()))
+			}).
+			Return(nil)
+	}
+
+	expectModuleCards := func(expectedCards []*entity.Card) func() {
+		return func() {
+			importJobsRepo.EXPECT().
+				GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+				Return(&testImportJob, nil)
+
+			modulesRepo.EXPECT().
+				CreateNewModuleWithCards(gomock.Any(), gomock.Any()).
+				Do(func(_ any, moduleWithCards *entity.ModuleWithCards) {
+					assert.Equal(t, expectedCards, moduleWithCards.Cards)
+				}).
+				Return(nil)
+		}
+	}
+
+	windows1251Content, err := charmap.Windows1251.NewEncoder().String("Перевод;Слово\r\ncat;кот\r\ndog;\"собака\"\r\n")
+	require.NoError(t, err)
+
+	utf16Content, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().
+		String("term\tmeaning\nhello\tпривет\n")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		mock         func()
+		content      string
+		fields       map[string]string
+		expectedCode int
+	}{
+		{
+			name:         "send invalid dialect",
+			mock:         func() {},
+			content:      "term,meaning",
+			fields:       map[string]string{"delimiter": "pipe"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "send too large file",
+			mock:         func() {},
+			content:      strings.Repeat("cat,кот\n", 1<<17),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "comma separated utf-8 with BOM",
+			mock: func() {
+				expectImport(
+					entity.ImportStatusProcessed,
+					[]*entity.ImportRowError{{Line: 2, Reason: "term or meaning column is missing", Raw: "short row"}},
+					expectModuleCards([]*entity.Card{
+						{Term: "cat", Meaning: "кот"},
+					}),
+				)
+			},
+			content:      "\ufeffcat,кот\nshort row\n",
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "semicolon separated windows-1251 with columns mapped by header",
+			mock: func() {
+				expectImport(entity.ImportStatusProcessed, nil, expectModuleCards([]*entity.Card{
+					{Term: "кот", Meaning: "cat"},
+					{Term: "собака", Meaning: "dog"},
+				}))
+			},
+			content: windows1251Content,
+			fields: map[string]string{
+				"delimiter":      "semicolon",
+				"encoding":       "windows-1251",
+				"term_column":    "слово",
+				"meaning_column": "перевод",
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "tab separated utf-16 with detected header and swapped columns",
+			mock: func() {
+				expectImport(entity.ImportStatusProcessed, nil, expectModuleCards([]*entity.Card{
+					{Term: "привет", Meaning: "hello"},
+				}))
+			},
+			content: utf16Content,
+			fields: map[string]string{
+				"delimiter": "tab",
+				"encoding":  "utf-16",
+				"header":    "auto",
+				"swap":      "true",
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "unknown column fails job",
+			mock: func() {
+				expectImport(entity.ImportStatusFailed, nil, func() {})
+			},
+			content:      "term,meaning\ncat,кот\n",
+			fields:       map[string]string{"term_column": "word"},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "malformed row fails job with rows skipped before it",
+			mock: func() {
+				expectImport(
+					entity.ImportStatusFailed,
+					[]*entity.ImportRowError{{Line: 2, Reason: "term or meaning is empty", Raw: "dog,"}},
+					func() {},
+				)
+			},
+			content:      "cat,кот\ndog,\n\"bird\"x,птица\nfish,рыба\n",
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "lenient mode skips malformed rows",
+			mock: func() {
+				expectImport(
+					entity.ImportStatusProcessed,
+					[]*entity.ImportRowError{
+						{Line: 2, Reason: "term or meaning is empty", Raw: "dog,"},
+						{Line: 3, Reason: "extraneous or missing \" in quoted-field", Raw: "\"bird\"x,птица"},
+					},
+					expectModuleCards([]*entity.Card{
+						{Term: "cat", Meaning: "кот"},
+						{Term: "fish", Meaning: "рыба"},
+					}),
+				)
+			},
+			content:      "cat,кот\ndog,\n\"bird\"x,птица\nfish,рыба\n",
+			fields:       map[string]string{"lenient": "true"},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "send invalid lenient flag",
+			mock:         func() {},
+			content:      "cat,кот",
+			fields:       map[string]string{"lenient": "maybe"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			body, headers := csvImportForm(t, tc.content, tc.fields)
+
+			res, _ := testutils.SendTestRequest(t, ts, http.MethodPost, "/api/modules/import/csv", body, headers)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+		})
+	}
+}
+
+func fileImportForm(
+	t *testing.T,
+	fileName string,
+	content []byte,
+	fields map[string]string,
+) (io.Reader, map[string]string) {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+
+	file, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+
+	_, err = file.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return &body, map[string]string{"Content-Type": writer.FormDataContentType()}
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var archive bytes.Buffer
+
+	writer := zip.NewWriter(&archive)
+
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+
+		_, err = file.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+
+	return archive.Bytes()
+}
+
+//nolint:funlen
+func TestImportModulesFromAPKG(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	// decks.apkg has "Spanish::Animals" and "Verbs" decks of "Basic" notes with "Front" and "Back" fields,
+	// the last note has an empty back.
+	testPackage, err := os.ReadFile("testdata/decks.apkg")
+	require.NoError(t, err)
+
+	expectImport := func(expectedStatus string, expectedModuleUUIDs []string, expectModules func()) {
+		importJobsRepo.EXPECT().
+			CreateImportJob(gomock.Any(), &entity.ImportJob{
+				Source:     entity.ImportSourceAPKG,
+				ModuleName: "spanish",
+			}).
+			DoAndReturn(func(_ any, _ *entity.ImportJob) (*entity.ImportJob, error) {
+				job := testImportJob
+				job.Source = entity.ImportSourceAPKG
+
+				return &job, nil
+			})
+
+		apkgImportWP.EXPECT().
+			QueueWork(gomock.Any()).
+			Do(func(work *usecase.APKGImportWork) {
+				expectModules()
+
+				gomock.InOrder(
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Return(nil),
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Do(func(_ any, job *entity.ImportJob) {
+							assert.Equal(t, expectedStatus, job.Status)
+							assert.Equal(t, expectedModuleUUIDs, job.ModuleUUIDs)
+						}).
+						Return(nil),
+				)
+
+				assert.NoError(t, work.Do(context.Background()))
+			}).
+			Return(nil)
+	}
+
+	testCases := []struct {
+		name         string
+		mock         func()
+		content      []byte
+		fields       map[string]string
+		expectedCode int
+	}{
+		{
+			name:         "send not a package",
+			mock:         func() {},
+			content:      []byte("term,meaning"),
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "send package of the latest format only",
+			mock:         func() {},
+			content:      zipArchive(t, map[string]string{"collection.anki21b": "compressed", "media": ""}),
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "send too long field",
+			mock:         func() {},
+			content:      testPackage,
+			fields:       map[string]string{"term_field": strings.Repeat("f", 101)},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "every deck becomes a module",
+			mock: func() {
+				expectImport(entity.ImportStatusProcessed, []string{"animals-uuid", "verbs-uuid"}, func() {
+					importJobsRepo.EXPECT().
+						SaveImportJobErrors(gomock.Any(), "job-uuid", []*entity.ImportRowError{
+							{Line: 4, Reason: "term or meaning is empty", Raw: "vacío | "},
+						}).
+						Return(nil)
+
+					importJobsRepo.EXPECT().
+						GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+						Return(&testImportJob, nil)
+
+					modulesRepo.EXPECT().
+						CreateNewModulesWithCards(gomock.Any(), []*entity.ModuleWithCards{
+							{
+								Module: entity.Module{Name: "Spanish::Animals", UserUUID: "some-user-uuid"},
+								Cards: []*entity.Card{
+									{Term: "perro", Meaning: "dog"},
+									{Term: "gato", Meaning: "cat"},
+								},
+							},
+							{
+								Module: entity.Module{Name: "Verbs", UserUUID: "some-user-uuid"},
+								Cards: []*entity.Card{
+									{Term: "comer", Meaning: "to eat\nto have a meal"},
+								},
+							},
+						}).
+						Do(func(_ any, modulesWithCards []*entity.ModuleWithCards) {
+							modulesWithCards[0].UUID = "animals-uuid"
+							modulesWithCards[1].UUID = "verbs-uuid"
+						}).
+						Return(nil)
+				})
+			},
+			content:      testPackage,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "fields are mapped by names",
+			mock: func() {
+				expectImport(entity.ImportStatusProcessed, []string{"animals-uuid", "verbs-uuid"}, func() {
+					importJobsRepo.EXPECT().
+						SaveImportJobErrors(gomock.Any(), "job-uuid", gomock.Len(1)).
+						Return(nil)
+
+					importJobsRepo.EXPECT().
+						GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+						Return(&testImportJob, nil)
+
+					modulesRepo.EXPECT().
+						CreateNewModulesWithCards(gomock.Any(), gomock.Any()).
+						Do(func(_ any, modulesWithCards []*entity.ModuleWithCards) {
+							assert.Equal(t, &entity.Card{Term: "dog", Meaning: "perro"}, modulesWithCards[0].Cards[0])
+
+							modulesWithCards[0].UUID = "animals-uuid"
+							modulesWithCards[1].UUID = "verbs-uuid"
+						}).
+						Return(nil)
+				})
+			},
+			content:      testPackage,
+			fields:       map[string]string{"term_field": "back", "meaning_field": "Front"},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "unknown field fails job",
+			mock: func() {
+				expectImport(entity.ImportStatusFailed, nil, func() {
+					importJobsRepo.EXPECT().
+						SaveImportJobErrors(gomock.Any(), "job-uuid", gomock.Len(4)).
+						Return(nil)
+				})
+			},
+			content:      testPackage,
+			fields:       map[string]string{"term_field": "Word"},
+			expectedCode: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			body, headers := fileImportForm(t, "spanish.apkg", tc.content, tc.fields)
+
+			res, _ := testutils.SendTestRequest(t, ts, http.MethodPost, "/api/modules/import/apkg", body, headers)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+		})
+	}
+}
+
+//nolint:funlen
+func TestImportModuleFromXLSX(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	// vocabulary.xlsx is saved like Excel does: texts are shared strings, some of them are rich or phonetic.
+	// "Animals" sheet has cards without a header, a number term and a row without meaning,
+	// "Verbs" sheet has "Term" and "Meaning" header in the second and the third columns.
+	testWorkbook, err := os.ReadFile("testdata/vocabulary.xlsx")
+	require.NoError(t, err)
+
+	expectImport := func(expectedRowErrors []*entity.ImportRowError, expectedCards []*entity.Card) {
+		importJobsRepo.EXPECT().
+			CreateImportJob(gomock.Any(), &entity.ImportJob{
+				Source:     entity.ImportSourceXLSX,
+				ModuleName: "vocabulary",
+			}).
+			DoAndReturn(func(_ any, _ *entity.ImportJob) (*entity.ImportJob, error) {
+				job := testImportJob
+				job.Source = entity.ImportSourceXLSX
+
+				return &job, nil
+			})
+
+		xlsxImportWP.EXPECT().
+			QueueWork(gomock.Any()).
+			Do(func(work *usecase.XLSXImportWork) {
+				if len(expectedRowErrors) > 0 {
+					importJobsRepo.EXPECT().
+						SaveImportJobErrors(gomock.Any(), "job-uuid", expectedRowErrors).
+						Return(nil)
+				}
+
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(&testImportJob, nil)
+
+				modulesRepo.EXPECT().
+					CreateNewModuleWithCards(gomock.Any(), gomock.Any()).
+					Do(func(_ any, moduleWithCards *entity.ModuleWithCards) {
+						assert.Equal(t, "vocabulary", moduleWithCards.Name)
+						assert.Equal(t, expectedCards, moduleWithCards.Cards)
+					}).
+					Return(nil)
+
+				gomock.InOrder(
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Return(nil),
+					importJobsRepo.EXPECT().
+						UpdateImportJob(gomock.Any(), gomock.Any()).
+						Do(func(_ any, job *entity.ImportJob) {
+							assert.Equal(t, entity.ImportStatusProcessed, job.Status)
+							assert.Equal(t, len(expectedRowErrors), job.ErrorsCount)
+						}).
+						Return(nil),
+				)
+
+				assert.NoError(t, work.Do(context.Background()))
+			}).
+			Return(nil)
+	}
+
+	testCases := []struct {
+		name         string
+		mock         func()
+		content      []byte
+		fields       map[string]string
+		expectedCode int
+	}{
+		{
+			name:         "send not a workbook",
+			mock:         func() {},
+			content:      []byte("term,meaning"),
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "send unknown sheet",
+			mock:         func() {},
+			content:      testWorkbook,
+			fields:       map[string]string{"sheet": "Nouns"},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "send invalid header mode",
+			mock:         func() {},
+			content:      testWorkbook,
+			fields:       map[string]string{"header": "maybe"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "first sheet becomes module",
+			mock: func() {
+				expectImport(
+					[]*entity.ImportRowError{{Line: 4, Reason: "term or meaning column is missing", Raw: "vacío"}},
+					[]*entity.Card{
+						{Term: "perro", Meaning: "собака"},
+						{Term: "gato", Meaning: "кошка"},
+						{Term: "猫", Meaning: "cat"},
+						{Term: "42", Meaning: "сорок два"},
+					},
+				)
+			},
+			content:      testWorkbook,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "columns of selected sheet are mapped by header names",
+			mock: func() {
+				expectImport(nil, []*entity.Card{{Term: "есть\nкушать", Meaning: "comer"}})
+			},
+			content: testWorkbook,
+			fields: map[string]string{
+				"sheet":          "verbs",
+				"header":         "present",
+				"term_column":    "term",
+				"meaning_column": "Meaning",
+				"swap":           "true",
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "sheet is selected by number",
+			mock: func() {
+				expectImport(nil, []*entity.Card{{Term: "comer", Meaning: "есть\nкушать"}})
+			},
+			content:      testWorkbook,
+			fields:       map[string]string{"sheet": "2", "header": "auto", "term_column": "2", "meaning_column": "3"},
+			expectedCode: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			body, headers := fileImportForm(t, "vocabulary.xlsx", tc.content, tc.fields)
+
+			res, _ := testutils.SendTestRequest(t, ts, http.MethodPost, "/api/modules/import/xlsx", body, headers)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+		})
+	}
+}
+
+func TestGetImportJobs(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	failedJob := testImportJob
+	failedJob.UUID = "failed-job-uuid"
+	failedJob.Status = entity.ImportStatusFailed
+	failedJob.ErrorMessage = entity.ErrNoCardsToImport.Error()
+
+	testCases := []testCase{
+		{
+			name: "repo error",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJobs(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("boom"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "import jobs returned successfully",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJobs(gomock.Any(), gomock.Any()).
+					Return([]*entity.ImportJob{&testImportJob, &failedJob}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, []entity.ImportJob{testImportJob, failedJob}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, "/api/modules/import", nil, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestGetImportJob(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name: "import job not found",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(nil, &entity.ImportJobNotFoundError{UUID: "job-uuid"})
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "import job returned successfully",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(&testImportJob, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, testImportJob),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t,
+				ts,
+				http.MethodGet,
+				"/api/modules/import/job-uuid",
+				nil,
+				map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestCancelImportJob(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	canceledJob := testImportJob
+	canceledJob.Status = entity.ImportStatusCanceled
+
+	testCases := []testCase{
+		{
+			name: "import job not found",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					CancelImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(nil, &entity.ImportJobNotFoundError{UUID: "job-uuid"})
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "import job already finished",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					CancelImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(nil, entity.ErrImportJobFinished)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "import job canceled successfully",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					CancelImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(&canceledJob, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, canceledJob),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t,
+				ts,
+				http.MethodDelete,
+				"/api/modules/import/job-uuid",
+				nil,
+				map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestGetImportJobErrors(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	rowErrors := []*entity.ImportRowError{
+		{Line: 2, Reason: "term or meaning is empty", Raw: "dog,"},
+		{Line: 5, Reason: "term or meaning column is missing", Raw: "bird"},
+	}
+
+	testCases := []struct {
+		name         string
+		mock         func()
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "import job not found",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(nil, &entity.ImportJobNotFoundError{UUID: "job-uuid"})
+			},
+			path:         "/api/modules/import/job-uuid/errors",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "import job errors fetching failed",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(&testImportJob, nil)
+				importJobsRepo.EXPECT().
+					GetImportJobErrors(gomock.Any(), "job-uuid").
+					Return(nil, errors.New("boom"))
+			},
+			path:         "/api/modules/import/job-uuid/errors",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "import job errors fetched successfully",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(&testImportJob, nil)
+				importJobsRepo.EXPECT().
+					GetImportJobErrors(gomock.Any(), "job-uuid").
+					Return(rowErrors, nil)
+			},
+			path:         "/api/modules/import/job-uuid/errors",
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, rowErrors),
+		},
+		{
+			name: "import job errors report downloaded successfully",
+			mock: func() {
+				importJobsRepo.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any(), "job-uuid").
+					Return(&testImportJob, nil)
+				importJobsRepo.EXPECT().
+					GetImportJobErrors(gomock.Any(), "job-uuid").
+					Return(rowErrors, nil)
+			},
+			path:         "/api/modules/import/job-uuid/errors/csv",
+			expectedCode: http.StatusOK,
+			expectedBody: "line,reason,raw\n2,term or meaning is empty,\"dog,\"\n5,term or meaning column is missing,bird\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, tc.path, nil, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.Equal(t, strings.TrimSpace(tc.expectedBody), strings.TrimSpace(string(body)))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestPreviewImport(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	windows1251Content, err := charmap.Windows1251.NewEncoder().String("слово;перевод\r\nкот;кошка, cat\r\nсобака;\r\n")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		mock         func()
+		path         string
+		csvContent   string
+		csvFields    map[string]string
+		jsonBody     string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "send invalid limit",
+			mock:         func() {},
+			path:         "/api/modules/import/preview?limit=1000",
+			jsonBody:     `{"quizlet_module_id":"123"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "send invalid dialect",
+			mock:         func() {},
+			path:         "/api/modules/import/preview",
+			csvContent:   "cat,кот\n",
+			csvFields:    map[string]string{"encoding": "latin-1"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "send too large csv",
+			mock:         func() {},
+			path:         "/api/modules/import/preview",
+			csvContent:   strings.Repeat("cat,кот\n", 1<<17),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "csv with detected dialect",
+			mock:         func() {},
+			path:         "/api/modules/import/preview",
+			csvContent:   windows1251Content,
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.ImportPreview{
+				Source: entity.ImportSourceCSV,
+				Cards: []*entity.ImportPreviewCard{
+					{Term: "кот", Meaning: "кошка, cat"},
+				},
+				Dialect: &csvcards.Dialect{
+					Delimiter:     csvcards.DelimiterSemicolon,
+					Header:        csvcards.HeaderPresent,
+					TermColumn:    "1",
+					MeaningColumn: "2",
+					Encoding:      csvcards.EncodingWindows1251,
+				},
+				Warnings: []string{"line 3: term or meaning is empty"},
+			}),
+		},
+		{
+			name:         "csv with more cards than limit",
+			mock:         func() {},
+			path:         "/api/modules/import/preview?limit=1",
+			csvContent:   "cat\tкот\ndog\tсобака\n",
+			csvFields:    map[string]string{"swap": "true"},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.ImportPreview{
+				Source:  entity.ImportSourceCSV,
+				Cards:   []*entity.ImportPreviewCard{{Term: "кот", Meaning: "cat"}},
+				HasMore: true,
+				Dialect: &csvcards.Dialect{
+					Delimiter:     csvcards.DelimiterTab,
+					Header:        csvcards.HeaderNone,
+					TermColumn:    "1",
+					MeaningColumn: "2",
+					Swap:          true,
+					Encoding:      csvcards.EncodingUTF8,
+				},
+				Warnings: []string{},
+			}),
+		},
+		{
+			name:         "send quizlet preview without module id",
+			mock:         func() {},
+			path:         "/api/modules/import/preview",
+			jsonBody:     `{"quizlet_module_id":" "}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "quizlet module fetching failed",
+			mock: func() {
+				quizletModuleParser.EXPECT().
+					Parse(gomock.Any(), "123").
+					Return(nil, &quizlet.ModuleFetchingError{ID: "123"})
+			},
+			path:         "/api/modules/import/preview",
+			jsonBody:     `{"quizlet_module_id":"123"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "quizlet module previewed successfully",
+			mock: func() {
+				quizletModuleParser.EXPECT().
+					Parse(gomock.Any(), "123").
+					Return([]quizlet.Card{
+						{Front: "cat", Back: "кот"},
+						{Front: "dog", Back: "собака"},
+						{Front: "bird", Back: ""},
+					}, nil)
+			},
+			path:         "/api/modules/import/preview?limit=2",
+			jsonBody:     `{"quizlet_module_id":"123"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.ImportPreview{
+				Source: entity.ImportSourceQuizlet,
+				Cards: []*entity.ImportPreviewCard{
+					{Term: "cat", Meaning: "кот"},
+					{Term: "dog", Meaning: "собака"},
+				},
+				HasMore:  true,
+				Warnings: []string{"card 3: term or meaning is empty"},
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			var (
+				body    io.Reader = strings.NewReader(tc.jsonBody)
+				headers           = map[string]string{}
+			)
+
+			if tc.csvContent != "" {
+				body, headers = csvImportForm(t, tc.csvContent, tc.csvFields)
+			}
+
+			res, resBody := testutils.SendTestRequest(t, ts, http.MethodPost, tc.path, body, headers)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(resBody))
+			}
+		})
+	}
+}
+
+var testExportJob = entity.ExportJob{
+	UUID:      "export-job-uuid",
+	Format:    entity.ExportFormatCSV,
+	Status:    entity.ExportStatusCreated,
+	CreatedAt: time.Date(2024, time.December, 30, 10, 0, 0, 0, time.UTC),
+	UpdatedAt: time.Date(2024, time.December, 30, 10, 0, 0, 0, time.UTC),
+}
+
+func readZipFiles(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+
+	zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(zipReader.File))
+
+	for _, file := range zipReader.File {
+		fileReader, err := file.Open()
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(fileReader)
+		require.NoError(t, err)
+		require.NoError(t, fileReader.Close())
+
+		files[file.Name] = string(content)
+	}
+
+	return files
+}
+
+//nolint:funlen,maintidx
+func TestExportLibrary(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	firstModule := &entity.Module{UUID: "0b6f4f36-1c1e-4f8a-9a5e-5d1c3c2f6a10", Name: "Animals: pets/wild"}
+	secondModule := &entity.Module{UUID: "7d3e2a91-54b2-4c37-8e0f-2a6b9d1c4e22", Name: "animals: pets/wild"}
+	library := func() []*entity.Module {
+		return []*entity.Module{firstModule, secondModule}
+	}
+	selectedModules := func(moduleUUIDs []string) []*entity.Module {
+		if len(moduleUUIDs) == 0 {
+			return library()
+		}
+
+		return slices.DeleteFunc(library(), func(module *entity.Module) bool {
+			return !slices.Contains(moduleUUIDs, module.UUID)
+		})
+	}
+
+	expectJobStatuses := func(statuses ...string) {
+		calls := make([]any, 0, len(statuses))
+
+		for _, status := range statuses {
+			calls = append(calls, exportJobsRepo.EXPECT().
+				UpdateExportJob(gomock.Any(), gomock.Any()).
+				Do(func(_ any, job *entity.ExportJob) {
+					assert.Equal(t, status, job.Status)
+				}).
+				Return(nil))
+		}
+
+		gomock.InOrder(calls...)
+	}
+
+	expectExport := func(format string, moduleUUIDs []string, doWork func()) {
+		modulesRepo.EXPECT().
+			GetLibraryModules(gomock.Any(), gomock.Any(), moduleUUIDs).
+			Return(selectedModules(moduleUUIDs), nil)
+
+		exportJobsRepo.EXPECT().
+			CreateExportJob(gomock.Any(), &entity.ExportJob{Format: format, ModuleUUIDs: moduleUUIDs}).
+			DoAndReturn(func(_ any, _ *entity.ExportJob) (*entity.ExportJob, error) {
+				job := testExportJob
+				job.Format = format
+				job.ModuleUUIDs = moduleUUIDs
+
+				return &job, nil
+			})
+
+		libraryExportWP.EXPECT().
+			QueueWork(gomock.Any()).
+			Do(func(work *usecase.LibraryExportWork) {
+				doWork()
+
+				assert.NoError(t, work.Do(context.Background()))
+			}).
+			Return(nil)
+	}
+
+	type exportTestCase struct {
+		testCase
+		expectedLocation string
+	}
+
+	testCases := []exportTestCase{
+		{
+			testCase: testCase{
+				name:         "send unknown format",
+				mock:         func() {},
+				body:         strings.NewReader(`{"format":"pdf"}`),
+				expectedCode: http.StatusBadRequest,
+			},
+		},
+		{
+			testCase: testCase{
+				name:         "send invalid module uuid",
+				mock:         func() {},
+				body:         strings.NewReader(`{"format":"csv","module_uuids":["module"]}`),
+				expectedCode: http.StatusBadRequest,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "selected module not found",
+				mock: func() {
+					modulesRepo.EXPECT().
+						GetLibraryModules(gomock.Any(), gomock.Any(), []string{"9c1d6a3e-2f4b-4e8d-b7a1-3e5f7c9d0b33"}).
+						Return([]*entity.Module{}, nil)
+				},
+				body:         strings.NewReader(`{"format":"csv","module_uuids":["9c1d6a3e-2f4b-4e8d-b7a1-3e5f7c9d0b33"]}`),
+				expectedCode: http.StatusNotFound,
+				expectedBody: "module with uuid=\"9c1d6a3e-2f4b-4e8d-b7a1-3e5f7c9d0b33\" does not exist\n",
+			},
+		},
+		{
+			testCase: testCase{
+				name: "empty library",
+				mock: func() {
+					modulesRepo.EXPECT().
+						GetLibraryModules(gomock.Any(), gomock.Any(), nil).
+						Return([]*entity.Module{}, nil)
+				},
+				body:         strings.NewReader(`{"format":"csv"}`),
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedBody: "no modules to export\n",
+			},
+		},
+		{
+			testCase: testCase{
+				name: "queue error fails job",
+				mock: func() {
+					modulesRepo.EXPECT().
+						GetLibraryModules(gomock.Any(), gomock.Any(), nil).
+						Return(library(), nil)
+
+					exportJobsRepo.EXPECT().
+						CreateExportJob(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ any, _ *entity.ExportJob) (*entity.ExportJob, error) {
+							job := testExportJob
+
+							return &job, nil
+						})
+
+					libraryExportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Return(errors.New("boom"))
+
+					expectJobStatuses(entity.ExportStatusFailed)
+				},
+				body:         strings.NewReader(`{"format":"csv"}`),
+				expectedCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "whole library to csv",
+				mock: func() {
+					expectExport(entity.ExportFormatCSV, nil, func() {
+						expectJobStatuses(entity.ExportStatusProcessing)
+
+						modulesRepo.EXPECT().
+							GetLibraryModules(gomock.Any(), gomock.Any(), nil).
+							Return(library(), nil)
+
+						cardsRepo.EXPECT().
+							GetModuleCards(gomock.Any(), firstModule.UUID).
+							Return([]*entity.Card{{Term: "cat", Meaning: "кошка"}, {Term: "dog", Meaning: "собака"}}, nil)
+
+						cardsRepo.EXPECT().
+							GetModuleCards(gomock.Any(), secondModule.UUID).
+							Return([]*entity.Card{{Term: "wolf", Meaning: "волк, \"серый\""}}, nil)
+
+						exportJobsRepo.EXPECT().
+							SaveExportArchive(gomock.Any(), gomock.Any(), gomock.Any()).
+							Do(func(_ any, job *entity.ExportJob, archive []byte) {
+								assert.Equal(t, entity.ExportStatusProcessed, job.Status)
+								assert.Equal(t, 2, job.ModulesCount)
+								assert.Equal(t, 3, job.CardsCount)
+								assert.Equal(t, int64(len(archive)), job.ArchiveSize)
+								require.NotNil(t, job.ExpiresAt)
+								assert.WithinDuration(t, time.Now().Add(24*time.Hour), *job.ExpiresAt, time.Minute)
+
+								assert.Equal(t, map[string]string{
+									"Animals_ pets_wild.csv":     "cat,кошка\ndog,собака\n",
+									"animals_ pets_wild (2).csv": "wolf,\"волк, \"\"серый\"\"\"\n",
+								}, readZipFiles(t, archive))
+							}).
+							Return(nil)
+					})
+				},
+				body:         strings.NewReader(`{"format":"csv"}`),
+				expectedCode: http.StatusAccepted,
+				expectedBody: testutils.ToJSON(t, testExportJob),
+			},
+			expectedLocation: "/api/modules/export/export-job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "selected module to json",
+				mock: func() {
+					expectExport(entity.ExportFormatJSON, []string{secondModule.UUID}, func() {
+						expectJobStatuses(entity.ExportStatusProcessing)
+
+						modulesRepo.EXPECT().
+							GetLibraryModules(gomock.Any(), gomock.Any(), []string{secondModule.UUID}).
+							Return([]*entity.Module{secondModule}, nil)
+
+						modulesRepo.EXPECT().
+							GetArchivedModule(gomock.Any(), gomock.Any(), secondModule.UUID).
+							Return(&testArchivedModule, nil)
+
+						exportJobsRepo.EXPECT().
+							SaveExportArchive(gomock.Any(), gomock.Any(), gomock.Any()).
+							Do(func(_ any, job *entity.ExportJob, archive []byte) {
+								assert.Equal(t, 1, job.ModulesCount)
+								assert.Equal(t, len(testArchivedModule.Cards), job.CardsCount)
+
+								files := readZipFiles(t, archive)
+								require.Contains(t, files, "animals_ pets_wild.json")
+
+								var moduleArchive entity.ModuleArchive
+
+								require.NoError(t, json.Unmarshal([]byte(files["animals_ pets_wild.json"]), &moduleArchive))
+								assert.Equal(t, entity.ModuleArchiveFormat, moduleArchive.Format)
+								assert.Equal(t, &testArchivedModule, moduleArchive.Module)
+							}).
+							Return(nil)
+					})
+				},
+				body: strings.NewReader(
+					`{"format":"json","module_uuids":["7d3e2a91-54b2-4c37-8e0f-2a6b9d1c4e22"]}`,
+				),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/export/export-job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "job fails when selected modules have been deleted",
+				mock: func() {
+					expectExport(entity.ExportFormatXLSX, []string{firstModule.UUID}, func() {
+						expectJobStatuses(entity.ExportStatusProcessing, entity.ExportStatusFailed)
+
+						modulesRepo.EXPECT().
+							GetLibraryModules(gomock.Any(), gomock.Any(), []string{firstModule.UUID}).
+							Return([]*entity.Module{}, nil)
+					})
+				},
+				body: strings.NewReader(
+					`{"format":"xlsx","module_uuids":["0b6f4f36-1c1e-4f8a-9a5e-5d1c3c2f6a10"]}`,
+				),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/export/export-job-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "storing error is returned to retry the work",
+				mock: func() {
+					modulesRepo.EXPECT().
+						GetLibraryModules(gomock.Any(), gomock.Any(), nil).
+						Return(library(), nil)
+
+					exportJobsRepo.EXPECT().
+						CreateExportJob(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ any, _ *entity.ExportJob) (*entity.ExportJob, error) {
+							job := testExportJob
+							job.Format = entity.ExportFormatXLSX
+
+							return &job, nil
+						})
+
+					libraryExportWP.EXPECT().
+						QueueWork(gomock.Any()).
+						Do(func(work *usecase.LibraryExportWork) {
+							expectJobStatuses(entity.ExportStatusProcessing)
+
+							modulesRepo.EXPECT().
+								GetLibraryModules(gomock.Any(), gomock.Any(), nil).
+								Return([]*entity.Module{firstModule}, nil)
+
+							cardsRepo.EXPECT().
+								GetModuleCards(gomock.Any(), firstModule.UUID).
+								Return([]*entity.Card{&testCard}, nil)
+
+							exportJobsRepo.EXPECT().
+								SaveExportArchive(gomock.Any(), gomock.Any(), gomock.Any()).
+								Return(errors.New("boom"))
+
+							assert.Error(t, work.Do(context.Background()))
+						}).
+						Return(nil)
+				},
+				body:         strings.NewReader(`{"format":"xlsx"}`),
+				expectedCode: http.StatusAccepted,
+			},
+			expectedLocation: "/api/modules/export/export-job-uuid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodPost, "/api/modules/export", tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+			assert.Equal(t, tc.expectedLocation, res.Header.Get("Location"))
+
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestGetExportJob(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	processedJob := testExportJob
+	processedJob.Status = entity.ExportStatusProcessed
+	processedJob.ModulesCount = 2
+	processedJob.CardsCount = 3
+	processedJob.ArchiveSize = 512
+	expiresAt := time.Date(2024, time.December, 31, 10, 0, 0, 0, time.UTC)
+	processedJob.ExpiresAt = &expiresAt
+
+	testCases := []testCase{
+		{
+			name: "job not found",
+			mock: func() {
+				exportJobsRepo.EXPECT().
+					GetExportJob(gomock.Any(), gomock.Any(), "export-job-uuid").
+					Return(nil, &entity.ExportJobNotFoundError{UUID: "export-job-uuid"})
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "processed job",
+			mock: func() {
+				exportJobsRepo.EXPECT().
+					GetExportJob(gomock.Any(), gomock.Any(), "export-job-uuid").
+					Return(&processedJob, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, processedJob),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodGet, "/api/modules/export/export-job-uuid", http.NoBody, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestDownloadExportArchive(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	ts := prepareTestServer(
+		t,
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+	)
+
+	defer ts.Close()
+
+	jobExpiringAt := func(status string, expiresAt time.Time) *entity.ExportJob {
+		job := testExportJob
+		job.Status = status
+		job.ExpiresAt = &expiresAt
+
+		return &job
+	}
+
+	processingJob := testExportJob
+	processingJob.Status = entity.ExportStatusProcessing
+
+	testArchive := []byte("PK zip archive")
+
+	type downloadTestCase struct {
+		testCase
+		expectedDisposition string
+	}
+
+	testCases := []downloadTestCase{
+		{
+			testCase: testCase{
+				name: "job not found",
+				mock: func() {
+					exportJobsRepo.EXPECT().
+						GetExportJob(gomock.Any(), gomock.Any(), "export-job-uuid").
+						Return(nil, &entity.ExportJobNotFoundError{UUID: "export-job-uuid"})
+				},
+				expectedCode: http.StatusNotFound,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "job is still processing",
+				mock: func() {
+					exportJobsRepo.EXPECT().
+						GetExportJob(gomock.Any(), gomock.Any(), "export-job-uuid").
+						Return(&processingJob, nil)
+				},
+				expectedCode: http.StatusConflict,
+				expectedBody: "export archive is not ready yet\n",
+			},
+		},
+		{
+			testCase: testCase{
+				name: "archive has expired",
+				mock: func() {
+					exportJobsRepo.EXPECT().
+						GetExportJob(gomock.Any(), gomock.Any(), "export-job-uuid").
+						Return(jobExpiringAt(entity.ExportStatusProcessed, time.Now().Add(-time.Minute)), nil)
+				},
+				expectedCode: http.StatusGone,
+				expectedBody: "export archive has expired\n",
+			},
+		},
+		{
+			testCase: testCase{
+				name: "archive has been removed",
+				mock: func() {
+					exportJobsRepo.EXPECT().
+						GetExportJob(gomock.Any(), gomock.Any(), "export-job-uuid").
+						Return(jobExpiringAt(entity.ExportStatusProcessed, time.Now().Add(time.Minute)), nil)
+
+					exportJobsRepo.EXPECT().
+						GetExportArchive(gomock.Any(), gomock.Any(), "export-job-uuid").
+						Return(nil, entity.ErrExportArchiveExpired)
+				},
+				expectedCode: http.StatusGone,
+			},
+		},
+		{
+			testCase: testCase{
+				name: "archive is downloaded",
+				mock: func() {
+					exportJobsRepo.EXPECT().
+						GetExportJob(gomock.Any(), gomock.Any(), "export-job-uuid").
+						Return(jobExpiringAt(entity.ExportStatusProcessed, time.Now().Add(time.Hour)), nil)
+
+					exportJobsRepo.EXPECT().
+						GetExportArchive(gomock.Any(), gomock.Any(), "export-job-uuid").
+						Return(testArchive, nil)
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: string(testArchive),
+			},
+			expectedDisposition: "attachment; filename=\"modules-csv-2024-12-30.zip\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodGet, "/api/modules/export/export-job-uuid/download", http.NoBody, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+			assert.Equal(t, tc.expectedDisposition, res.Header.Get("Content-Disposition"))
+
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package review
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	httpCommon "github.com/llravell/simple-cards/internal/controller/http"
+	"github.com/llravell/simple-cards/internal/controller/http/middleware"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultDueCardsLimit = 50
+	maxDueCardsLimit     = 500
+)
+
+type Routes struct {
+	log       zerolog.Logger
+	modulesUC httpCommon.ModulesUseCase
+	reviewUC  httpCommon.ReviewUseCase
+}
+
+func NewRoutes(
+	modulesUC httpCommon.ModulesUseCase,
+	reviewUC httpCommon.ReviewUseCase,
+	log zerolog.Logger,
+) *Routes {
+	return &Routes{
+		log:       log,
+		modulesUC: modulesUC,
+		reviewUC:  reviewUC,
+	}
+}
+
+func (routes *Routes) checkModuleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isModuleExists, err := routes.modulesUC.ModuleExists(
+			r.Context(),
+			middleware.GetUserUUIDFromRequest(r),
+			r.PathValue("module_uuid"),
+		)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			routes.log.Error().Err(err).Msg("module checking failed")
+
+			return
+		}
+
+		if !isModuleExists {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (routes *Routes) jsonResponse(w http.ResponseWriter, v any) {
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		routes.log.Err(err).Msg("response write has been failed")
+	}
+}
+
+func parseLimit(r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultDueCardsLimit, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxDueCardsLimit {
+		return 0, false
+	}
+
+	return limit, true
+}
+
+// Swagger spec:
+// @Summary      Get cards due for review from all user's modules
+// @Description  Returns overdue cards first, then cards which have never been reviewed
+// @Security     UsersAuth
+// @Tags         review
+// @Produce      json
+// @Param        limit query int false "Max cards amount, 50 by default, 500 at most"
+// @Success      200  {array}  entity.DueCard
+// @Failure      400
+// @Failure      500
+// @Router       /api/review/due [get]
+func (routes *Routes) getDueCards(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(r)
+	if !ok {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+
+		return
+	}
+
+	cards, err := routes.reviewUC.GetDueCards(r.Context(), middleware.GetUserUUIDFromRequest(r), limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("due cards fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, cards)
+}
+
+// Swagger spec:
+// @Summary      Get module's cards due for review
+// @Description  Returns overdue cards first, then cards which have never been reviewed
+// @Security     UsersAuth
+// @Tags         review
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Param        limit query int false "Max cards amount, 50 by default, 500 at most"
+// @Success      200  {array}  entity.DueCard
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/due [get]
+func (routes *Routes) getModuleDueCards(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(r)
+	if !ok {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+
+		return
+	}
+
+	cards, err := routes.reviewUC.GetModuleDueCards(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+		limit,
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("module due cards fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, cards)
+}
+
+func (routes *Routes) Apply(r chi.Router) {
+	r.Get("/api/review/due", routes.getDueCards)
+	r.With(routes.checkModuleMiddleware).Get("/api/modules/{module_uuid}/due", routes.getModuleDueCards)
+}
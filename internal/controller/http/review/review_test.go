@@ -0,0 +1,247 @@
+package review_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	testutils "github.com/llravell/simple-cards/internal"
+	"github.com/llravell/simple-cards/internal/controller/http/review"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/mocks"
+	"github.com/llravell/simple-cards/internal/usecase"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testCase struct {
+	name         string
+	mock         func()
+	path         string
+	body         io.Reader
+	expectedCode int
+	expectedBody string
+}
+
+var testDueCard = entity.DueCard{
+	Card: entity.Card{
+		UUID:       "card-uuid",
+		Term:       "term",
+		Meaning:    "meaning",
+		ModuleUUID: "module-uuid",
+	},
+	ReviewState: &entity.ReviewState{
+		CardUUID:       "card-uuid",
+		ModuleUUID:     "module-uuid",
+		EaseFactor:     2.5,
+		IntervalDays:   1,
+		Repetitions:    1,
+		DueAt:          time.Date(2024, time.December, 15, 10, 0, 0, 0, time.UTC),
+		LastReviewedAt: time.Date(2024, time.December, 14, 10, 0, 0, 0, time.UTC),
+	},
+}
+
+var testNewCard = entity.DueCard{
+	Card: entity.Card{
+		UUID:       "new-card-uuid",
+		Term:       "new term",
+		Meaning:    "new meaning",
+		ModuleUUID: "module-uuid",
+	},
+}
+
+func prepareTestServer(
+	t *testing.T,
+	modulesRepo usecase.ModulesRepository,
+	reviewRepo usecase.ReviewRepository,
+) *httptest.Server {
+	t.Helper()
+
+	log := zerolog.Nop()
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	modulesUseCase := usecase.NewModulesUseCase(
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+		usecase.NewRunningImports(),
+		usecase.NewJobEvents(mocks.NewMockJobEventsNotifier(gomock.NewController(t)), &log),
+		&log,
+	)
+	reviewUseCase := usecase.NewReviewUseCase(reviewRepo)
+	router := chi.NewRouter()
+	routes := review.NewRoutes(modulesUseCase, reviewUseCase, log)
+
+	routes.Apply(router)
+
+	return httptest.NewServer(router)
+}
+
+//nolint:funlen
+func TestGetDueCards(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, reviewRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name:         "send invalid limit",
+			mock:         func() {},
+			path:         "/api/review/due?limit=abc",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "send too big limit",
+			mock:         func() {},
+			path:         "/api/review/due?limit=501",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "repo error",
+			mock: func() {
+				reviewRepo.EXPECT().
+					GetDueCards(gomock.Any(), gomock.Any(), "", gomock.Any(), 50).
+					Return(nil, errors.New("boom"))
+			},
+			path:         "/api/review/due",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "due cards returned successfully",
+			mock: func() {
+				reviewRepo.EXPECT().
+					GetDueCards(gomock.Any(), gomock.Any(), "", gomock.Any(), 10).
+					Return([]*entity.DueCard{&testDueCard, &testNewCard}, nil)
+			},
+			path:         "/api/review/due?limit=10",
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, []entity.DueCard{testDueCard, testNewCard}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, tc.path, tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestGetModuleDueCards(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, reviewRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name: "module checking error",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(false, errors.New("boom"))
+			},
+			path:         "/api/modules/module-uuid/due",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "module checking failed",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(false, nil)
+			},
+			path:         "/api/modules/module-uuid/due",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "send invalid limit",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(true, nil)
+			},
+			path:         "/api/modules/module-uuid/due?limit=0",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "repo error",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(true, nil)
+
+				reviewRepo.EXPECT().
+					GetDueCards(gomock.Any(), gomock.Any(), "module-uuid", gomock.Any(), 50).
+					Return(nil, errors.New("boom"))
+			},
+			path:         "/api/modules/module-uuid/due",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "due cards returned successfully",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(true, nil)
+
+				reviewRepo.EXPECT().
+					GetDueCards(gomock.Any(), gomock.Any(), "module-uuid", gomock.Any(), 50).
+					Return([]*entity.DueCard{&testDueCard}, nil)
+			},
+			path:         "/api/modules/module-uuid/due",
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, []entity.DueCard{testDueCard}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, tc.path, tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	httpCommon "github.com/llravell/simple-cards/internal/controller/http"
+	"github.com/llravell/simple-cards/internal/controller/http/middleware"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/rs/zerolog"
+)
+
+type Routes struct {
+	log        zerolog.Logger
+	modulesUC  httpCommon.ModulesUseCase
+	sessionsUC httpCommon.SessionsUseCase
+}
+
+func NewRoutes(
+	modulesUC httpCommon.ModulesUseCase,
+	sessionsUC httpCommon.SessionsUseCase,
+	log zerolog.Logger,
+) *Routes {
+	return &Routes{
+		log:        log,
+		modulesUC:  modulesUC,
+		sessionsUC: sessionsUC,
+	}
+}
+
+func (routes *Routes) checkModuleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isModuleExists, err := routes.modulesUC.ModuleExists(
+			r.Context(),
+			middleware.GetUserUUIDFromRequest(r),
+			r.PathValue("module_uuid"),
+		)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			routes.log.Error().Err(err).Msg("module checking failed")
+
+			return
+		}
+
+		if !isModuleExists {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (routes *Routes) jsonResponse(w http.ResponseWriter, v any) {
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		routes.log.Err(err).Msg("response write has been failed")
+	}
+}
+
+// Swagger spec:
+// @Summary      Get module's study sessions
+// @Description  Returns the latest sessions first, only finished sessions have a summary
+// @Security     UsersAuth
+// @Tags         sessions
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Success      200  {array}  entity.StudySession
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/sessions/ [get]
+func (routes *Routes) getSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := routes.sessionsUC.GetModuleSessions(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("sessions fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, sessions)
+}
+
+// Swagger spec:
+// @Summary      Start study session
+// @Description  Answers linked to the session are included in its summary
+// @Security     UsersAuth
+// @Tags         sessions
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Success      201  {object}  entity.StudySession
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/sessions/ [post]
+func (routes *Routes) startSession(w http.ResponseWriter, r *http.Request) {
+	session, err := routes.sessionsUC.StartSession(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("session starting failed")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	routes.jsonResponse(w, session)
+}
+
+// Swagger spec:
+// @Summary      Finish study session
+// @Description  Returns the session with its summary
+// @Security     UsersAuth
+// @Tags         sessions
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Param        session_uuid path string true "Session UUID"
+// @Success      200  {object}  entity.StudySession
+// @Failure      404
+// @Failure      409
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/sessions/{session_uuid}/finish [post]
+func (routes *Routes) finishSession(w http.ResponseWriter, r *http.Request) {
+	session, err := routes.sessionsUC.FinishSession(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+		r.PathValue("session_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.SessionNotFoundError
+
+		switch {
+		case errors.As(err, &notFoundErr):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, entity.ErrSessionFinished):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("session finishing failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, session)
+}
+
+func (routes *Routes) Apply(r chi.Router) {
+	r.Route("/api/modules/{module_uuid}/sessions", func(r chi.Router) {
+		r.Use(routes.checkModuleMiddleware)
+
+		r.Get("/", routes.getSessions)
+		r.Post("/", routes.startSession)
+		r.Post("/{session_uuid}/finish", routes.finishSession)
+	})
+}
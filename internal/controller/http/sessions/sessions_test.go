@@ -0,0 +1,302 @@
+package sessions_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	testutils "github.com/llravell/simple-cards/internal"
+	"github.com/llravell/simple-cards/internal/controller/http/sessions"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/mocks"
+	"github.com/llravell/simple-cards/internal/usecase"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testCase struct {
+	name         string
+	mock         func()
+	path         string
+	body         io.Reader
+	expectedCode int
+	expectedBody string
+}
+
+var (
+	testStartedAt  = time.Date(2024, time.December, 19, 10, 0, 0, 0, time.UTC)
+	testFinishedAt = testStartedAt.Add(5 * time.Minute)
+)
+
+func prepareTestServer(
+	t *testing.T,
+	modulesRepo usecase.ModulesRepository,
+	sessionsRepo usecase.SessionsRepository,
+) *httptest.Server {
+	t.Helper()
+
+	log := zerolog.Nop()
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	modulesUseCase := usecase.NewModulesUseCase(
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+		usecase.NewRunningImports(),
+		usecase.NewJobEvents(mocks.NewMockJobEventsNotifier(gomock.NewController(t)), &log),
+		&log,
+	)
+	sessionsUseCase := usecase.NewSessionsUseCase(sessionsRepo)
+	router := chi.NewRouter()
+	routes := sessions.NewRoutes(modulesUseCase, sessionsUseCase, log)
+
+	routes.Apply(router)
+
+	return httptest.NewServer(router)
+}
+
+func expectModuleExists(modulesRepo *mocks.MockModulesRepository) {
+	modulesRepo.EXPECT().
+		ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+		Return(true, nil)
+}
+
+//nolint:funlen
+func TestStartSession(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	sessionsRepo := mocks.NewMockSessionsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, sessionsRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name: "module not found",
+			mock: func() {
+				modulesRepo.EXPECT().
+					ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(false, nil)
+			},
+			path:         "/api/modules/module-uuid/sessions",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "repo error",
+			mock: func() {
+				expectModuleExists(modulesRepo)
+
+				sessionsRepo.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(nil, errors.New("boom"))
+			},
+			path:         "/api/modules/module-uuid/sessions",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "session started successfully",
+			mock: func() {
+				expectModuleExists(modulesRepo)
+
+				sessionsRepo.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(&entity.StudySession{
+						UUID:       "session-uuid",
+						ModuleUUID: "module-uuid",
+						StartedAt:  testStartedAt,
+					}, nil)
+			},
+			path:         "/api/modules/module-uuid/sessions",
+			expectedCode: http.StatusCreated,
+			expectedBody: testutils.ToJSON(t, entity.StudySession{
+				UUID:       "session-uuid",
+				ModuleUUID: "module-uuid",
+				StartedAt:  testStartedAt,
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodPost, tc.path, tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestFinishSession(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	sessionsRepo := mocks.NewMockSessionsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, sessionsRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name: "session not found",
+			mock: func() {
+				expectModuleExists(modulesRepo)
+
+				sessionsRepo.EXPECT().
+					FinishSession(gomock.Any(), gomock.Any(), "module-uuid", "session-uuid").
+					Return(nil, &entity.SessionNotFoundError{UUID: "session-uuid"})
+			},
+			path:         "/api/modules/module-uuid/sessions/session-uuid/finish",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "session already finished",
+			mock: func() {
+				expectModuleExists(modulesRepo)
+
+				sessionsRepo.EXPECT().
+					FinishSession(gomock.Any(), gomock.Any(), "module-uuid", "session-uuid").
+					Return(nil, entity.ErrSessionFinished)
+			},
+			path:         "/api/modules/module-uuid/sessions/session-uuid/finish",
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "session finished successfully",
+			mock: func() {
+				expectModuleExists(modulesRepo)
+
+				sessionsRepo.EXPECT().
+					FinishSession(gomock.Any(), gomock.Any(), "module-uuid", "session-uuid").
+					Return(&entity.StudySession{
+						UUID:       "session-uuid",
+						ModuleUUID: "module-uuid",
+						StartedAt:  testStartedAt,
+						FinishedAt: &testFinishedAt,
+						Summary: &entity.SessionSummary{
+							CardsSeen:     3,
+							AnswersCount:  4,
+							CorrectCount:  3,
+							PromotedCount: 2,
+							DemotedCount:  1,
+						},
+					}, nil)
+			},
+			path:         "/api/modules/module-uuid/sessions/session-uuid/finish",
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.StudySession{
+				UUID:       "session-uuid",
+				ModuleUUID: "module-uuid",
+				StartedAt:  testStartedAt,
+				FinishedAt: &testFinishedAt,
+				Summary: &entity.SessionSummary{
+					CardsSeen:     3,
+					AnswersCount:  4,
+					CorrectCount:  3,
+					Accuracy:      0.75,
+					TimeSpentMs:   300000,
+					PromotedCount: 2,
+					DemotedCount:  1,
+				},
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodPost, tc.path, tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	sessionsRepo := mocks.NewMockSessionsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, sessionsRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name: "repo error",
+			mock: func() {
+				expectModuleExists(modulesRepo)
+
+				sessionsRepo.EXPECT().
+					GetModuleSessions(gomock.Any(), gomock.Any(), "module-uuid").
+					Return(nil, errors.New("boom"))
+			},
+			path:         "/api/modules/module-uuid/sessions",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "active session has no summary",
+			mock: func() {
+				expectModuleExists(modulesRepo)
+
+				sessionsRepo.EXPECT().
+					GetModuleSessions(gomock.Any(), gomock.Any(), "module-uuid").
+					Return([]*entity.StudySession{
+						{UUID: "session-uuid", ModuleUUID: "module-uuid", StartedAt: testStartedAt},
+					}, nil)
+			},
+			path:         "/api/modules/module-uuid/sessions",
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, []entity.StudySession{
+				{UUID: "session-uuid", ModuleUUID: "module-uuid", StartedAt: testStartedAt},
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, tc.path, tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
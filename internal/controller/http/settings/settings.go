@@ -0,0 +1,224 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	httpCommon "github.com/llravell/simple-cards/internal/controller/http"
+	"github.com/llravell/simple-cards/internal/controller/http/middleware"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/entity/dto"
+	"github.com/rs/zerolog"
+)
+
+type Routes struct {
+	log        zerolog.Logger
+	settingsUC httpCommon.SettingsUseCase
+	validator  *validator.Validate
+}
+
+func NewRoutes(settingsUC httpCommon.SettingsUseCase, log zerolog.Logger) *Routes {
+	return &Routes{
+		log:        log,
+		settingsUC: settingsUC,
+		validator:  validator.New(),
+	}
+}
+
+func (routes *Routes) jsonResponse(w http.ResponseWriter, v any) {
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		routes.log.Err(err).Msg("response write has been failed")
+	}
+}
+
+// Swagger spec:
+// @Summary      Get user's settings
+// @Security     UsersAuth
+// @Tags         settings
+// @Produce      json
+// @Success      200  {object}  entity.UserSettings
+// @Failure      500
+// @Router       /api/user/settings [get]
+func (routes *Routes) getUserSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := routes.settingsUC.GetUserSettings(r.Context(), middleware.GetUserUUIDFromRequest(r))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("user settings fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, settings)
+}
+
+// Swagger spec:
+// @Summary      Update user's settings
+// @Description  Only passed settings are updated
+// @Security     UsersAuth
+// @Tags         settings
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.UpdateUserSettingsRequest true "Settings params"
+// @Success      200  {object}  entity.UserSettings
+// @Failure      400
+// @Failure      500
+// @Router       /api/user/settings [put]
+func (routes *Routes) updateUserSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateUserSettingsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	userUUID := middleware.GetUserUUIDFromRequest(r)
+
+	settings, err := routes.settingsUC.GetUserSettings(r.Context(), userUUID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("user settings fetching failed")
+
+		return
+	}
+
+	if req.Scheduler != nil {
+		settings.Scheduler = *req.Scheduler
+	}
+
+	if req.DesiredRetention != nil {
+		settings.DesiredRetention = *req.DesiredRetention
+	}
+
+	if req.Timezone != nil {
+		settings.Timezone = *req.Timezone
+	}
+
+	if req.DailyGoalType != nil {
+		settings.DailyGoalType = *req.DailyGoalType
+	}
+
+	if req.DailyGoalValue != nil {
+		settings.DailyGoalValue = *req.DailyGoalValue
+	}
+
+	if req.LeechThreshold != nil {
+		settings.LeechThreshold = *req.LeechThreshold
+	}
+
+	settings, err = routes.settingsUC.SaveUserSettings(r.Context(), userUUID, settings)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("user settings saving failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, settings)
+}
+
+// Swagger spec:
+// @Summary      Get module's settings
+// @Security     UsersAuth
+// @Tags         settings
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Success      200  {object}  entity.ModuleSettings
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/settings [get]
+func (routes *Routes) getModuleSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := routes.settingsUC.GetModuleSettings(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module settings fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, settings)
+}
+
+// Swagger spec:
+// @Summary      Update module's settings
+// @Description  Empty scheduler means that the user's one is used
+// @Security     UsersAuth
+// @Tags         settings
+// @Accept       json
+// @Produce      json
+// @Param        module_uuid path string true "Module UUID"
+// @Param        request body dto.UpdateModuleSettingsRequest true "Settings params"
+// @Success      200  {object}  entity.ModuleSettings
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/modules/{module_uuid}/settings [put]
+func (routes *Routes) updateModuleSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateModuleSettingsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	req.Scheduler = strings.TrimSpace(req.Scheduler)
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	settings, err := routes.settingsUC.SaveModuleSettings(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		r.PathValue("module_uuid"),
+		&entity.ModuleSettings{Scheduler: req.Scheduler},
+	)
+	if err != nil {
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		routes.log.Error().Err(err).Msg("module settings saving failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, settings)
+}
+
+func (routes *Routes) Apply(r chi.Router) {
+	r.Get("/api/user/settings", routes.getUserSettings)
+	r.Put("/api/user/settings", routes.updateUserSettings)
+
+	r.Get("/api/modules/{module_uuid}/settings", routes.getModuleSettings)
+	r.Put("/api/modules/{module_uuid}/settings", routes.updateModuleSettings)
+}
@@ -0,0 +1,311 @@
+package settings_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	testutils "github.com/llravell/simple-cards/internal"
+	"github.com/llravell/simple-cards/internal/controller/http/settings"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/mocks"
+	"github.com/llravell/simple-cards/internal/usecase"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testCase struct {
+	name         string
+	mock         func()
+	body         io.Reader
+	expectedCode int
+	expectedBody string
+}
+
+var testUserSettings = entity.UserSettings{
+	Scheduler:        entity.DefaultScheduler,
+	DesiredRetention: entity.DefaultDesiredRetention,
+	Timezone:         entity.DefaultTimezone,
+	DailyGoalType:    entity.DefaultDailyGoalType,
+	DailyGoalValue:   entity.DefaultDailyGoalValue,
+	LeechThreshold:   entity.DefaultLeechThreshold,
+}
+
+func prepareTestServer(t *testing.T, settingsRepo usecase.SettingsRepository) *httptest.Server {
+	t.Helper()
+
+	log := zerolog.Nop()
+	settingsUseCase := usecase.NewSettingsUseCase(settingsRepo)
+	router := chi.NewRouter()
+	routes := settings.NewRoutes(settingsUseCase, log)
+
+	routes.Apply(router)
+
+	return httptest.NewServer(router)
+}
+
+func TestGetUserSettings(t *testing.T) {
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, settingsRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name: "settings fetching error",
+			mock: func() {
+				settingsRepo.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("boom"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "settings fetched successfully",
+			mock: func() {
+				settingsRepo.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Any()).
+					Return(&testUserSettings, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, testUserSettings),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodGet, "/api/user/settings", tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestUpdateUserSettings(t *testing.T) {
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, settingsRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name:         "unexpected format",
+			mock:         func() {},
+			body:         strings.NewReader("not json"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown scheduler",
+			mock:         func() {},
+			body:         strings.NewReader(`{"scheduler":"leitner"}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "desired retention out of range",
+			mock:         func() {},
+			body:         strings.NewReader(`{"desired_retention":1}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown timezone",
+			mock:         func() {},
+			body:         strings.NewReader(`{"timezone":"Mars/Olympus"}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown daily goal type",
+			mock:         func() {},
+			body:         strings.NewReader(`{"daily_goal_type":"pages"}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "leech threshold out of range",
+			mock:         func() {},
+			body:         strings.NewReader(`{"leech_threshold":0}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "settings saving error",
+			mock: func() {
+				settingsRepo.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Any()).
+					Return(&entity.UserSettings{
+						Scheduler:        entity.DefaultScheduler,
+						DesiredRetention: entity.DefaultDesiredRetention,
+					}, nil)
+
+				settingsRepo.EXPECT().
+					SaveUserSettings(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("boom"))
+			},
+			body:         strings.NewReader(`{"scheduler":"fsrs"}`),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "daily goal is updated",
+			mock: func() {
+				settingsRepo.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Any()).
+					Return(&entity.UserSettings{
+						Scheduler:        entity.DefaultScheduler,
+						DesiredRetention: entity.DefaultDesiredRetention,
+						Timezone:         entity.DefaultTimezone,
+						DailyGoalType:    entity.DefaultDailyGoalType,
+						DailyGoalValue:   entity.DefaultDailyGoalValue,
+					}, nil)
+
+				settingsRepo.EXPECT().
+					SaveUserSettings(gomock.Any(), gomock.Any(), &entity.UserSettings{
+						Scheduler:        entity.DefaultScheduler,
+						DesiredRetention: entity.DefaultDesiredRetention,
+						Timezone:         "Europe/Moscow",
+						DailyGoalType:    entity.DailyGoalMinutes,
+						DailyGoalValue:   15,
+					}).
+					DoAndReturn(func(_ any, _ string, settings *entity.UserSettings) (*entity.UserSettings, error) {
+						return settings, nil
+					})
+			},
+			body: strings.NewReader(
+				`{"timezone":"Europe/Moscow","daily_goal_type":"minutes","daily_goal_value":15}`,
+			),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.UserSettings{
+				Scheduler:        entity.DefaultScheduler,
+				DesiredRetention: entity.DefaultDesiredRetention,
+				Timezone:         "Europe/Moscow",
+				DailyGoalType:    entity.DailyGoalMinutes,
+				DailyGoalValue:   15,
+			}),
+		},
+		{
+			name: "only passed settings are updated",
+			mock: func() {
+				settingsRepo.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Any()).
+					Return(&entity.UserSettings{
+						Scheduler:        entity.DefaultScheduler,
+						DesiredRetention: entity.DefaultDesiredRetention,
+					}, nil)
+
+				settingsRepo.EXPECT().
+					SaveUserSettings(gomock.Any(), gomock.Any(), &entity.UserSettings{
+						Scheduler:        entity.SchedulerFSRS,
+						DesiredRetention: entity.DefaultDesiredRetention,
+					}).
+					DoAndReturn(func(_ any, _ string, settings *entity.UserSettings) (*entity.UserSettings, error) {
+						return settings, nil
+					})
+			},
+			body:         strings.NewReader(`{"scheduler":"fsrs"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.UserSettings{
+				Scheduler:        entity.SchedulerFSRS,
+				DesiredRetention: entity.DefaultDesiredRetention,
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodPut, "/api/user/settings", tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestUpdateModuleSettings(t *testing.T) {
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, settingsRepo)
+
+	defer ts.Close()
+
+	testCases := []testCase{
+		{
+			name:         "unknown scheduler",
+			mock:         func() {},
+			body:         strings.NewReader(`{"scheduler":"leitner"}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "module not found",
+			mock: func() {
+				settingsRepo.EXPECT().
+					SaveModuleSettings(gomock.Any(), gomock.Any(), "module-uuid", gomock.Any()).
+					Return(nil, &entity.ModuleNotFoundError{UUID: "module-uuid"})
+			},
+			body:         strings.NewReader(`{"scheduler":"fsrs"}`),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "scheduler reset to user's one",
+			mock: func() {
+				settingsRepo.EXPECT().
+					SaveModuleSettings(gomock.Any(), gomock.Any(), "module-uuid", &entity.ModuleSettings{}).
+					Return(&entity.ModuleSettings{}, nil)
+			},
+			body:         strings.NewReader(`{"scheduler":""}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.ModuleSettings{}),
+		},
+		{
+			name: "scheduler saved successfully",
+			mock: func() {
+				settingsRepo.EXPECT().
+					SaveModuleSettings(gomock.Any(), gomock.Any(), "module-uuid", &entity.ModuleSettings{
+						Scheduler: entity.SchedulerFSRS,
+					}).
+					Return(&entity.ModuleSettings{Scheduler: entity.SchedulerFSRS}, nil)
+			},
+			body:         strings.NewReader(`{"scheduler":"fsrs"}`),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.ModuleSettings{Scheduler: entity.SchedulerFSRS}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodPut, "/api/modules/module-uuid/settings", tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
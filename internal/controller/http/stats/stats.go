@@ -0,0 +1,381 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	httpCommon "github.com/llravell/simple-cards/internal/controller/http"
+	"github.com/llravell/simple-cards/internal/controller/http/middleware"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/entity/dto"
+	"github.com/rs/zerolog"
+)
+
+type Routes struct {
+	log       zerolog.Logger
+	modulesUC httpCommon.ModulesUseCase
+	statsUC   httpCommon.StatsUseCase
+	validator *validator.Validate
+}
+
+func NewRoutes(
+	modulesUC httpCommon.ModulesUseCase,
+	statsUC httpCommon.StatsUseCase,
+	log zerolog.Logger,
+) *Routes {
+	return &Routes{
+		log:       log,
+		modulesUC: modulesUC,
+		statsUC:   statsUC,
+		validator: validator.New(),
+	}
+}
+
+// answerGrade returns explicit answer grade or the default one for answer correctness.
+func answerGrade(req dto.AnswerRequest) int {
+	if req.Grade != nil {
+		return *req.Grade
+	}
+
+	if req.IsCorrect {
+		return entity.DefaultCorrectAnswerGrade
+	}
+
+	return entity.DefaultIncorrectAnswerGrade
+}
+
+// parseTimeParam accepts both a date and a RFC3339 timestamp.
+// A date used as the period end includes the whole day.
+func parseTimeParam(value string, isPeriodEnd bool) (time.Time, error) {
+	date, err := time.Parse(time.DateOnly, value)
+	if err == nil {
+		if isPeriodEnd {
+			return date.AddDate(0, 0, 1), nil
+		}
+
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func newAnswer(req dto.AnswerRequest) *entity.Answer {
+	grade := answerGrade(req)
+
+	return &entity.Answer{
+		CardUUID:       strings.TrimSpace(req.CardUUID),
+		ModuleUUID:     strings.TrimSpace(req.ModuleUUID),
+		SessionUUID:    strings.TrimSpace(req.SessionUUID),
+		IsCorrect:      grade >= entity.PassingAnswerGrade,
+		Grade:          grade,
+		ResponseTimeMs: req.ResponseTimeMs,
+		AnsweredAt:     req.AnsweredAt,
+	}
+}
+
+func (routes *Routes) jsonResponse(w http.ResponseWriter, v any) {
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		routes.log.Err(err).Msg("response write has been failed")
+	}
+}
+
+// Swagger spec:
+// @Summary      Save user's answers
+// @Description  Explicit grade from 0 to 5 overrides is_correct, grade 3 and higher is a correct answer.
+// @Description  Answers may be linked to an active study session of the card's module.
+// @Security     UsersAuth
+// @Tags         stats
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.SaveAnswersRequest true "Answers batch"
+// @Success      201  {array}  entity.Answer
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/stats/ [post]
+func (routes *Routes) saveAnswers(w http.ResponseWriter, r *http.Request) {
+	var req dto.SaveAnswersRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	answers := make([]*entity.Answer, 0, len(req.Answers))
+
+	for _, answerReq := range req.Answers {
+		answers = append(answers, newAnswer(answerReq))
+	}
+
+	err := routes.statsUC.SaveAnswers(r.Context(), middleware.GetUserUUIDFromRequest(r), answers)
+	if err != nil {
+		routes.answersSavingError(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	routes.jsonResponse(w, answers)
+}
+
+// Swagger spec:
+// @Summary      Sync answers of an offline client
+// @Description  Every answer has a client generated ID, answers with already uploaded IDs are reported
+// @Description  as duplicates and skipped, so a failed batch can be retried. Answers are applied to review
+// @Description  states in chronological order of their client timestamps, even if they are older than
+// @Description  answers uploaded before.
+// @Security     UsersAuth
+// @Tags         stats
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.SyncAnswersRequest true "Answers batch"
+// @Success      200  {object}  entity.SyncResult
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/stats/sync [post]
+func (routes *Routes) syncAnswers(w http.ResponseWriter, r *http.Request) {
+	var req dto.SyncAnswersRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err := routes.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	answers := make([]*entity.Answer, 0, len(req.Answers))
+
+	for _, answerReq := range req.Answers {
+		answer := newAnswer(answerReq.AnswerRequest)
+		answer.ClientID = strings.TrimSpace(answerReq.ClientID)
+
+		answers = append(answers, answer)
+	}
+
+	result, err := routes.statsUC.SyncAnswers(r.Context(), middleware.GetUserUUIDFromRequest(r), answers)
+	if err != nil {
+		routes.answersSavingError(w, err)
+
+		return
+	}
+
+	routes.jsonResponse(w, result)
+}
+
+func (routes *Routes) answersSavingError(w http.ResponseWriter, err error) {
+	var (
+		cardNotFoundErr    *entity.CardNotFoundError
+		sessionNotFoundErr *entity.SessionNotFoundError
+	)
+
+	if errors.As(err, &cardNotFoundErr) || errors.As(err, &sessionNotFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	routes.log.Error().Err(err).Msg("answers saving failed")
+}
+
+// Swagger spec:
+// @Summary      Get user's module stats
+// @Security     UsersAuth
+// @Tags         stats
+// @Produce      json
+// @Param        module_id query string true "Module UUID"
+// @Param        from query string false "Period start, date or RFC3339 timestamp"
+// @Param        to query string false "Period end, date or RFC3339 timestamp"
+// @Success      200  {array}  entity.CardStats
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       /api/stats/ [get]
+func (routes *Routes) getModuleStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	moduleUUID := strings.TrimSpace(query.Get("module_id"))
+
+	if moduleUUID == "" {
+		http.Error(w, "module_id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	var (
+		from time.Time
+		to   = time.Now()
+		err  error
+	)
+
+	if value := query.Get("from"); value != "" {
+		from, err = parseTimeParam(value, false)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		to, err = parseTimeParam(value, true)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+
+		return
+	}
+
+	userUUID := middleware.GetUserUUIDFromRequest(r)
+
+	isModuleExists, err := routes.modulesUC.ModuleExists(r.Context(), userUUID, moduleUUID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("module checking failed")
+
+		return
+	}
+
+	if !isModuleExists {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	stats, err := routes.statsUC.GetModuleStats(r.Context(), userUUID, moduleUUID, from, to)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("module stats fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, stats)
+}
+
+// Swagger spec:
+// @Summary      Get user's daily review activity
+// @Description  Returns days with answers from all modules, the last year by default
+// @Security     UsersAuth
+// @Tags         stats
+// @Produce      json
+// @Param        from query string false "Period start date"
+// @Param        to query string false "Period end date, inclusive"
+// @Param        tz query string false "IANA time zone of day boundaries, the user's one by default"
+// @Success      200  {array}  entity.DailyActivity
+// @Failure      400
+// @Failure      500
+// @Router       /api/stats/activity [get]
+func (routes *Routes) getActivity(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	timezone := strings.TrimSpace(query.Get("tz"))
+
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil || strings.EqualFold(timezone, "local") {
+			http.Error(w, "invalid tz", http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	var (
+		from time.Time
+		to   time.Time
+		err  error
+	)
+
+	if value := query.Get("from"); value != "" {
+		from, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		to, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		http.Error(w, "from must not be after to", http.StatusBadRequest)
+
+		return
+	}
+
+	activity, err := routes.statsUC.GetActivity(
+		r.Context(),
+		middleware.GetUserUUIDFromRequest(r),
+		timezone,
+		from,
+		to,
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("activity fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, activity)
+}
+
+// Swagger spec:
+// @Summary      Get user's study streak
+// @Description  Days are counted in the user's time zone, progress is measured in daily goal units
+// @Security     UsersAuth
+// @Tags         stats
+// @Produce      json
+// @Success      200  {object}  entity.StudyStreak
+// @Failure      500
+// @Router       /api/user/streak [get]
+func (routes *Routes) getStreak(w http.ResponseWriter, r *http.Request) {
+	streak, err := routes.statsUC.GetStreak(r.Context(), middleware.GetUserUUIDFromRequest(r))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		routes.log.Error().Err(err).Msg("streak fetching failed")
+
+		return
+	}
+
+	routes.jsonResponse(w, streak)
+}
+
+func (routes *Routes) Apply(r chi.Router) {
+	r.Route("/api/stats", func(r chi.Router) {
+		r.Get("/", routes.getModuleStats)
+		r.Post("/", routes.saveAnswers)
+		r.Post("/sync", routes.syncAnswers)
+		r.Get("/activity", routes.getActivity)
+	})
+
+	r.Get("/api/user/streak", routes.getStreak)
+}
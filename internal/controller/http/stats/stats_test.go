@@ -0,0 +1,1044 @@
+package stats_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	testutils "github.com/llravell/simple-cards/internal"
+	"github.com/llravell/simple-cards/internal/controller/http/stats"
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/internal/mocks"
+	"github.com/llravell/simple-cards/internal/usecase"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testCase struct {
+	name         string
+	mock         func()
+	body         io.Reader
+	expectedCode int
+	expectedBody string
+}
+
+var testAnsweredAt = time.Date(2024, time.December, 14, 10, 0, 0, 0, time.UTC)
+
+func prepareTestServer(
+	t *testing.T,
+	modulesRepo usecase.ModulesRepository,
+	statsRepo usecase.StatsRepository,
+	reviewRepo usecase.ReviewRepository,
+	settingsRepo usecase.SettingsRepository,
+) *httptest.Server {
+	t.Helper()
+
+	log := zerolog.Nop()
+	cardsRepo := mocks.NewMockCardsRepository(gomock.NewController(t))
+	quizletModuleParser := mocks.NewMockQuizletModuleParser(gomock.NewController(t))
+	importJobsRepo := mocks.NewMockImportJobsRepository(gomock.NewController(t))
+	exportJobsRepo := mocks.NewMockExportJobsRepository(gomock.NewController(t))
+	quizletImportWP := mocks.NewMockQuizletImportWorkerPool(gomock.NewController(t))
+	csvImportWP := mocks.NewMockCSVImportWorkerPool(gomock.NewController(t))
+	apkgImportWP := mocks.NewMockAPKGImportWorkerPool(gomock.NewController(t))
+	xlsxImportWP := mocks.NewMockXLSXImportWorkerPool(gomock.NewController(t))
+	libraryExportWP := mocks.NewMockLibraryExportWorkerPool(gomock.NewController(t))
+
+	modulesUseCase := usecase.NewModulesUseCase(
+		modulesRepo,
+		cardsRepo,
+		reviewRepo,
+		importJobsRepo,
+		exportJobsRepo,
+		quizletModuleParser,
+		quizletImportWP,
+		csvImportWP,
+		apkgImportWP,
+		xlsxImportWP,
+		libraryExportWP,
+		usecase.NewRunningImports(),
+		usecase.NewJobEvents(mocks.NewMockJobEventsNotifier(gomock.NewController(t)), &log),
+		&log,
+	)
+	statsUseCase := usecase.NewStatsUseCase(statsRepo, reviewRepo, settingsRepo)
+	router := chi.NewRouter()
+	routes := stats.NewRoutes(modulesUseCase, statsUseCase, log)
+
+	routes.Apply(router)
+
+	return httptest.NewServer(router)
+}
+
+func answersBody(t *testing.T, answers ...map[string]any) io.Reader {
+	t.Helper()
+
+	return strings.NewReader(testutils.ToJSON(t, map[string]any{
+		"answers": answers,
+	}))
+}
+
+//nolint:funlen
+func TestSaveAnswers(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	statsRepo := mocks.NewMockStatsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, statsRepo, reviewRepo, settingsRepo)
+
+	defer ts.Close()
+
+	validAnswer := map[string]any{
+		"card_uuid":        "card-uuid",
+		"module_uuid":      "module-uuid",
+		"is_correct":       true,
+		"response_time_ms": 1500,
+		"answered_at":      testAnsweredAt,
+	}
+
+	expectSchedulers := func(moduleSchedulers map[string]string) {
+		settingsRepo.EXPECT().
+			GetUserSettings(gomock.Any(), gomock.Any()).
+			Return(&entity.UserSettings{
+				Scheduler:        entity.DefaultScheduler,
+				DesiredRetention: entity.DefaultDesiredRetention,
+				LeechThreshold:   entity.DefaultLeechThreshold,
+			}, nil)
+
+		settingsRepo.EXPECT().
+			GetModuleSchedulers(gomock.Any(), gomock.Any(), []string{"module-uuid"}).
+			Return(moduleSchedulers, nil)
+	}
+
+	testCases := []testCase{
+		{
+			name:         "unexpected format",
+			mock:         func() {},
+			body:         strings.NewReader("not json"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "send empty answers",
+			mock:         func() {},
+			body:         answersBody(t),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "send answer without card",
+			mock: func() {},
+			body: answersBody(t, map[string]any{
+				"module_uuid": "module-uuid",
+				"is_correct":  true,
+				"answered_at": testAnsweredAt,
+			}),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "send answer with grade out of range",
+			mock: func() {},
+			body: answersBody(t, map[string]any{
+				"card_uuid":   "card-uuid",
+				"module_uuid": "module-uuid",
+				"grade":       6,
+				"answered_at": testAnsweredAt,
+			}),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "send answer with negative response time",
+			mock: func() {},
+			body: answersBody(t, map[string]any{
+				"card_uuid":        "card-uuid",
+				"module_uuid":      "module-uuid",
+				"response_time_ms": -1,
+				"answered_at":      testAnsweredAt,
+			}),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "card not found",
+			mock: func() {
+				expectSchedulers(map[string]string{})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, &entity.CardNotFoundError{UUID: "card-uuid"})
+			},
+			body:         answersBody(t, validAnswer),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "session not found",
+			mock: func() {
+				expectSchedulers(map[string]string{})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, &entity.SessionNotFoundError{UUID: "session-uuid"})
+			},
+			body: answersBody(t, map[string]any{
+				"card_uuid":    "card-uuid",
+				"module_uuid":  "module-uuid",
+				"session_uuid": "session-uuid",
+				"is_correct":   true,
+				"answered_at":  testAnsweredAt,
+			}),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "repo error",
+			mock: func() {
+				expectSchedulers(map[string]string{})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("boom"))
+			},
+			body:         answersBody(t, validAnswer),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "settings fetching error",
+			mock: func() {
+				settingsRepo.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("boom"))
+			},
+			body:         answersBody(t, validAnswer),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "review states fetching error",
+			mock: func() {
+				expectSchedulers(map[string]string{})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(nil, errors.New("boom"))
+			},
+			body:         answersBody(t, validAnswer),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "saved successfully",
+			mock: func() {
+				expectSchedulers(map[string]string{})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), []*entity.ReviewState{
+						{
+							CardUUID:       "card-uuid",
+							ModuleUUID:     "module-uuid",
+							EaseFactor:     2.5,
+							IntervalDays:   1,
+							Repetitions:    1,
+							DueAt:          testAnsweredAt.AddDate(0, 0, 1),
+							LastReviewedAt: testAnsweredAt,
+						},
+					}).
+					DoAndReturn(func(_ any, _ string, answers []*entity.Answer, _ []*entity.ReviewState) ([]string, error) {
+						answers[0].UUID = "answer-uuid"
+
+						return nil, nil
+					})
+			},
+			body:         answersBody(t, validAnswer),
+			expectedCode: http.StatusCreated,
+			expectedBody: testutils.ToJSON(t, []entity.Answer{
+				{
+					UUID:           "answer-uuid",
+					CardUUID:       "card-uuid",
+					ModuleUUID:     "module-uuid",
+					IsCorrect:      true,
+					Grade:          entity.DefaultCorrectAnswerGrade,
+					ResponseTimeMs: 1500,
+					IntervalBefore: 0,
+					IntervalAfter:  1,
+					AnsweredAt:     testAnsweredAt,
+				},
+			}),
+		},
+		{
+			name: "explicit grade overrides correctness",
+			mock: func() {
+				expectSchedulers(map[string]string{})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{
+						"card-uuid": {
+							CardUUID:       "card-uuid",
+							ModuleUUID:     "module-uuid",
+							EaseFactor:     2.5,
+							IntervalDays:   6,
+							Repetitions:    2,
+							DueAt:          testAnsweredAt,
+							LastReviewedAt: testAnsweredAt.AddDate(0, 0, -6),
+						},
+					}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), []*entity.ReviewState{
+						{
+							CardUUID:       "card-uuid",
+							ModuleUUID:     "module-uuid",
+							EaseFactor:     1.96,
+							IntervalDays:   1,
+							Lapses:         1,
+							DueAt:          testAnsweredAt.AddDate(0, 0, 1),
+							LastReviewedAt: testAnsweredAt,
+						},
+					}).
+					Return(nil, nil)
+			},
+			body: answersBody(t, map[string]any{
+				"card_uuid":   "card-uuid",
+				"module_uuid": "module-uuid",
+				"is_correct":  true,
+				"grade":       1,
+				"answered_at": testAnsweredAt,
+			}),
+			expectedCode: http.StatusCreated,
+			expectedBody: testutils.ToJSON(t, []entity.Answer{
+				{
+					CardUUID:       "card-uuid",
+					ModuleUUID:     "module-uuid",
+					Grade:          1,
+					IntervalBefore: 6,
+					IntervalAfter:  1,
+					AnsweredAt:     testAnsweredAt,
+				},
+			}),
+		},
+		{
+			name: "card becomes leech after too many lapses",
+			mock: func() {
+				expectSchedulers(map[string]string{})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{
+						"card-uuid": {
+							CardUUID:       "card-uuid",
+							ModuleUUID:     "module-uuid",
+							EaseFactor:     2.5,
+							IntervalDays:   6,
+							Repetitions:    2,
+							Lapses:         entity.DefaultLeechThreshold - 1,
+							DueAt:          testAnsweredAt,
+							LastReviewedAt: testAnsweredAt.AddDate(0, 0, -6),
+						},
+					}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), []*entity.ReviewState{
+						{
+							CardUUID:       "card-uuid",
+							ModuleUUID:     "module-uuid",
+							EaseFactor:     1.96,
+							IntervalDays:   1,
+							Lapses:         entity.DefaultLeechThreshold,
+							DueAt:          testAnsweredAt.AddDate(0, 0, 1),
+							LastReviewedAt: testAnsweredAt,
+							IsLeech:        true,
+						},
+					}).
+					Return(nil, nil)
+			},
+			body: answersBody(t, map[string]any{
+				"card_uuid":   "card-uuid",
+				"module_uuid": "module-uuid",
+				"grade":       1,
+				"answered_at": testAnsweredAt,
+			}),
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "module scheduler overrides user's one",
+			mock: func() {
+				expectSchedulers(map[string]string{"module-uuid": entity.SchedulerFSRS})
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), []*entity.ReviewState{
+						{
+							CardUUID:       "card-uuid",
+							ModuleUUID:     "module-uuid",
+							Stability:      3.7145,
+							Difficulty:     5.1618,
+							IntervalDays:   4,
+							Repetitions:    1,
+							DueAt:          testAnsweredAt.AddDate(0, 0, 4),
+							LastReviewedAt: testAnsweredAt,
+						},
+					}).
+					Return(nil, nil)
+			},
+			body:         answersBody(t, validAnswer),
+			expectedCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodPost, "/api/stats", tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestGetModuleStats(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	statsRepo := mocks.NewMockStatsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, statsRepo, reviewRepo, settingsRepo)
+
+	defer ts.Close()
+
+	from := time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC)
+
+	answeredCardStats := entity.CardStats{
+		CardUUID:       "card-uuid",
+		Term:           "term",
+		Meaning:        "meaning",
+		CorrectCount:   3,
+		IncorrectCount: 1,
+		LastReviewedAt: &testAnsweredAt,
+		Streak:         2,
+	}
+	newCardStats := entity.CardStats{
+		CardUUID: "new-card-uuid",
+		Term:     "new term",
+		Meaning:  "new meaning",
+	}
+
+	testCases := []struct {
+		testCase
+		query string
+	}{
+		{
+			testCase: testCase{
+				name:         "send without module",
+				mock:         func() {},
+				expectedCode: http.StatusBadRequest,
+			},
+			query: "",
+		},
+		{
+			testCase: testCase{
+				name:         "send invalid period",
+				mock:         func() {},
+				expectedCode: http.StatusBadRequest,
+			},
+			query: "?module_id=module-uuid&from=yesterday",
+		},
+		{
+			testCase: testCase{
+				name:         "send reversed period",
+				mock:         func() {},
+				expectedCode: http.StatusBadRequest,
+			},
+			query: "?module_id=module-uuid&from=2024-12-15&to=2024-12-01",
+		},
+		{
+			testCase: testCase{
+				name: "module checking error",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(false, errors.New("boom"))
+				},
+				expectedCode: http.StatusInternalServerError,
+			},
+			query: "?module_id=module-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "module checking failed",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(false, nil)
+				},
+				expectedCode: http.StatusNotFound,
+			},
+			query: "?module_id=module-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "repo error",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					statsRepo.EXPECT().
+						GetModuleStats(gomock.Any(), gomock.Any(), "module-uuid", gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("boom"))
+				},
+				expectedCode: http.StatusInternalServerError,
+			},
+			query: "?module_id=module-uuid",
+		},
+		{
+			testCase: testCase{
+				name: "stats returned successfully",
+				mock: func() {
+					modulesRepo.EXPECT().
+						ModuleExists(gomock.Any(), gomock.Any(), "module-uuid").
+						Return(true, nil)
+
+					statsRepo.EXPECT().
+						GetModuleStats(gomock.Any(), gomock.Any(), "module-uuid", from, to).
+						Return([]*entity.CardStats{&answeredCardStats, &newCardStats}, nil)
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: testutils.ToJSON(t, []entity.CardStats{
+					{
+						CardUUID:       "card-uuid",
+						Term:           "term",
+						Meaning:        "meaning",
+						CorrectCount:   3,
+						IncorrectCount: 1,
+						Accuracy:       0.75,
+						LastReviewedAt: &testAnsweredAt,
+						Streak:         2,
+					},
+					newCardStats,
+				}),
+			},
+			query: "?module_id=module-uuid&from=2024-12-01&to=2024-12-14",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodGet,
+				"/api/stats"+tc.query, tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestGetStreak(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	statsRepo := mocks.NewMockStatsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, statsRepo, reviewRepo, settingsRepo)
+
+	defer ts.Close()
+
+	daysAgo := func(days int) string {
+		return time.Now().UTC().AddDate(0, 0, -days).Format(time.DateOnly)
+	}
+
+	expectSettings := func(goalType string, goalValue int) {
+		settingsRepo.EXPECT().
+			GetUserSettings(gomock.Any(), gomock.Any()).
+			Return(&entity.UserSettings{
+				Scheduler:        entity.DefaultScheduler,
+				DesiredRetention: entity.DefaultDesiredRetention,
+				Timezone:         "UTC",
+				DailyGoalType:    goalType,
+				DailyGoalValue:   goalValue,
+			}, nil)
+	}
+
+	testCases := []testCase{
+		{
+			name: "activity fetching error",
+			mock: func() {
+				expectSettings(entity.DailyGoalCards, 20)
+
+				statsRepo.EXPECT().
+					GetDailyActivity(gomock.Any(), gomock.Any(), "UTC", gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("boom"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "streak continues until today",
+			mock: func() {
+				expectSettings(entity.DailyGoalCards, 20)
+
+				statsRepo.EXPECT().
+					GetDailyActivity(gomock.Any(), gomock.Any(), "UTC", gomock.Any(), gomock.Any()).
+					Return([]*entity.DailyActivity{
+						{Date: daysAgo(10), AnswersCount: 5},
+						{Date: daysAgo(9), AnswersCount: 5},
+						{Date: daysAgo(8), AnswersCount: 5},
+						{Date: daysAgo(7), AnswersCount: 5},
+						{Date: daysAgo(1), AnswersCount: 30},
+						{Date: daysAgo(0), AnswersCount: 25},
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.StudyStreak{
+				CurrentStreak:  2,
+				LongestStreak:  4,
+				Timezone:       "UTC",
+				DailyGoalType:  entity.DailyGoalCards,
+				DailyGoalValue: 20,
+				TodayProgress:  25,
+				GoalReached:    true,
+			}),
+		},
+		{
+			name: "streak is kept while today is not studied yet",
+			mock: func() {
+				expectSettings(entity.DailyGoalMinutes, 10)
+
+				statsRepo.EXPECT().
+					GetDailyActivity(gomock.Any(), gomock.Any(), "UTC", gomock.Any(), gomock.Any()).
+					Return([]*entity.DailyActivity{
+						{Date: daysAgo(2), AnswersCount: 5, TimeSpentMs: 60000},
+						{Date: daysAgo(1), AnswersCount: 5, TimeSpentMs: 60000},
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.StudyStreak{
+				CurrentStreak:  2,
+				LongestStreak:  2,
+				Timezone:       "UTC",
+				DailyGoalType:  entity.DailyGoalMinutes,
+				DailyGoalValue: 10,
+			}),
+		},
+		{
+			name: "streak is lost after a missed day",
+			mock: func() {
+				expectSettings(entity.DailyGoalMinutes, 10)
+
+				statsRepo.EXPECT().
+					GetDailyActivity(gomock.Any(), gomock.Any(), "UTC", gomock.Any(), gomock.Any()).
+					Return([]*entity.DailyActivity{
+						{Date: daysAgo(3), AnswersCount: 5, TimeSpentMs: 60000},
+						{Date: daysAgo(2), AnswersCount: 5, TimeSpentMs: 60000},
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.StudyStreak{
+				LongestStreak:  2,
+				Timezone:       "UTC",
+				DailyGoalType:  entity.DailyGoalMinutes,
+				DailyGoalValue: 10,
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodGet, "/api/user/streak", tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestGetActivity(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	statsRepo := mocks.NewMockStatsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, statsRepo, reviewRepo, settingsRepo)
+
+	defer ts.Close()
+
+	activity := []*entity.DailyActivity{
+		{Date: "2024-12-19", AnswersCount: 4, CorrectCount: 3, Accuracy: 0.75, TimeSpentMs: 12000},
+		{Date: "2024-12-21", AnswersCount: 1, CorrectCount: 1, Accuracy: 1, TimeSpentMs: 3000},
+	}
+
+	testCases := []struct {
+		testCase
+		query string
+	}{
+		{
+			testCase: testCase{
+				name:         "invalid time zone",
+				mock:         func() {},
+				expectedCode: http.StatusBadRequest,
+			},
+			query: "?tz=Mars/Olympus",
+		},
+		{
+			testCase: testCase{
+				name:         "invalid date",
+				mock:         func() {},
+				expectedCode: http.StatusBadRequest,
+			},
+			query: "?from=yesterday",
+		},
+		{
+			testCase: testCase{
+				name:         "period start after end",
+				mock:         func() {},
+				expectedCode: http.StatusBadRequest,
+			},
+			query: "?from=2024-12-21&to=2024-12-01",
+		},
+		{
+			testCase: testCase{
+				name: "repo error",
+				mock: func() {
+					statsRepo.EXPECT().
+						GetDailyActivity(gomock.Any(), gomock.Any(), "UTC", gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("boom"))
+				},
+				expectedCode: http.StatusInternalServerError,
+			},
+			query: "?tz=UTC",
+		},
+		{
+			testCase: testCase{
+				name: "days are bounded in passed time zone",
+				mock: func() {
+					statsRepo.EXPECT().
+						GetDailyActivity(gomock.Any(), gomock.Any(), "Europe/Moscow", gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ any, _ string, _ string, from time.Time, to time.Time) ([]*entity.DailyActivity, error) {
+							assert.True(t, from.Equal(time.Date(2023, time.December, 31, 21, 0, 0, 0, time.UTC)))
+							assert.True(t, to.Equal(time.Date(2024, time.December, 31, 21, 0, 0, 0, time.UTC)))
+
+							return activity, nil
+						})
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: testutils.ToJSON(t, activity),
+			},
+			query: "?from=2024-01-01&to=2024-12-31&tz=Europe/Moscow",
+		},
+		{
+			testCase: testCase{
+				name: "user's time zone is used by default",
+				mock: func() {
+					settingsRepo.EXPECT().
+						GetUserSettings(gomock.Any(), gomock.Any()).
+						Return(&entity.UserSettings{
+							Scheduler:        entity.DefaultScheduler,
+							DesiredRetention: entity.DefaultDesiredRetention,
+							Timezone:         "Asia/Tokyo",
+							DailyGoalType:    entity.DefaultDailyGoalType,
+							DailyGoalValue:   entity.DefaultDailyGoalValue,
+						}, nil)
+
+					statsRepo.EXPECT().
+						GetDailyActivity(gomock.Any(), gomock.Any(), "Asia/Tokyo", gomock.Any(), gomock.Any()).
+						Return(activity, nil)
+				},
+				expectedCode: http.StatusOK,
+				expectedBody: testutils.ToJSON(t, activity),
+			},
+			query: "?from=2024-12-01&to=2024-12-31",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(
+				t, ts, http.MethodGet,
+				"/api/stats/activity"+tc.query, tc.body, map[string]string{},
+			)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+//nolint:funlen,maintidx
+func TestSyncAnswers(t *testing.T) {
+	modulesRepo := mocks.NewMockModulesRepository(gomock.NewController(t))
+	statsRepo := mocks.NewMockStatsRepository(gomock.NewController(t))
+	reviewRepo := mocks.NewMockReviewRepository(gomock.NewController(t))
+	settingsRepo := mocks.NewMockSettingsRepository(gomock.NewController(t))
+
+	ts := prepareTestServer(t, modulesRepo, statsRepo, reviewRepo, settingsRepo)
+
+	defer ts.Close()
+
+	syncedAnswer := func(clientID string, answeredAt time.Time) map[string]any {
+		return map[string]any{
+			"client_id":   clientID,
+			"card_uuid":   "card-uuid",
+			"module_uuid": "module-uuid",
+			"is_correct":  true,
+			"answered_at": answeredAt,
+		}
+	}
+
+	expectSchedulers := func() {
+		settingsRepo.EXPECT().
+			GetUserSettings(gomock.Any(), gomock.Any()).
+			Return(&entity.UserSettings{
+				Scheduler:        entity.DefaultScheduler,
+				DesiredRetention: entity.DefaultDesiredRetention,
+				LeechThreshold:   entity.DefaultLeechThreshold,
+			}, nil)
+
+		settingsRepo.EXPECT().
+			GetModuleSchedulers(gomock.Any(), gomock.Any(), []string{"module-uuid"}).
+			Return(map[string]string{}, nil)
+	}
+
+	testCases := []testCase{
+		{
+			name: "send answer without client id",
+			mock: func() {},
+			body: answersBody(t, map[string]any{
+				"card_uuid":   "card-uuid",
+				"module_uuid": "module-uuid",
+				"is_correct":  true,
+				"answered_at": testAnsweredAt,
+			}),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "repo error",
+			mock: func() {
+				statsRepo.EXPECT().
+					GetAnswersByClientIDs(gomock.Any(), gomock.Any(), []string{"first"}).
+					Return(nil, errors.New("boom"))
+			},
+			body:         answersBody(t, syncedAnswer("first", testAnsweredAt)),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "retried batch is skipped",
+			mock: func() {
+				statsRepo.EXPECT().
+					GetAnswersByClientIDs(gomock.Any(), gomock.Any(), []string{"first"}).
+					Return(map[string]*entity.Answer{
+						"first": {UUID: "answer-uuid", ClientID: "first"},
+					}, nil)
+			},
+			body: answersBody(
+				t,
+				syncedAnswer("first", testAnsweredAt),
+				syncedAnswer("first", testAnsweredAt),
+			),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.SyncResult{
+				Accepted:   []*entity.Answer{},
+				Duplicates: []string{"first", "first"},
+			}),
+		},
+		{
+			name: "answer stored by concurrent batch is reported as duplicate",
+			mock: func() {
+				statsRepo.EXPECT().
+					GetAnswersByClientIDs(gomock.Any(), gomock.Any(), []string{"first", "second"}).
+					Return(map[string]*entity.Answer{}, nil)
+
+				expectSchedulers()
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, _ string, answers []*entity.Answer, _ []*entity.ReviewState) ([]string, error) {
+						answers[1].UUID = "answer-uuid"
+
+						return []string{"first"}, nil
+					})
+			},
+			body: answersBody(
+				t,
+				syncedAnswer("first", testAnsweredAt),
+				syncedAnswer("second", testAnsweredAt.AddDate(0, 0, 1)),
+			),
+			expectedCode: http.StatusOK,
+			expectedBody: testutils.ToJSON(t, entity.SyncResult{
+				Accepted: []*entity.Answer{
+					{
+						UUID:           "answer-uuid",
+						ClientID:       "second",
+						CardUUID:       "card-uuid",
+						ModuleUUID:     "module-uuid",
+						IsCorrect:      true,
+						Grade:          entity.DefaultCorrectAnswerGrade,
+						IntervalBefore: 1,
+						IntervalAfter:  6,
+						AnsweredAt:     testAnsweredAt.AddDate(0, 0, 1),
+					},
+				},
+				Duplicates: []string{"first"},
+			}),
+		},
+		{
+			name: "new answers are applied in chronological order",
+			mock: func() {
+				statsRepo.EXPECT().
+					GetAnswersByClientIDs(gomock.Any(), gomock.Any(), []string{"second", "first"}).
+					Return(map[string]*entity.Answer{}, nil)
+
+				expectSchedulers()
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, _ string, answers []*entity.Answer, states []*entity.ReviewState) ([]string, error) {
+						assert.Len(t, answers, 2)
+						assert.Equal(t, "second", answers[0].ClientID)
+						assert.Equal(t, 1, answers[0].IntervalBefore)
+						assert.Equal(t, 6, answers[0].IntervalAfter)
+						assert.Equal(t, "first", answers[1].ClientID)
+						assert.Equal(t, 0, answers[1].IntervalBefore)
+						assert.Equal(t, 1, answers[1].IntervalAfter)
+
+						assert.Len(t, states, 1)
+						assert.Equal(t, 6, states[0].IntervalDays)
+						assert.Equal(t, 2, states[0].Repetitions)
+						assert.Equal(t, testAnsweredAt.AddDate(0, 0, 1), states[0].LastReviewedAt)
+
+						return nil, nil
+					})
+			},
+			body: answersBody(
+				t,
+				syncedAnswer("second", testAnsweredAt.AddDate(0, 0, 1)),
+				syncedAnswer("first", testAnsweredAt),
+			),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "outdated answer replays card history",
+			mock: func() {
+				statsRepo.EXPECT().
+					GetAnswersByClientIDs(gomock.Any(), gomock.Any(), []string{"offline"}).
+					Return(map[string]*entity.Answer{}, nil)
+
+				expectSchedulers()
+
+				reviewRepo.EXPECT().
+					GetReviewStates(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return(map[string]*entity.ReviewState{
+						"card-uuid": {
+							CardUUID:       "card-uuid",
+							ModuleUUID:     "module-uuid",
+							EaseFactor:     2.5,
+							IntervalDays:   1,
+							Repetitions:    1,
+							DueAt:          testAnsweredAt.AddDate(0, 0, 2),
+							LastReviewedAt: testAnsweredAt.AddDate(0, 0, 1),
+						},
+					}, nil)
+
+				statsRepo.EXPECT().
+					GetCardsAnswers(gomock.Any(), gomock.Any(), []string{"card-uuid"}).
+					Return([]*entity.Answer{
+						{
+							UUID:       "stored-answer-uuid",
+							CardUUID:   "card-uuid",
+							ModuleUUID: "module-uuid",
+							IsCorrect:  true,
+							Grade:      entity.DefaultCorrectAnswerGrade,
+							AnsweredAt: testAnsweredAt.AddDate(0, 0, 1),
+						},
+					}, nil)
+
+				statsRepo.EXPECT().
+					SaveAnswers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, _ string, answers []*entity.Answer, states []*entity.ReviewState) ([]string, error) {
+						assert.Len(t, answers, 1)
+						assert.Equal(t, "offline", answers[0].ClientID)
+						assert.Equal(t, 0, answers[0].IntervalBefore)
+						assert.Equal(t, 1, answers[0].IntervalAfter)
+
+						assert.Len(t, states, 1)
+						assert.Equal(t, 6, states[0].IntervalDays)
+						assert.Equal(t, 2, states[0].Repetitions)
+						assert.Equal(t, testAnsweredAt.AddDate(0, 0, 1), states[0].LastReviewedAt)
+
+						return nil, nil
+					})
+			},
+			body:         answersBody(t, syncedAnswer("offline", testAnsweredAt)),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, body := testutils.SendTestRequest(t, ts, http.MethodPost, "/api/stats/sync", tc.body, map[string]string{})
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
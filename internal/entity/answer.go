@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+const (
+	AnswerVerdictCorrect       = "correct"
+	AnswerVerdictAlmostCorrect = "almost_correct"
+	AnswerVerdictIncorrect     = "incorrect"
+
+	CardSideTerm    = "term"
+	CardSideMeaning = "meaning"
+)
+
+type Answer struct {
+	UUID           string    `json:"uuid"`
+	ClientID       string    `json:"client_id,omitempty"`
+	UserUUID       string    `json:"user_uuid"`
+	CardUUID       string    `json:"card_uuid"`
+	ModuleUUID     string    `json:"module_uuid"`
+	SessionUUID    string    `json:"session_uuid,omitempty"`
+	IsCorrect      bool      `json:"is_correct"`
+	Grade          int       `json:"grade"`
+	ResponseTimeMs int       `json:"response_time_ms"`
+	IntervalBefore int       `json:"interval_before"`
+	IntervalAfter  int       `json:"interval_after"`
+	AnsweredAt     time.Time `json:"answered_at"`
+}
+
+// AnswerCheck is a result of comparing a typed answer with a card side.
+// Distance is the amount of typos between the answer and the closest acceptable variant.
+type AnswerCheck struct {
+	Verdict  string  `json:"verdict"`
+	Grade    int     `json:"grade"`
+	Expected string  `json:"expected"`
+	Distance int     `json:"distance"`
+	Answer   *Answer `json:"answer,omitempty"`
+}
+
+// SyncResult describes a synced batch of answers.
+// Duplicates are client IDs of answers which had already been uploaded before.
+type SyncResult struct {
+	Accepted   []*Answer `json:"accepted"`
+	Duplicates []string  `json:"duplicates"`
+}
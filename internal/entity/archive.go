@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+const (
+	// ModuleArchiveFormat identifies module archives among other json documents.
+	ModuleArchiveFormat = "simple-cards.module"
+
+	// ModuleArchiveVersion is the version of archives which are exported,
+	// archives of older versions are migrated on import.
+	ModuleArchiveVersion = 1
+)
+
+// ModuleArchive is a lossless json backup of a module with its settings, cards and user's review states.
+// Answers and study sessions are user's activity, they aren't archived.
+type ModuleArchive struct {
+	Format     string          `json:"format"`
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Module     *ArchivedModule `json:"module"`
+}
+
+// ArchivedModule keeps UUIDs of the exported module and cards for reference only,
+// an imported module and its cards get new ones.
+type ArchivedModule struct {
+	UUID      string          `json:"uuid"`
+	Name      string          `json:"name"`
+	CreatedAt time.Time       `json:"created_at"`
+	Settings  ModuleSettings  `json:"settings"`
+	Cards     []*ArchivedCard `json:"cards"`
+}
+
+// ArchivedCard has no review state if the card has never been reviewed.
+type ArchivedCard struct {
+	UUID        string               `json:"uuid"`
+	Term        string               `json:"term"`
+	Meaning     string               `json:"meaning"`
+	CreatedAt   time.Time            `json:"created_at"`
+	ReviewState *ArchivedReviewState `json:"review_state,omitempty"`
+}
+
+type ArchivedReviewState struct {
+	EaseFactor     float64   `json:"ease_factor"`
+	Stability      float64   `json:"stability"`
+	Difficulty     float64   `json:"difficulty"`
+	IntervalDays   int       `json:"interval_days"`
+	Repetitions    int       `json:"repetitions"`
+	Lapses         int       `json:"lapses"`
+	DueAt          time.Time `json:"due_at"`
+	LastReviewedAt time.Time `json:"last_reviewed_at"`
+	IsLeech        bool      `json:"is_leech"`
+	IsSuspended    bool      `json:"is_suspended"`
+}
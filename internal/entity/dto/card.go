@@ -9,3 +9,12 @@ type UpdateCardRequest struct {
 	Term    string `json:"term"    validate:"required_without=Meaning"`
 	Meaning string `json:"meaning" validate:"required_without=Term"`
 }
+
+type CheckAnswerRequest struct {
+	Answer         string `json:"answer"           validate:"max=1000"`
+	Side           string `json:"side"             validate:"omitempty,oneof=term meaning"`
+	Language       string `json:"language"         validate:"omitempty,oneof=en de fr es"`
+	Record         bool   `json:"record"`
+	SessionUUID    string `json:"session_uuid"`
+	ResponseTimeMs int    `json:"response_time_ms" validate:"min=0"`
+}
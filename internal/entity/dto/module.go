@@ -4,7 +4,51 @@ type CreateOrUpdateModuleRequest struct {
 	Name string `json:"name" validate:"required,max=100"`
 }
 
+// QuizletImportRequest creates a new module unless ModuleUUID of an existing module is passed.
 type QuizletImportRequest struct {
-	ModuleName      string `json:"module_name"       validate:"required,max=100"`
+	ModuleName      string `json:"module_name"       validate:"required_without=ModuleUUID,max=100"`
 	QuizletModuleID string `json:"quizlet_module_id" validate:"required"`
+	ModuleUUID      string `json:"module_uuid"       validate:"omitempty,uuid"`
+	Strategy        string `json:"strategy"          validate:"omitempty,oneof=append skip-duplicates update-existing"`
+}
+
+type QuizletImportPreviewRequest struct {
+	QuizletModuleID string `json:"quizlet_module_id" validate:"required"`
+}
+
+// CSVImportRequest is made of csv import form values.
+type CSVImportRequest struct {
+	ModuleUUID    string `validate:"omitempty,uuid"`
+	Strategy      string `validate:"omitempty,oneof=append skip-duplicates update-existing"`
+	Delimiter     string `validate:"omitempty,oneof=comma semicolon tab auto"`
+	Header        string `validate:"omitempty,oneof=none present auto"`
+	TermColumn    string `validate:"omitempty,max=100"`
+	MeaningColumn string `validate:"omitempty,max=100"`
+	Swap          string `validate:"omitempty,boolean"`
+	LazyQuotes    string `validate:"omitempty,boolean"`
+	Encoding      string `validate:"omitempty,oneof=utf-8 utf-16 utf-16le utf-16be windows-1251 koi8-r auto"`
+	Lenient       string `validate:"omitempty,boolean"`
+}
+
+// APKGImportRequest is made of anki package import form values.
+type APKGImportRequest struct {
+	TermField    string `validate:"omitempty,max=100"`
+	MeaningField string `validate:"omitempty,max=100"`
+}
+
+// XLSXImportRequest is made of xlsx import form values.
+type XLSXImportRequest struct {
+	ModuleUUID    string `validate:"omitempty,uuid"`
+	Strategy      string `validate:"omitempty,oneof=append skip-duplicates update-existing"`
+	Sheet         string `validate:"omitempty,max=100"`
+	Header        string `validate:"omitempty,oneof=none present auto"`
+	TermColumn    string `validate:"omitempty,max=100"`
+	MeaningColumn string `validate:"omitempty,max=100"`
+	Swap          string `validate:"omitempty,boolean"`
+}
+
+// LibraryExportRequest exports the whole library unless modules are selected with ModuleUUIDs.
+type LibraryExportRequest struct {
+	Format      string   `json:"format"       validate:"required,oneof=csv xlsx apkg json"`
+	ModuleUUIDs []string `json:"module_uuids" validate:"max=1000,unique,dive,uuid"`
 }
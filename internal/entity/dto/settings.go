@@ -0,0 +1,14 @@
+package dto
+
+type UpdateUserSettingsRequest struct {
+	Scheduler        *string  `json:"scheduler"         validate:"omitempty,oneof=sm2 fsrs"`
+	DesiredRetention *float64 `json:"desired_retention" validate:"omitempty,min=0.7,max=0.99"`
+	Timezone         *string  `json:"timezone"          validate:"omitempty,timezone"`
+	DailyGoalType    *string  `json:"daily_goal_type"   validate:"omitempty,oneof=cards minutes"`
+	DailyGoalValue   *int     `json:"daily_goal_value"  validate:"omitempty,min=1,max=1440"`
+	LeechThreshold   *int     `json:"leech_threshold"   validate:"omitempty,min=1,max=100"`
+}
+
+type UpdateModuleSettingsRequest struct {
+	Scheduler string `json:"scheduler" validate:"omitempty,oneof=sm2 fsrs"`
+}
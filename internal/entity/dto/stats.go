@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type AnswerRequest struct {
+	CardUUID       string    `json:"card_uuid"        validate:"required"`
+	ModuleUUID     string    `json:"module_uuid"      validate:"required"`
+	SessionUUID    string    `json:"session_uuid"`
+	IsCorrect      bool      `json:"is_correct"`
+	Grade          *int      `json:"grade"            validate:"omitempty,min=0,max=5"`
+	ResponseTimeMs int       `json:"response_time_ms" validate:"min=0"`
+	AnsweredAt     time.Time `json:"answered_at"      validate:"required"`
+}
+
+type SaveAnswersRequest struct {
+	Answers []AnswerRequest `json:"answers" validate:"required,min=1,max=500,dive"`
+}
+
+type SyncAnswerRequest struct {
+	ClientID string `json:"client_id" validate:"required,max=64"`
+	AnswerRequest
+}
+
+type SyncAnswersRequest struct {
+	Answers []SyncAnswerRequest `json:"answers" validate:"required,min=1,max=1000,dive"`
+}
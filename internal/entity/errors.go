@@ -5,7 +5,19 @@ import (
 	"fmt"
 )
 
-var ErrUserConflict = errors.New("user with same login already exists")
+var (
+	ErrUserConflict    = errors.New("user with same login already exists")
+	ErrSessionFinished = errors.New("session is already finished")
+	ErrNoCardsToImport = errors.New("no cards to import")
+
+	ErrImportJobCanceled = errors.New("import job has been canceled")
+	ErrImportJobFinished = errors.New("import job is already finished")
+
+	ErrNoModulesToExport    = errors.New("no modules to export")
+	ErrExportNotReady       = errors.New("export archive is not ready yet")
+	ErrExportArchiveExpired = errors.New("export archive has expired")
+	ErrExportArchiveTooBig  = errors.New("export archive is too big, export fewer modules at once")
+)
 
 type (
 	ModuleNotFoundError struct {
@@ -15,6 +27,26 @@ type (
 	CardNotFoundError struct {
 		UUID string
 	}
+
+	SessionNotFoundError struct {
+		UUID string
+	}
+
+	ImportJobNotFoundError struct {
+		UUID string
+	}
+
+	ExportJobNotFoundError struct {
+		UUID string
+	}
+
+	QueueJobNotFoundError struct {
+		ID int64
+	}
+
+	ModuleArchiveError struct {
+		Reason string
+	}
 )
 
 func (err *ModuleNotFoundError) Error() string {
@@ -24,3 +56,23 @@ func (err *ModuleNotFoundError) Error() string {
 func (err *CardNotFoundError) Error() string {
 	return fmt.Sprintf("card with uuid=\"%s\" does not exist", err.UUID)
 }
+
+func (err *SessionNotFoundError) Error() string {
+	return fmt.Sprintf("active session with uuid=\"%s\" does not exist", err.UUID)
+}
+
+func (err *ImportJobNotFoundError) Error() string {
+	return fmt.Sprintf("import job with uuid=\"%s\" does not exist", err.UUID)
+}
+
+func (err *ExportJobNotFoundError) Error() string {
+	return fmt.Sprintf("export job with uuid=\"%s\" does not exist", err.UUID)
+}
+
+func (err *QueueJobNotFoundError) Error() string {
+	return fmt.Sprintf("dead queue job with id=%d does not exist", err.ID)
+}
+
+func (err *ModuleArchiveError) Error() string {
+	return "invalid module archive: " + err.Reason
+}
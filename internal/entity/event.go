@@ -0,0 +1,25 @@
+package entity
+
+const (
+	JobEventQueued    = "queued"
+	JobEventStarted   = "started"
+	JobEventProgress  = "progress"
+	JobEventCompleted = "completed"
+	JobEventFailed    = "failed"
+	JobEventCanceled  = "canceled"
+
+	JobKindImport = "import"
+	JobKindExport = "export"
+)
+
+// JobEvent is a lifecycle event of a user's asynchronous job.
+// Progress is a percentage, ModuleUUID is set on completion of an import and Reason on failure.
+type JobEvent struct {
+	Type       string `json:"type"`
+	JobKind    string `json:"job_kind"`
+	JobUUID    string `json:"job_uuid"`
+	UserUUID   string `json:"user_uuid"`
+	Progress   int    `json:"progress"`
+	ModuleUUID string `json:"module_uuid,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+const (
+	ExportStatusCreated    = "created"
+	ExportStatusProcessing = "processing"
+	ExportStatusProcessed  = "processed"
+	ExportStatusFailed     = "failed"
+
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatAPKG = "apkg"
+	ExportFormatJSON = "json"
+)
+
+// ExportJob tracks an asynchronous export of user's modules to a zip archive with a file per module.
+// A job without ModuleUUIDs exports the whole library. The processed job's archive can be downloaded
+// till ExpiresAt, after that it's removed and the library has to be exported again.
+type ExportJob struct {
+	UUID         string     `json:"uuid"`
+	UserUUID     string     `json:"user_uuid"`
+	Format       string     `json:"format"`
+	Status       string     `json:"status"`
+	ModuleUUIDs  []string   `json:"module_uuids,omitempty"`
+	ModulesCount int        `json:"modules_count"`
+	CardsCount   int        `json:"cards_count"`
+	ArchiveSize  int64      `json:"archive_size"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/llravell/simple-cards/pkg/csvcards"
+)
+
+const (
+	ImportStatusCreated    = "created"
+	ImportStatusProcessing = "processing"
+	ImportStatusProcessed  = "processed"
+	ImportStatusFailed     = "failed"
+	ImportStatusCanceled   = "canceled"
+
+	ImportSourceQuizlet = "quizlet"
+	ImportSourceCSV     = "csv"
+	ImportSourceAPKG    = "apkg"
+	ImportSourceXLSX    = "xlsx"
+
+	// ImportStrategyAppend adds all imported cards to the existing module.
+	ImportStrategyAppend = "append"
+	// ImportStrategySkipDuplicates adds only cards whose normalized terms are not in the module yet.
+	ImportStrategySkipDuplicates = "skip-duplicates"
+	// ImportStrategyUpdateExisting overwrites meanings of cards with the same normalized terms and adds the rest.
+	ImportStrategyUpdateExisting = "update-existing"
+)
+
+// ImportRowError is a row skipped by an import, Raw is the row content.
+type ImportRowError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+	Raw    string `json:"raw"`
+}
+
+// ImportJob tracks an asynchronous import of a module.
+// ModuleUUID is set when the imported module has been created,
+// imports into an existing module have it from the start together with the merge strategy.
+// An import of several modules, e.g. of anki decks, lists all of them in ModuleUUIDs
+// and ModuleUUID is the first one.
+type ImportJob struct {
+	UUID              string    `json:"uuid"`
+	UserUUID          string    `json:"user_uuid"`
+	Source            string    `json:"source"`
+	Status            string    `json:"status"`
+	ModuleName        string    `json:"module_name"`
+	ModuleUUID        string    `json:"module_uuid,omitempty"`
+	ModuleUUIDs       []string  `json:"module_uuids,omitempty"`
+	Strategy          string    `json:"strategy,omitempty"`
+	CardsCount        int       `json:"cards_count"`
+	UpdatedCardsCount int       `json:"updated_cards_count"`
+	SkippedCardsCount int       `json:"skipped_cards_count"`
+	ErrorsCount       int       `json:"errors_count"`
+	ErrorMessage      string    `json:"error_message,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type ImportPreviewCard struct {
+	Term    string `json:"term"`
+	Meaning string `json:"meaning"`
+}
+
+// ImportPreview is a sample of cards an import would produce, nothing is stored.
+// Dialect of a csv preview has the detected delimiter, encoding and header,
+// it can be passed to the csv import as is.
+type ImportPreview struct {
+	Source   string               `json:"source"`
+	Cards    []*ImportPreviewCard `json:"cards"`
+	HasMore  bool                 `json:"has_more"`
+	Dialect  *csvcards.Dialect    `json:"dialect,omitempty"`
+	Warnings []string             `json:"warnings"`
+}
@@ -1,9 +1,26 @@
 package entity
 
+import "time"
+
+// MasteredIntervalDays is a minimal review interval of a mastered card.
+const MasteredIntervalDays = 21
+
 type Module struct {
-	UUID     string `json:"uuid"`
-	Name     string `json:"name"`
-	UserUUID string `json:"user_uuid"`
+	UUID     string          `json:"uuid"`
+	Name     string          `json:"name"`
+	UserUUID string          `json:"user_uuid"`
+	Progress *ModuleProgress `json:"progress,omitempty"`
+}
+
+// ModuleProgress describes user's learning progress of a module.
+// Cards which have never been reviewed are new, reviewed cards are learning until they become mastered.
+type ModuleProgress struct {
+	CardsCount    int        `json:"cards_count"`
+	NewCount      int        `json:"new_count"`
+	LearningCount int        `json:"learning_count"`
+	MasteredCount int        `json:"mastered_count"`
+	DueTodayCount int        `json:"due_today_count"`
+	LastStudiedAt *time.Time `json:"last_studied_at"`
 }
 
 type ModuleWithCards struct {
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+const (
+	QueueJobStatusQueued  = "queued"
+	QueueJobStatusRunning = "running"
+	QueueJobStatusDead    = "dead"
+)
+
+// QueueJob is a work stored in the durable queue.
+// Dead jobs have run out of attempts or failed with an error which is not worth retrying.
+type QueueJob struct {
+	ID        int64     `json:"id"`
+	Queue     string    `json:"queue"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	RunAt     time.Time `json:"run_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
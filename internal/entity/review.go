@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+const (
+	MinAnswerGrade     = 0
+	MaxAnswerGrade     = 5
+	PassingAnswerGrade = 3
+
+	// Grades of answers which were sent without explicit grade.
+	DefaultCorrectAnswerGrade   = 4
+	DefaultIncorrectAnswerGrade = 1
+
+	// Grade of a checked answer which has typos.
+	AlmostCorrectAnswerGrade = 3
+)
+
+type ReviewState struct {
+	CardUUID       string    `json:"card_uuid"`
+	ModuleUUID     string    `json:"module_uuid"`
+	EaseFactor     float64   `json:"ease_factor"`
+	Stability      float64   `json:"stability"`
+	Difficulty     float64   `json:"difficulty"`
+	IntervalDays   int       `json:"interval_days"`
+	Repetitions    int       `json:"repetitions"`
+	Lapses         int       `json:"lapses"`
+	DueAt          time.Time `json:"due_at"`
+	LastReviewedAt time.Time `json:"last_reviewed_at"`
+	IsLeech        bool      `json:"is_leech"`
+	IsSuspended    bool      `json:"is_suspended"`
+}
+
+type DueCard struct {
+	Card
+	ReviewState *ReviewState `json:"review_state"`
+}
+
+// LeechCard is a card which the user keeps failing.
+type LeechCard struct {
+	Card
+	ReviewState *ReviewState `json:"review_state"`
+}
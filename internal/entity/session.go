@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+type StudySession struct {
+	UUID       string          `json:"uuid"`
+	ModuleUUID string          `json:"module_uuid"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+	Summary    *SessionSummary `json:"summary,omitempty"`
+}
+
+// SessionSummary describes results of a finished session.
+// A card is promoted when its interval has grown during the session and demoted when it has shrunk.
+type SessionSummary struct {
+	CardsSeen     int     `json:"cards_seen"`
+	AnswersCount  int     `json:"answers_count"`
+	CorrectCount  int     `json:"correct_count"`
+	Accuracy      float64 `json:"accuracy"`
+	TimeSpentMs   int64   `json:"time_spent_ms"`
+	PromotedCount int     `json:"promoted_count"`
+	DemotedCount  int     `json:"demoted_count"`
+}
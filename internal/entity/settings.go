@@ -0,0 +1,30 @@
+package entity
+
+const (
+	SchedulerSM2  = "sm2"
+	SchedulerFSRS = "fsrs"
+
+	DailyGoalCards   = "cards"
+	DailyGoalMinutes = "minutes"
+
+	DefaultScheduler        = SchedulerSM2
+	DefaultDesiredRetention = 0.9
+	DefaultTimezone         = "UTC"
+	DefaultDailyGoalType    = DailyGoalCards
+	DefaultDailyGoalValue   = 20
+	DefaultLeechThreshold   = 8
+)
+
+type UserSettings struct {
+	Scheduler        string  `json:"scheduler"`
+	DesiredRetention float64 `json:"desired_retention"`
+	Timezone         string  `json:"timezone"`
+	DailyGoalType    string  `json:"daily_goal_type"`
+	DailyGoalValue   int     `json:"daily_goal_value"`
+	LeechThreshold   int     `json:"leech_threshold"`
+}
+
+// ModuleSettings overrides user settings for a single module, empty values are inherited.
+type ModuleSettings struct {
+	Scheduler string `json:"scheduler"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+type CardStats struct {
+	CardUUID       string     `json:"card_uuid"`
+	Term           string     `json:"term"`
+	Meaning        string     `json:"meaning"`
+	CorrectCount   int        `json:"correct_count"`
+	IncorrectCount int        `json:"incorrect_count"`
+	Accuracy       float64    `json:"accuracy"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	Streak         int        `json:"streak"`
+}
+
+// DailyActivity is an amount of user's study during a day in the user's time zone.
+type DailyActivity struct {
+	Date         string  `json:"date"`
+	AnswersCount int     `json:"answers_count"`
+	CorrectCount int     `json:"correct_count"`
+	Accuracy     float64 `json:"accuracy"`
+	TimeSpentMs  int64   `json:"time_spent_ms"`
+}
+
+type StudyStreak struct {
+	CurrentStreak  int    `json:"current_streak"`
+	LongestStreak  int    `json:"longest_streak"`
+	Timezone       string `json:"timezone"`
+	DailyGoalType  string `json:"daily_goal_type"`
+	DailyGoalValue int    `json:"daily_goal_value"`
+	TodayProgress  int    `json:"today_progress"`
+	GoalReached    bool   `json:"goal_reached"`
+}
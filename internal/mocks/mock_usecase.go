@@ -17,6 +17,7 @@ import (
 	entity "github.com/llravell/simple-cards/internal/entity"
 	usecase "github.com/llravell/simple-cards/internal/usecase"
 	quizlet "github.com/llravell/simple-cards/pkg/quizlet"
+	srs "github.com/llravell/simple-cards/pkg/srs"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -136,190 +137,960 @@ func (m *MockModulesRepository) EXPECT() *MockModulesRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddCardsToModule mocks base method.
+func (m *MockModulesRepository) AddCardsToModule(ctx context.Context, moduleUUID string, newCards, updatedCards []*entity.Card) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCardsToModule", ctx, moduleUUID, newCards, updatedCards)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCardsToModule indicates an expected call of AddCardsToModule.
+func (mr *MockModulesRepositoryMockRecorder) AddCardsToModule(ctx, moduleUUID, newCards, updatedCards any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCardsToModule", reflect.TypeOf((*MockModulesRepository)(nil).AddCardsToModule), ctx, moduleUUID, newCards, updatedCards)
+}
+
+// CreateModuleFromArchive mocks base method.
+func (m *MockModulesRepository) CreateModuleFromArchive(ctx context.Context, userUUID string, archivedModule *entity.ArchivedModule) (*entity.Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModuleFromArchive", ctx, userUUID, archivedModule)
+	ret0, _ := ret[0].(*entity.Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateModuleFromArchive indicates an expected call of CreateModuleFromArchive.
+func (mr *MockModulesRepositoryMockRecorder) CreateModuleFromArchive(ctx, userUUID, archivedModule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModuleFromArchive", reflect.TypeOf((*MockModulesRepository)(nil).CreateModuleFromArchive), ctx, userUUID, archivedModule)
+}
+
 // CreateNewModule mocks base method.
 func (m *MockModulesRepository) CreateNewModule(ctx context.Context, userUUID, moduleName string) (*entity.Module, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateNewModule", ctx, userUUID, moduleName)
-	ret0, _ := ret[0].(*entity.Module)
+	ret := m.ctrl.Call(m, "CreateNewModule", ctx, userUUID, moduleName)
+	ret0, _ := ret[0].(*entity.Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNewModule indicates an expected call of CreateNewModule.
+func (mr *MockModulesRepositoryMockRecorder) CreateNewModule(ctx, userUUID, moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewModule", reflect.TypeOf((*MockModulesRepository)(nil).CreateNewModule), ctx, userUUID, moduleName)
+}
+
+// CreateNewModuleWithCards mocks base method.
+func (m *MockModulesRepository) CreateNewModuleWithCards(ctx context.Context, moduleWithCards *entity.ModuleWithCards) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNewModuleWithCards", ctx, moduleWithCards)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNewModuleWithCards indicates an expected call of CreateNewModuleWithCards.
+func (mr *MockModulesRepositoryMockRecorder) CreateNewModuleWithCards(ctx, moduleWithCards any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewModuleWithCards", reflect.TypeOf((*MockModulesRepository)(nil).CreateNewModuleWithCards), ctx, moduleWithCards)
+}
+
+// CreateNewModulesWithCards mocks base method.
+func (m *MockModulesRepository) CreateNewModulesWithCards(ctx context.Context, modulesWithCards []*entity.ModuleWithCards) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNewModulesWithCards", ctx, modulesWithCards)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNewModulesWithCards indicates an expected call of CreateNewModulesWithCards.
+func (mr *MockModulesRepositoryMockRecorder) CreateNewModulesWithCards(ctx, modulesWithCards any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewModulesWithCards", reflect.TypeOf((*MockModulesRepository)(nil).CreateNewModulesWithCards), ctx, modulesWithCards)
+}
+
+// DeleteModule mocks base method.
+func (m *MockModulesRepository) DeleteModule(ctx context.Context, userUUID, moduleUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModule", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModule indicates an expected call of DeleteModule.
+func (mr *MockModulesRepositoryMockRecorder) DeleteModule(ctx, userUUID, moduleUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModule", reflect.TypeOf((*MockModulesRepository)(nil).DeleteModule), ctx, userUUID, moduleUUID)
+}
+
+// GetAllModules mocks base method.
+func (m *MockModulesRepository) GetAllModules(ctx context.Context, userUUID string) ([]*entity.Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllModules", ctx, userUUID)
+	ret0, _ := ret[0].([]*entity.Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllModules indicates an expected call of GetAllModules.
+func (mr *MockModulesRepositoryMockRecorder) GetAllModules(ctx, userUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllModules", reflect.TypeOf((*MockModulesRepository)(nil).GetAllModules), ctx, userUUID)
+}
+
+// GetArchivedModule mocks base method.
+func (m *MockModulesRepository) GetArchivedModule(ctx context.Context, userUUID, moduleUUID string) (*entity.ArchivedModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedModule", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].(*entity.ArchivedModule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedModule indicates an expected call of GetArchivedModule.
+func (mr *MockModulesRepositoryMockRecorder) GetArchivedModule(ctx, userUUID, moduleUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedModule", reflect.TypeOf((*MockModulesRepository)(nil).GetArchivedModule), ctx, userUUID, moduleUUID)
+}
+
+// GetLibraryModules mocks base method.
+func (m *MockModulesRepository) GetLibraryModules(ctx context.Context, userUUID string, moduleUUIDs []string) ([]*entity.Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLibraryModules", ctx, userUUID, moduleUUIDs)
+	ret0, _ := ret[0].([]*entity.Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLibraryModules indicates an expected call of GetLibraryModules.
+func (mr *MockModulesRepositoryMockRecorder) GetLibraryModules(ctx, userUUID, moduleUUIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLibraryModules", reflect.TypeOf((*MockModulesRepository)(nil).GetLibraryModules), ctx, userUUID, moduleUUIDs)
+}
+
+// GetModule mocks base method.
+func (m *MockModulesRepository) GetModule(ctx context.Context, userUUID, moduleUUID string) (*entity.Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModule", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].(*entity.Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModule indicates an expected call of GetModule.
+func (mr *MockModulesRepositoryMockRecorder) GetModule(ctx, userUUID, moduleUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModule", reflect.TypeOf((*MockModulesRepository)(nil).GetModule), ctx, userUUID, moduleUUID)
+}
+
+// ModuleExists mocks base method.
+func (m *MockModulesRepository) ModuleExists(ctx context.Context, userUUID, moduleUUID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModuleExists", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModuleExists indicates an expected call of ModuleExists.
+func (mr *MockModulesRepositoryMockRecorder) ModuleExists(ctx, userUUID, moduleUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleExists", reflect.TypeOf((*MockModulesRepository)(nil).ModuleExists), ctx, userUUID, moduleUUID)
+}
+
+// UpdateModule mocks base method.
+func (m *MockModulesRepository) UpdateModule(ctx context.Context, userUUID, moduleUUID, moduleName string) (*entity.Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModule", ctx, userUUID, moduleUUID, moduleName)
+	ret0, _ := ret[0].(*entity.Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateModule indicates an expected call of UpdateModule.
+func (mr *MockModulesRepositoryMockRecorder) UpdateModule(ctx, userUUID, moduleUUID, moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModule", reflect.TypeOf((*MockModulesRepository)(nil).UpdateModule), ctx, userUUID, moduleUUID, moduleName)
+}
+
+// MockImportJobsRepository is a mock of ImportJobsRepository interface.
+type MockImportJobsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportJobsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockImportJobsRepositoryMockRecorder is the mock recorder for MockImportJobsRepository.
+type MockImportJobsRepositoryMockRecorder struct {
+	mock *MockImportJobsRepository
+}
+
+// NewMockImportJobsRepository creates a new mock instance.
+func NewMockImportJobsRepository(ctrl *gomock.Controller) *MockImportJobsRepository {
+	mock := &MockImportJobsRepository{ctrl: ctrl}
+	mock.recorder = &MockImportJobsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportJobsRepository) EXPECT() *MockImportJobsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelImportJob mocks base method.
+func (m *MockImportJobsRepository) CancelImportJob(ctx context.Context, userUUID, jobUUID string) (*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelImportJob", ctx, userUUID, jobUUID)
+	ret0, _ := ret[0].(*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelImportJob indicates an expected call of CancelImportJob.
+func (mr *MockImportJobsRepositoryMockRecorder) CancelImportJob(ctx, userUUID, jobUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelImportJob", reflect.TypeOf((*MockImportJobsRepository)(nil).CancelImportJob), ctx, userUUID, jobUUID)
+}
+
+// CreateImportJob mocks base method.
+func (m *MockImportJobsRepository) CreateImportJob(ctx context.Context, job *entity.ImportJob) (*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportJob", ctx, job)
+	ret0, _ := ret[0].(*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportJob indicates an expected call of CreateImportJob.
+func (mr *MockImportJobsRepositoryMockRecorder) CreateImportJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportJob", reflect.TypeOf((*MockImportJobsRepository)(nil).CreateImportJob), ctx, job)
+}
+
+// GetImportJob mocks base method.
+func (m *MockImportJobsRepository) GetImportJob(ctx context.Context, userUUID, jobUUID string) (*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", ctx, userUUID, jobUUID)
+	ret0, _ := ret[0].(*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockImportJobsRepositoryMockRecorder) GetImportJob(ctx, userUUID, jobUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockImportJobsRepository)(nil).GetImportJob), ctx, userUUID, jobUUID)
+}
+
+// GetImportJobErrors mocks base method.
+func (m *MockImportJobsRepository) GetImportJobErrors(ctx context.Context, jobUUID string) ([]*entity.ImportRowError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJobErrors", ctx, jobUUID)
+	ret0, _ := ret[0].([]*entity.ImportRowError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJobErrors indicates an expected call of GetImportJobErrors.
+func (mr *MockImportJobsRepositoryMockRecorder) GetImportJobErrors(ctx, jobUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJobErrors", reflect.TypeOf((*MockImportJobsRepository)(nil).GetImportJobErrors), ctx, jobUUID)
+}
+
+// GetImportJobs mocks base method.
+func (m *MockImportJobsRepository) GetImportJobs(ctx context.Context, userUUID string) ([]*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJobs", ctx, userUUID)
+	ret0, _ := ret[0].([]*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJobs indicates an expected call of GetImportJobs.
+func (mr *MockImportJobsRepositoryMockRecorder) GetImportJobs(ctx, userUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJobs", reflect.TypeOf((*MockImportJobsRepository)(nil).GetImportJobs), ctx, userUUID)
+}
+
+// SaveImportJobErrors mocks base method.
+func (m *MockImportJobsRepository) SaveImportJobErrors(ctx context.Context, jobUUID string, rowErrors []*entity.ImportRowError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveImportJobErrors", ctx, jobUUID, rowErrors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveImportJobErrors indicates an expected call of SaveImportJobErrors.
+func (mr *MockImportJobsRepositoryMockRecorder) SaveImportJobErrors(ctx, jobUUID, rowErrors any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveImportJobErrors", reflect.TypeOf((*MockImportJobsRepository)(nil).SaveImportJobErrors), ctx, jobUUID, rowErrors)
+}
+
+// UpdateImportJob mocks base method.
+func (m *MockImportJobsRepository) UpdateImportJob(ctx context.Context, job *entity.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateImportJob indicates an expected call of UpdateImportJob.
+func (mr *MockImportJobsRepositoryMockRecorder) UpdateImportJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportJob", reflect.TypeOf((*MockImportJobsRepository)(nil).UpdateImportJob), ctx, job)
+}
+
+// MockExportJobsRepository is a mock of ExportJobsRepository interface.
+type MockExportJobsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportJobsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExportJobsRepositoryMockRecorder is the mock recorder for MockExportJobsRepository.
+type MockExportJobsRepositoryMockRecorder struct {
+	mock *MockExportJobsRepository
+}
+
+// NewMockExportJobsRepository creates a new mock instance.
+func NewMockExportJobsRepository(ctrl *gomock.Controller) *MockExportJobsRepository {
+	mock := &MockExportJobsRepository{ctrl: ctrl}
+	mock.recorder = &MockExportJobsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportJobsRepository) EXPECT() *MockExportJobsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateExportJob mocks base method.
+func (m *MockExportJobsRepository) CreateExportJob(ctx context.Context, job *entity.ExportJob) (*entity.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExportJob", ctx, job)
+	ret0, _ := ret[0].(*entity.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExportJob indicates an expected call of CreateExportJob.
+func (mr *MockExportJobsRepositoryMockRecorder) CreateExportJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportJob", reflect.TypeOf((*MockExportJobsRepository)(nil).CreateExportJob), ctx, job)
+}
+
+// DeleteExpiredExportArchives mocks base method.
+func (m *MockExportJobsRepository) DeleteExpiredExportArchives(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredExportArchives", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredExportArchives indicates an expected call of DeleteExpiredExportArchives.
+func (mr *MockExportJobsRepositoryMockRecorder) DeleteExpiredExportArchives(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredExportArchives", reflect.TypeOf((*MockExportJobsRepository)(nil).DeleteExpiredExportArchives), ctx)
+}
+
+// GetExportArchive mocks base method.
+func (m *MockExportJobsRepository) GetExportArchive(ctx context.Context, userUUID, jobUUID string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportArchive", ctx, userUUID, jobUUID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportArchive indicates an expected call of GetExportArchive.
+func (mr *MockExportJobsRepositoryMockRecorder) GetExportArchive(ctx, userUUID, jobUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportArchive", reflect.TypeOf((*MockExportJobsRepository)(nil).GetExportArchive), ctx, userUUID, jobUUID)
+}
+
+// GetExportJob mocks base method.
+func (m *MockExportJobsRepository) GetExportJob(ctx context.Context, userUUID, jobUUID string) (*entity.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportJob", ctx, userUUID, jobUUID)
+	ret0, _ := ret[0].(*entity.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportJob indicates an expected call of GetExportJob.
+func (mr *MockExportJobsRepositoryMockRecorder) GetExportJob(ctx, userUUID, jobUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportJob", reflect.TypeOf((*MockExportJobsRepository)(nil).GetExportJob), ctx, userUUID, jobUUID)
+}
+
+// SaveExportArchive mocks base method.
+func (m *MockExportJobsRepository) SaveExportArchive(ctx context.Context, job *entity.ExportJob, archive []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExportArchive", ctx, job, archive)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveExportArchive indicates an expected call of SaveExportArchive.
+func (mr *MockExportJobsRepositoryMockRecorder) SaveExportArchive(ctx, job, archive any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExportArchive", reflect.TypeOf((*MockExportJobsRepository)(nil).SaveExportArchive), ctx, job, archive)
+}
+
+// UpdateExportJob mocks base method.
+func (m *MockExportJobsRepository) UpdateExportJob(ctx context.Context, job *entity.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExportJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExportJob indicates an expected call of UpdateExportJob.
+func (mr *MockExportJobsRepositoryMockRecorder) UpdateExportJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExportJob", reflect.TypeOf((*MockExportJobsRepository)(nil).UpdateExportJob), ctx, job)
+}
+
+// MockQueueJobsRepository is a mock of QueueJobsRepository interface.
+type MockQueueJobsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueueJobsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQueueJobsRepositoryMockRecorder is the mock recorder for MockQueueJobsRepository.
+type MockQueueJobsRepositoryMockRecorder struct {
+	mock *MockQueueJobsRepository
+}
+
+// NewMockQueueJobsRepository creates a new mock instance.
+func NewMockQueueJobsRepository(ctrl *gomock.Controller) *MockQueueJobsRepository {
+	mock := &MockQueueJobsRepository{ctrl: ctrl}
+	mock.recorder = &MockQueueJobsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueueJobsRepository) EXPECT() *MockQueueJobsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetDeadJobs mocks base method.
+func (m *MockQueueJobsRepository) GetDeadJobs(ctx context.Context, queue string) ([]*entity.QueueJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadJobs", ctx, queue)
+	ret0, _ := ret[0].([]*entity.QueueJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadJobs indicates an expected call of GetDeadJobs.
+func (mr *MockQueueJobsRepositoryMockRecorder) GetDeadJobs(ctx, queue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadJobs", reflect.TypeOf((*MockQueueJobsRepository)(nil).GetDeadJobs), ctx, queue)
+}
+
+// RequeueDeadJob mocks base method.
+func (m *MockQueueJobsRepository) RequeueDeadJob(ctx context.Context, id int64) (*entity.QueueJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDeadJob", ctx, id)
+	ret0, _ := ret[0].(*entity.QueueJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueDeadJob indicates an expected call of RequeueDeadJob.
+func (mr *MockQueueJobsRepositoryMockRecorder) RequeueDeadJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDeadJob", reflect.TypeOf((*MockQueueJobsRepository)(nil).RequeueDeadJob), ctx, id)
+}
+
+// MockCardsRepository is a mock of CardsRepository interface.
+type MockCardsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCardsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCardsRepositoryMockRecorder is the mock recorder for MockCardsRepository.
+type MockCardsRepositoryMockRecorder struct {
+	mock *MockCardsRepository
+}
+
+// NewMockCardsRepository creates a new mock instance.
+func NewMockCardsRepository(ctrl *gomock.Controller) *MockCardsRepository {
+	mock := &MockCardsRepository{ctrl: ctrl}
+	mock.recorder = &MockCardsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCardsRepository) EXPECT() *MockCardsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCard mocks base method.
+func (m *MockCardsRepository) CreateCard(ctx context.Context, card *entity.Card) (*entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCard", ctx, card)
+	ret0, _ := ret[0].(*entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCard indicates an expected call of CreateCard.
+func (mr *MockCardsRepositoryMockRecorder) CreateCard(ctx, card any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCard", reflect.TypeOf((*MockCardsRepository)(nil).CreateCard), ctx, card)
+}
+
+// DeleteCard mocks base method.
+func (m *MockCardsRepository) DeleteCard(ctx context.Context, moduleUUID, cardUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCard", ctx, moduleUUID, cardUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCard indicates an expected call of DeleteCard.
+func (mr *MockCardsRepositoryMockRecorder) DeleteCard(ctx, moduleUUID, cardUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCard", reflect.TypeOf((*MockCardsRepository)(nil).DeleteCard), ctx, moduleUUID, cardUUID)
+}
+
+// GetCard mocks base method.
+func (m *MockCardsRepository) GetCard(ctx context.Context, moduleUUID, cardUUID string) (*entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCard", ctx, moduleUUID, cardUUID)
+	ret0, _ := ret[0].(*entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCard indicates an expected call of GetCard.
+func (mr *MockCardsRepositoryMockRecorder) GetCard(ctx, moduleUUID, cardUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCard", reflect.TypeOf((*MockCardsRepository)(nil).GetCard), ctx, moduleUUID, cardUUID)
+}
+
+// GetLeeches mocks base method.
+func (m *MockCardsRepository) GetLeeches(ctx context.Context, userUUID, moduleUUID string) ([]*entity.LeechCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeeches", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].([]*entity.LeechCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeeches indicates an expected call of GetLeeches.
+func (mr *MockCardsRepositoryMockRecorder) GetLeeches(ctx, userUUID, moduleUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeeches", reflect.TypeOf((*MockCardsRepository)(nil).GetLeeches), ctx, userUUID, moduleUUID)
+}
+
+// GetModuleCards mocks base method.
+func (m *MockCardsRepository) GetModuleCards(ctx context.Context, moduleUUID string) ([]*entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleCards", ctx, moduleUUID)
+	ret0, _ := ret[0].([]*entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModuleCards indicates an expected call of GetModuleCards.
+func (mr *MockCardsRepositoryMockRecorder) GetModuleCards(ctx, moduleUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleCards", reflect.TypeOf((*MockCardsRepository)(nil).GetModuleCards), ctx, moduleUUID)
+}
+
+// SaveCard mocks base method.
+func (m *MockCardsRepository) SaveCard(ctx context.Context, card *entity.Card) (*entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCard", ctx, card)
+	ret0, _ := ret[0].(*entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCard indicates an expected call of SaveCard.
+func (mr *MockCardsRepositoryMockRecorder) SaveCard(ctx, card any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCard", reflect.TypeOf((*MockCardsRepository)(nil).SaveCard), ctx, card)
+}
+
+// SetCardSuspended mocks base method.
+func (m *MockCardsRepository) SetCardSuspended(ctx context.Context, userUUID, moduleUUID, cardUUID string, isSuspended bool) (*entity.ReviewState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCardSuspended", ctx, userUUID, moduleUUID, cardUUID, isSuspended)
+	ret0, _ := ret[0].(*entity.ReviewState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCardSuspended indicates an expected call of SetCardSuspended.
+func (mr *MockCardsRepositoryMockRecorder) SetCardSuspended(ctx, userUUID, moduleUUID, cardUUID, isSuspended any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCardSuspended", reflect.TypeOf((*MockCardsRepository)(nil).SetCardSuspended), ctx, userUUID, moduleUUID, cardUUID, isSuspended)
+}
+
+// MockStatsRepository is a mock of StatsRepository interface.
+type MockStatsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStatsRepositoryMockRecorder is the mock recorder for MockStatsRepository.
+type MockStatsRepositoryMockRecorder struct {
+	mock *MockStatsRepository
+}
+
+// NewMockStatsRepository creates a new mock instance.
+func NewMockStatsRepository(ctrl *gomock.Controller) *MockStatsRepository {
+	mock := &MockStatsRepository{ctrl: ctrl}
+	mock.recorder = &MockStatsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsRepository) EXPECT() *MockStatsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAnswersByClientIDs mocks base method.
+func (m *MockStatsRepository) GetAnswersByClientIDs(ctx context.Context, userUUID string, clientIDs []string) (map[string]*entity.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswersByClientIDs", ctx, userUUID, clientIDs)
+	ret0, _ := ret[0].(map[string]*entity.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswersByClientIDs indicates an expected call of GetAnswersByClientIDs.
+func (mr *MockStatsRepositoryMockRecorder) GetAnswersByClientIDs(ctx, userUUID, clientIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswersByClientIDs", reflect.TypeOf((*MockStatsRepository)(nil).GetAnswersByClientIDs), ctx, userUUID, clientIDs)
+}
+
+// GetCardsAnswers mocks base method.
+func (m *MockStatsRepository) GetCardsAnswers(ctx context.Context, userUUID string, cardUUIDs []string) ([]*entity.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardsAnswers", ctx, userUUID, cardUUIDs)
+	ret0, _ := ret[0].([]*entity.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardsAnswers indicates an expected call of GetCardsAnswers.
+func (mr *MockStatsRepositoryMockRecorder) GetCardsAnswers(ctx, userUUID, cardUUIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardsAnswers", reflect.TypeOf((*MockStatsRepository)(nil).GetCardsAnswers), ctx, userUUID, cardUUIDs)
+}
+
+// GetDailyActivity mocks base method.
+func (m *MockStatsRepository) GetDailyActivity(ctx context.Context, userUUID, timezone string, from, to time.Time) ([]*entity.DailyActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyActivity", ctx, userUUID, timezone, from, to)
+	ret0, _ := ret[0].([]*entity.DailyActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyActivity indicates an expected call of GetDailyActivity.
+func (mr *MockStatsRepositoryMockRecorder) GetDailyActivity(ctx, userUUID, timezone, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyActivity", reflect.TypeOf((*MockStatsRepository)(nil).GetDailyActivity), ctx, userUUID, timezone, from, to)
+}
+
+// GetModuleStats mocks base method.
+func (m *MockStatsRepository) GetModuleStats(ctx context.Context, userUUID, moduleUUID string, from, to time.Time) ([]*entity.CardStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleStats", ctx, userUUID, moduleUUID, from, to)
+	ret0, _ := ret[0].([]*entity.CardStats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateNewModule indicates an expected call of CreateNewModule.
-func (mr *MockModulesRepositoryMockRecorder) CreateNewModule(ctx, userUUID, moduleName any) *gomock.Call {
+// GetModuleStats indicates an expected call of GetModuleStats.
+func (mr *MockStatsRepositoryMockRecorder) GetModuleStats(ctx, userUUID, moduleUUID, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewModule", reflect.TypeOf((*MockModulesRepository)(nil).CreateNewModule), ctx, userUUID, moduleName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleStats", reflect.TypeOf((*MockStatsRepository)(nil).GetModuleStats), ctx, userUUID, moduleUUID, from, to)
 }
 
-// CreateNewModuleWithCards mocks base method.
-func (m *MockModulesRepository) CreateNewModuleWithCards(ctx context.Context, moduleWithCards *entity.ModuleWithCards) error {
+// SaveAnswers mocks base method.
+func (m *MockStatsRepository) SaveAnswers(ctx context.Context, userUUID string, answers []*entity.Answer, states []*entity.ReviewState) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateNewModuleWithCards", ctx, moduleWithCards)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "SaveAnswers", ctx, userUUID, answers, states)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateNewModuleWithCards indicates an expected call of CreateNewModuleWithCards.
-func (mr *MockModulesRepositoryMockRecorder) CreateNewModuleWithCards(ctx, moduleWithCards any) *gomock.Call {
+// SaveAnswers indicates an expected call of SaveAnswers.
+func (mr *MockStatsRepositoryMockRecorder) SaveAnswers(ctx, userUUID, answers, states any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewModuleWithCards", reflect.TypeOf((*MockModulesRepository)(nil).CreateNewModuleWithCards), ctx, moduleWithCards)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAnswers", reflect.TypeOf((*MockStatsRepository)(nil).SaveAnswers), ctx, userUUID, answers, states)
 }
 
-// DeleteModule mocks base method.
-func (m *MockModulesRepository) DeleteModule(ctx context.Context, userUUID, moduleUUID string) error {
+// MockReviewRepository is a mock of ReviewRepository interface.
+type MockReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReviewRepositoryMockRecorder is the mock recorder for MockReviewRepository.
+type MockReviewRepositoryMockRecorder struct {
+	mock *MockReviewRepository
+}
+
+// NewMockReviewRepository creates a new mock instance.
+func NewMockReviewRepository(ctrl *gomock.Controller) *MockReviewRepository {
+	mock := &MockReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewRepository) EXPECT() *MockReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetDueCards mocks base method.
+func (m *MockReviewRepository) GetDueCards(ctx context.Context, userUUID, moduleUUID string, dueAt time.Time, limit int) ([]*entity.DueCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteModule", ctx, userUUID, moduleUUID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetDueCards", ctx, userUUID, moduleUUID, dueAt, limit)
+	ret0, _ := ret[0].([]*entity.DueCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteModule indicates an expected call of DeleteModule.
-func (mr *MockModulesRepositoryMockRecorder) DeleteModule(ctx, userUUID, moduleUUID any) *gomock.Call {
+// GetDueCards indicates an expected call of GetDueCards.
+func (mr *MockReviewRepositoryMockRecorder) GetDueCards(ctx, userUUID, moduleUUID, dueAt, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModule", reflect.TypeOf((*MockModulesRepository)(nil).DeleteModule), ctx, userUUID, moduleUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueCards", reflect.TypeOf((*MockReviewRepository)(nil).GetDueCards), ctx, userUUID, moduleUUID, dueAt, limit)
 }
 
-// GetAllModules mocks base method.
-func (m *MockModulesRepository) GetAllModules(ctx context.Context, userUUID string) ([]*entity.Module, error) {
+// GetReviewStates mocks base method.
+func (m *MockReviewRepository) GetReviewStates(ctx context.Context, userUUID string, cardUUIDs []string) (map[string]*entity.ReviewState, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllModules", ctx, userUUID)
-	ret0, _ := ret[0].([]*entity.Module)
+	ret := m.ctrl.Call(m, "GetReviewStates", ctx, userUUID, cardUUIDs)
+	ret0, _ := ret[0].(map[string]*entity.ReviewState)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllModules indicates an expected call of GetAllModules.
-func (mr *MockModulesRepositoryMockRecorder) GetAllModules(ctx, userUUID any) *gomock.Call {
+// GetReviewStates indicates an expected call of GetReviewStates.
+func (mr *MockReviewRepositoryMockRecorder) GetReviewStates(ctx, userUUID, cardUUIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllModules", reflect.TypeOf((*MockModulesRepository)(nil).GetAllModules), ctx, userUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewStates", reflect.TypeOf((*MockReviewRepository)(nil).GetReviewStates), ctx, userUUID, cardUUIDs)
 }
 
-// GetModule mocks base method.
-func (m *MockModulesRepository) GetModule(ctx context.Context, userUUID, moduleUUID string) (*entity.Module, error) {
+// MockSettingsRepository is a mock of SettingsRepository interface.
+type MockSettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSettingsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSettingsRepositoryMockRecorder is the mock recorder for MockSettingsRepository.
+type MockSettingsRepositoryMockRecorder struct {
+	mock *MockSettingsRepository
+}
+
+// NewMockSettingsRepository creates a new mock instance.
+func NewMockSettingsRepository(ctrl *gomock.Controller) *MockSettingsRepository {
+	mock := &MockSettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockSettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSettingsRepository) EXPECT() *MockSettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetModuleSchedulers mocks base method.
+func (m *MockSettingsRepository) GetModuleSchedulers(ctx context.Context, userUUID string, moduleUUIDs []string) (map[string]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModule", ctx, userUUID, moduleUUID)
-	ret0, _ := ret[0].(*entity.Module)
+	ret := m.ctrl.Call(m, "GetModuleSchedulers", ctx, userUUID, moduleUUIDs)
+	ret0, _ := ret[0].(map[string]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetModule indicates an expected call of GetModule.
-func (mr *MockModulesRepositoryMockRecorder) GetModule(ctx, userUUID, moduleUUID any) *gomock.Call {
+// GetModuleSchedulers indicates an expected call of GetModuleSchedulers.
+func (mr *MockSettingsRepositoryMockRecorder) GetModuleSchedulers(ctx, userUUID, moduleUUIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModule", reflect.TypeOf((*MockModulesRepository)(nil).GetModule), ctx, userUUID, moduleUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleSchedulers", reflect.TypeOf((*MockSettingsRepository)(nil).GetModuleSchedulers), ctx, userUUID, moduleUUIDs)
 }
 
-// ModuleExists mocks base method.
-func (m *MockModulesRepository) ModuleExists(ctx context.Context, userUUID, moduleUUID string) (bool, error) {
+// GetModuleSettings mocks base method.
+func (m *MockSettingsRepository) GetModuleSettings(ctx context.Context, userUUID, moduleUUID string) (*entity.ModuleSettings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ModuleExists", ctx, userUUID, moduleUUID)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetModuleSettings", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].(*entity.ModuleSettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ModuleExists indicates an expected call of ModuleExists.
-func (mr *MockModulesRepositoryMockRecorder) ModuleExists(ctx, userUUID, moduleUUID any) *gomock.Call {
+// GetModuleSettings indicates an expected call of GetModuleSettings.
+func (mr *MockSettingsRepositoryMockRecorder) GetModuleSettings(ctx, userUUID, moduleUUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleExists", reflect.TypeOf((*MockModulesRepository)(nil).ModuleExists), ctx, userUUID, moduleUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleSettings", reflect.TypeOf((*MockSettingsRepository)(nil).GetModuleSettings), ctx, userUUID, moduleUUID)
 }
 
-// UpdateModule mocks base method.
-func (m *MockModulesRepository) UpdateModule(ctx context.Context, userUUID, moduleUUID, moduleName string) (*entity.Module, error) {
+// GetUserSettings mocks base method.
+func (m *MockSettingsRepository) GetUserSettings(ctx context.Context, userUUID string) (*entity.UserSettings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateModule", ctx, userUUID, moduleUUID, moduleName)
-	ret0, _ := ret[0].(*entity.Module)
+	ret := m.ctrl.Call(m, "GetUserSettings", ctx, userUUID)
+	ret0, _ := ret[0].(*entity.UserSettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateModule indicates an expected call of UpdateModule.
-func (mr *MockModulesRepositoryMockRecorder) UpdateModule(ctx, userUUID, moduleUUID, moduleName any) *gomock.Call {
+// GetUserSettings indicates an expected call of GetUserSettings.
+func (mr *MockSettingsRepositoryMockRecorder) GetUserSettings(ctx, userUUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModule", reflect.TypeOf((*MockModulesRepository)(nil).UpdateModule), ctx, userUUID, moduleUUID, moduleName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSettings", reflect.TypeOf((*MockSettingsRepository)(nil).GetUserSettings), ctx, userUUID)
 }
 
-// MockCardsRepository is a mock of CardsRepository interface.
-type MockCardsRepository struct {
+// SaveModuleSettings mocks base method.
+func (m *MockSettingsRepository) SaveModuleSettings(ctx context.Context, userUUID, moduleUUID string, settings *entity.ModuleSettings) (*entity.ModuleSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveModuleSettings", ctx, userUUID, moduleUUID, settings)
+	ret0, _ := ret[0].(*entity.ModuleSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveModuleSettings indicates an expected call of SaveModuleSettings.
+func (mr *MockSettingsRepositoryMockRecorder) SaveModuleSettings(ctx, userUUID, moduleUUID, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveModuleSettings", reflect.TypeOf((*MockSettingsRepository)(nil).SaveModuleSettings), ctx, userUUID, moduleUUID, settings)
+}
+
+// SaveUserSettings mocks base method.
+func (m *MockSettingsRepository) SaveUserSettings(ctx context.Context, userUUID string, settings *entity.UserSettings) (*entity.UserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserSettings", ctx, userUUID, settings)
+	ret0, _ := ret[0].(*entity.UserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveUserSettings indicates an expected call of SaveUserSettings.
+func (mr *MockSettingsRepositoryMockRecorder) SaveUserSettings(ctx, userUUID, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSettings", reflect.TypeOf((*MockSettingsRepository)(nil).SaveUserSettings), ctx, userUUID, settings)
+}
+
+// MockSessionsRepository is a mock of SessionsRepository interface.
+type MockSessionsRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockCardsRepositoryMockRecorder
+	recorder *MockSessionsRepositoryMockRecorder
 	isgomock struct{}
 }
 
-// MockCardsRepositoryMockRecorder is the mock recorder for MockCardsRepository.
-type MockCardsRepositoryMockRecorder struct {
-	mock *MockCardsRepository
+// MockSessionsRepositoryMockRecorder is the mock recorder for MockSessionsRepository.
+type MockSessionsRepositoryMockRecorder struct {
+	mock *MockSessionsRepository
 }
 
-// NewMockCardsRepository creates a new mock instance.
-func NewMockCardsRepository(ctrl *gomock.Controller) *MockCardsRepository {
-	mock := &MockCardsRepository{ctrl: ctrl}
-	mock.recorder = &MockCardsRepositoryMockRecorder{mock}
+// NewMockSessionsRepository creates a new mock instance.
+func NewMockSessionsRepository(ctrl *gomock.Controller) *MockSessionsRepository {
+	mock := &MockSessionsRepository{ctrl: ctrl}
+	mock.recorder = &MockSessionsRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockCardsRepository) EXPECT() *MockCardsRepositoryMockRecorder {
+func (m *MockSessionsRepository) EXPECT() *MockSessionsRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CreateCard mocks base method.
-func (m *MockCardsRepository) CreateCard(ctx context.Context, card *entity.Card) (*entity.Card, error) {
+// CreateSession mocks base method.
+func (m *MockSessionsRepository) CreateSession(ctx context.Context, userUUID, moduleUUID string) (*entity.StudySession, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCard", ctx, card)
-	ret0, _ := ret[0].(*entity.Card)
+	ret := m.ctrl.Call(m, "CreateSession", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].(*entity.StudySession)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateCard indicates an expected call of CreateCard.
-func (mr *MockCardsRepositoryMockRecorder) CreateCard(ctx, card any) *gomock.Call {
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionsRepositoryMockRecorder) CreateSession(ctx, userUUID, moduleUUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCard", reflect.TypeOf((*MockCardsRepository)(nil).CreateCard), ctx, card)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionsRepository)(nil).CreateSession), ctx, userUUID, moduleUUID)
 }
 
-// DeleteCard mocks base method.
-func (m *MockCardsRepository) DeleteCard(ctx context.Context, moduleUUID, cardUUID string) error {
+// FinishSession mocks base method.
+func (m *MockSessionsRepository) FinishSession(ctx context.Context, userUUID, moduleUUID, sessionUUID string) (*entity.StudySession, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCard", ctx, moduleUUID, cardUUID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FinishSession", ctx, userUUID, moduleUUID, sessionUUID)
+	ret0, _ := ret[0].(*entity.StudySession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteCard indicates an expected call of DeleteCard.
-func (mr *MockCardsRepositoryMockRecorder) DeleteCard(ctx, moduleUUID, cardUUID any) *gomock.Call {
+// FinishSession indicates an expected call of FinishSession.
+func (mr *MockSessionsRepositoryMockRecorder) FinishSession(ctx, userUUID, moduleUUID, sessionUUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCard", reflect.TypeOf((*MockCardsRepository)(nil).DeleteCard), ctx, moduleUUID, cardUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSession", reflect.TypeOf((*MockSessionsRepository)(nil).FinishSession), ctx, userUUID, moduleUUID, sessionUUID)
 }
 
-// GetModuleCards mocks base method.
-func (m *MockCardsRepository) GetModuleCards(ctx context.Context, moduleUUID string) ([]*entity.Card, error) {
+// GetModuleSessions mocks base method.
+func (m *MockSessionsRepository) GetModuleSessions(ctx context.Context, userUUID, moduleUUID string) ([]*entity.StudySession, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModuleCards", ctx, moduleUUID)
-	ret0, _ := ret[0].([]*entity.Card)
+	ret := m.ctrl.Call(m, "GetModuleSessions", ctx, userUUID, moduleUUID)
+	ret0, _ := ret[0].([]*entity.StudySession)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetModuleCards indicates an expected call of GetModuleCards.
-func (mr *MockCardsRepositoryMockRecorder) GetModuleCards(ctx, moduleUUID any) *gomock.Call {
+// GetModuleSessions indicates an expected call of GetModuleSessions.
+func (mr *MockSessionsRepositoryMockRecorder) GetModuleSessions(ctx, userUUID, moduleUUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleCards", reflect.TypeOf((*MockCardsRepository)(nil).GetModuleCards), ctx, moduleUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleSessions", reflect.TypeOf((*MockSessionsRepository)(nil).GetModuleSessions), ctx, userUUID, moduleUUID)
 }
 
-// SaveCard mocks base method.
-func (m *MockCardsRepository) SaveCard(ctx context.Context, card *entity.Card) (*entity.Card, error) {
+// MockScheduler is a mock of Scheduler interface.
+type MockScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerMockRecorder
+	isgomock struct{}
+}
+
+// MockSchedulerMockRecorder is the mock recorder for MockScheduler.
+type MockSchedulerMockRecorder struct {
+	mock *MockScheduler
+}
+
+// NewMockScheduler creates a new mock instance.
+func NewMockScheduler(ctrl *gomock.Controller) *MockScheduler {
+	mock := &MockScheduler{ctrl: ctrl}
+	mock.recorder = &MockSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduler) EXPECT() *MockSchedulerMockRecorder {
+	return m.recorder
+}
+
+// Review mocks base method.
+func (m *MockScheduler) Review(state srs.State, grade srs.Grade, reviewedAt time.Time) srs.State {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveCard", ctx, card)
-	ret0, _ := ret[0].(*entity.Card)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Review", state, grade, reviewedAt)
+	ret0, _ := ret[0].(srs.State)
+	return ret0
 }
 
-// SaveCard indicates an expected call of SaveCard.
-func (mr *MockCardsRepositoryMockRecorder) SaveCard(ctx, card any) *gomock.Call {
+// Review indicates an expected call of Review.
+func (mr *MockSchedulerMockRecorder) Review(state, grade, reviewedAt any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCard", reflect.TypeOf((*MockCardsRepository)(nil).SaveCard), ctx, card)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockScheduler)(nil).Review), state, grade, reviewedAt)
 }
 
 // MockJWTIssuer is a mock of JWTIssuer interface.
@@ -475,3 +1246,170 @@ func (mr *MockCSVImportWorkerPoolMockRecorder) QueueWork(w any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWork", reflect.TypeOf((*MockCSVImportWorkerPool)(nil).QueueWork), w)
 }
+
+// MockAPKGImportWorkerPool is a mock of APKGImportWorkerPool interface.
+type MockAPKGImportWorkerPool struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPKGImportWorkerPoolMockRecorder
+	isgomock struct{}
+}
+
+// MockAPKGImportWorkerPoolMockRecorder is the mock recorder for MockAPKGImportWorkerPool.
+type MockAPKGImportWorkerPoolMockRecorder struct {
+	mock *MockAPKGImportWorkerPool
+}
+
+// NewMockAPKGImportWorkerPool creates a new mock instance.
+func NewMockAPKGImportWorkerPool(ctrl *gomock.Controller) *MockAPKGImportWorkerPool {
+	mock := &MockAPKGImportWorkerPool{ctrl: ctrl}
+	mock.recorder = &MockAPKGImportWorkerPoolMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPKGImportWorkerPool) EXPECT() *MockAPKGImportWorkerPoolMockRecorder {
+	return m.recorder
+}
+
+// QueueWork mocks base method.
+func (m *MockAPKGImportWorkerPool) QueueWork(w *usecase.APKGImportWork) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueWork", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueWork indicates an expected call of QueueWork.
+func (mr *MockAPKGImportWorkerPoolMockRecorder) QueueWork(w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWork", reflect.TypeOf((*MockAPKGImportWorkerPool)(nil).QueueWork), w)
+}
+
+// MockXLSXImportWorkerPool is a mock of XLSXImportWorkerPool interface.
+type MockXLSXImportWorkerPool struct {
+	ctrl     *gomock.Controller
+	recorder *MockXLSXImportWorkerPoolMockRecorder
+	isgomock struct{}
+}
+
+// MockXLSXImportWorkerPoolMockRecorder is the mock recorder for MockXLSXImportWorkerPool.
+type MockXLSXImportWorkerPoolMockRecorder struct {
+	mock *MockXLSXImportWorkerPool
+}
+
+// NewMockXLSXImportWorkerPool creates a new mock instance.
+func NewMockXLSXImportWorkerPool(ctrl *gomock.Controller) *MockXLSXImportWorkerPool {
+	mock := &MockXLSXImportWorkerPool{ctrl: ctrl}
+	mock.recorder = &MockXLSXImportWorkerPoolMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockXLSXImportWorkerPool) EXPECT() *MockXLSXImportWorkerPoolMockRecorder {
+	return m.recorder
+}
+
+// QueueWork mocks base method.
+func (m *MockXLSXImportWorkerPool) QueueWork(w *usecase.XLSXImportWork) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueWork", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueWork indicates an expected call of QueueWork.
+func (mr *MockXLSXImportWorkerPoolMockRecorder) QueueWork(w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWork", reflect.TypeOf((*MockXLSXImportWorkerPool)(nil).QueueWork), w)
+}
+
+// MockLibraryExportWorkerPool is a mock of LibraryExportWorkerPool interface.
+type MockLibraryExportWorkerPool struct {
+	ctrl     *gomock.Controller
+	recorder *MockLibraryExportWorkerPoolMockRecorder
+	isgomock struct{}
+}
+
+// MockLibraryExportWorkerPoolMockRecorder is the mock recorder for MockLibraryExportWorkerPool.
+type MockLibraryExportWorkerPoolMockRecorder struct {
+	mock *MockLibraryExportWorkerPool
+}
+
+// NewMockLibraryExportWorkerPool creates a new mock instance.
+func NewMockLibraryExportWorkerPool(ctrl *gomock.Controller) *MockLibraryExportWorkerPool {
+	mock := &MockLibraryExportWorkerPool{ctrl: ctrl}
+	mock.recorder = &MockLibraryExportWorkerPoolMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLibraryExportWorkerPool) EXPECT() *MockLibraryExportWorkerPoolMockRecorder {
+	return m.recorder
+}
+
+// QueueWork mocks base method.
+func (m *MockLibraryExportWorkerPool) QueueWork(w *usecase.LibraryExportWork) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueWork", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueWork indicates an expected call of QueueWork.
+func (mr *MockLibraryExportWorkerPoolMockRecorder) QueueWork(w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWork", reflect.TypeOf((*MockLibraryExportWorkerPool)(nil).QueueWork), w)
+}
+
+// MockJobEventsNotifier is a mock of JobEventsNotifier interface.
+type MockJobEventsNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobEventsNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockJobEventsNotifierMockRecorder is the mock recorder for MockJobEventsNotifier.
+type MockJobEventsNotifierMockRecorder struct {
+	mock *MockJobEventsNotifier
+}
+
+// NewMockJobEventsNotifier creates a new mock instance.
+func NewMockJobEventsNotifier(ctrl *gomock.Controller) *MockJobEventsNotifier {
+	mock := &MockJobEventsNotifier{ctrl: ctrl}
+	mock.recorder = &MockJobEventsNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobEventsNotifier) EXPECT() *MockJobEventsNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockJobEventsNotifier) Notify(ctx context.Context, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockJobEventsNotifierMockRecorder) Notify(ctx, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockJobEventsNotifier)(nil).Notify), ctx, payload)
+}
+
+// Subscribe mocks base method.
+func (m *MockJobEventsNotifier) Subscribe() (<-chan []byte, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe")
+	ret0, _ := ret[0].(<-chan []byte)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockJobEventsNotifierMockRecorder) Subscribe() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockJobEventsNotifier)(nil).Subscribe))
+}
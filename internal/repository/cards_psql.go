@@ -57,6 +57,31 @@ func (repo *CardsRepository) GetModuleCards(
 	return cards, nil
 }
 
+func (repo *CardsRepository) GetCard(
+	ctx context.Context,
+	moduleUUID string,
+	cardUUID string,
+) (*entity.Card, error) {
+	var card entity.Card
+
+	row := repo.conn.QueryRowContext(ctx, `
+		SELECT uuid, term, meaning, module_uuid
+		FROM cards
+		WHERE uuid=$1 AND module_uuid=$2;
+	`, cardUUID, moduleUUID)
+
+	err := row.Scan(&card.UUID, &card.Term, &card.Meaning, &card.ModuleUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.CardNotFoundError{UUID: cardUUID}
+		}
+
+		return nil, err
+	}
+
+	return &card, nil
+}
+
 func (repo *CardsRepository) CreateCard(ctx context.Context, card *entity.Card) (*entity.Card, error) {
 	var storedCard entity.Card
 
@@ -75,6 +100,7 @@ func (repo *CardsRepository) CreateCard(ctx context.Context, card *entity.Card)
 	return &storedCard, nil
 }
 
+// SaveCard updates the card and releases it from leeches, so the card returns to review queues.
 func (repo *CardsRepository) SaveCard(ctx context.Context, card *entity.Card) (*entity.Card, error) {
 	var storedCard entity.Card
 
@@ -99,10 +125,17 @@ func (repo *CardsRepository) SaveCard(ctx context.Context, card *entity.Card) (*
 
 	//nolint:gosec
 	query := fmt.Sprintf(`
-		UPDATE cards
-		SET %s
-		WHERE uuid=$%d AND module_uuid=$%d
-		RETURNING uuid, module_uuid, term, meaning;
+		WITH updated_card AS (
+			UPDATE cards
+			SET %s
+			WHERE uuid=$%d AND module_uuid=$%d
+			RETURNING uuid, module_uuid, term, meaning
+		), released_states AS (
+			UPDATE review_states
+			SET is_leech=false, is_suspended=false, updated_at=CURRENT_TIMESTAMP
+			WHERE card_uuid IN (SELECT uuid FROM updated_card)
+		)
+		SELECT uuid, module_uuid, term, meaning FROM updated_card;
 	`, strings.Join(setParts, ","), len(setParts)+1, len(setParts)+2)
 
 	args = append(args, card.UUID, card.ModuleUUID)
@@ -124,3 +157,139 @@ func (repo *CardsRepository) DeleteCard(ctx context.Context, moduleUUID string,
 
 	return err
 }
+
+// GetLeeches returns user's leech cards, most failed first. Empty moduleUUID means all user's modules.
+func (repo *CardsRepository) GetLeeches(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) ([]*entity.LeechCard, error) {
+	cards := make([]*entity.LeechCard, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			c.uuid,
+			c.term,
+			c.meaning,
+			c.module_uuid,
+			rs.ease_factor,
+			rs.stability,
+			rs.difficulty,
+			rs.interval_days,
+			rs.repetitions,
+			rs.lapses,
+			rs.due_at,
+			rs.last_reviewed_at,
+			rs.is_leech,
+			rs.is_suspended
+		FROM review_states rs
+		JOIN cards c ON c.uuid = rs.card_uuid
+		WHERE
+			rs.user_uuid=$1
+			AND rs.is_leech
+			AND ($2::text = '' OR rs.module_uuid = NULLIF($2::text, '')::uuid)
+		ORDER BY rs.lapses DESC, c.created_at;
+	`, userUUID, moduleUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			card  entity.LeechCard
+			state entity.ReviewState
+		)
+
+		err = rows.Scan(
+			&card.UUID,
+			&card.Term,
+			&card.Meaning,
+			&card.ModuleUUID,
+			&state.EaseFactor,
+			&state.Stability,
+			&state.Difficulty,
+			&state.IntervalDays,
+			&state.Repetitions,
+			&state.Lapses,
+			&state.DueAt,
+			&state.LastReviewedAt,
+			&state.IsLeech,
+			&state.IsSuspended,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		state.CardUUID = card.UUID
+		state.ModuleUUID = card.ModuleUUID
+		card.ReviewState = &state
+
+		cards = append(cards, &card)
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cards, nil
+		}
+
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// SetCardSuspended suspends or resumes reviews of the card. Only reviewed cards can be suspended.
+func (repo *CardsRepository) SetCardSuspended(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	cardUUID string,
+	isSuspended bool,
+) (*entity.ReviewState, error) {
+	var state entity.ReviewState
+
+	row := repo.conn.QueryRowContext(ctx, `
+		UPDATE review_states
+		SET is_suspended=$4, updated_at=CURRENT_TIMESTAMP
+		WHERE user_uuid=$1 AND module_uuid=$2 AND card_uuid=$3
+		RETURNING
+			card_uuid,
+			module_uuid,
+			ease_factor,
+			stability,
+			difficulty,
+			interval_days,
+			repetitions,
+			lapses,
+			due_at,
+			last_reviewed_at,
+			is_leech,
+			is_suspended;
+	`, userUUID, moduleUUID, cardUUID, isSuspended)
+
+	err := row.Scan(
+		&state.CardUUID,
+		&state.ModuleUUID,
+		&state.EaseFactor,
+		&state.Stability,
+		&state.Difficulty,
+		&state.IntervalDays,
+		&state.Repetitions,
+		&state.Lapses,
+		&state.DueAt,
+		&state.LastReviewedAt,
+		&state.IsLeech,
+		&state.IsSuspended,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.CardNotFoundError{UUID: cardUUID}
+		}
+
+		return nil, err
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type ExportJobsRepository struct {
+	conn *sql.DB
+}
+
+func NewExportJobsRepository(conn *sql.DB) *ExportJobsRepository {
+	return &ExportJobsRepository{conn: conn}
+}
+
+type exportJobRow struct {
+	job          entity.ExportJob
+	moduleUUIDs  sql.NullString
+	errorMessage sql.NullString
+	expiresAt    sql.NullTime
+}
+
+func (row *exportJobRow) dest() []any {
+	return []any{
+		&row.job.UUID,
+		&row.job.UserUUID,
+		&row.job.Format,
+		&row.job.Status,
+		&row.moduleUUIDs,
+		&row.job.ModulesCount,
+		&row.job.CardsCount,
+		&row.job.ArchiveSize,
+		&row.errorMessage,
+		&row.expiresAt,
+		&row.job.CreatedAt,
+		&row.job.UpdatedAt,
+	}
+}
+
+func (row *exportJobRow) toEntity() *entity.ExportJob {
+	job := row.job
+	job.ErrorMessage = row.errorMessage.String
+
+	if row.moduleUUIDs.String != "" {
+		job.ModuleUUIDs = strings.Split(row.moduleUUIDs.String, ",")
+	}
+
+	if row.expiresAt.Valid {
+		expiresAt := row.expiresAt.Time
+		job.ExpiresAt = &expiresAt
+	}
+
+	return &job
+}
+
+func (repo *ExportJobsRepository) CreateExportJob(
+	ctx context.Context,
+	job *entity.ExportJob,
+) (*entity.ExportJob, error) {
+	var row exportJobRow
+
+	err := repo.conn.QueryRowContext(ctx, `
+		INSERT INTO export_jobs (user_uuid, format, module_uuids)
+		VALUES
+			($1, $2, string_to_array(NULLIF($3::text, ''), ',')::uuid[])
+		RETURNING
+			uuid,
+			user_uuid,
+			format,
+			status,
+			array_to_string(module_uuids, ','),
+			modules_count,
+			cards_count,
+			archive_size,
+			error_message,
+			expires_at,
+			created_at,
+			updated_at;
+	`, job.UserUUID, job.Format, strings.Join(job.ModuleUUIDs, ",")).Scan(row.dest()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// UpdateExportJob stores the job's status and error, the archive is stored with SaveExportArchive.
+func (repo *ExportJobsRepository) UpdateExportJob(ctx context.Context, job *entity.ExportJob) error {
+	_, err := repo.conn.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET
+			status=$2,
+			modules_count=$3,
+			cards_count=$4,
+			error_message=NULLIF($5::text, ''),
+			updated_at=CURRENT_TIMESTAMP
+		WHERE uuid=$1;
+	`,
+		job.UUID,
+		job.Status,
+		job.ModulesCount,
+		job.CardsCount,
+		job.ErrorMessage,
+	)
+
+	return err
+}
+
+// SaveExportArchive stores the archive together with the job's result and expiration time.
+func (repo *ExportJobsRepository) SaveExportArchive(
+	ctx context.Context,
+	job *entity.ExportJob,
+	archive []byte,
+) error {
+	_, err := repo.conn.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET
+			status=$2,
+			modules_count=$3,
+			cards_count=$4,
+			archive=$5,
+			archive_size=$6,
+			error_message=NULL,
+			expires_at=$7,
+			updated_at=CURRENT_TIMESTAMP
+		WHERE uuid=$1;
+	`,
+		job.UUID,
+		job.Status,
+		job.ModulesCount,
+		job.CardsCount,
+		archive,
+		job.ArchiveSize,
+		job.ExpiresAt,
+	)
+
+	return err
+}
+
+func (repo *ExportJobsRepository) GetExportJob(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) (*entity.ExportJob, error) {
+	var row exportJobRow
+
+	err := repo.conn.QueryRowContext(ctx, `
+		SELECT
+			uuid,
+			user_uuid,
+			format,
+			status,
+			array_to_string(module_uuids, ','),
+			modules_count,
+			cards_count,
+			archive_size,
+			error_message,
+			expires_at,
+			created_at,
+			updated_at
+		FROM export_jobs
+		WHERE uuid=$1 AND user_uuid=$2;
+	`, jobUUID, userUUID).Scan(row.dest()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.ExportJobNotFoundError{UUID: jobUUID}
+		}
+
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// GetExportArchive returns the job's archive unless it has expired, ErrExportArchiveExpired is returned then.
+func (repo *ExportJobsRepository) GetExportArchive(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) ([]byte, error) {
+	var archive []byte
+
+	err := repo.conn.QueryRowContext(ctx, `
+		SELECT archive
+		FROM export_jobs
+		WHERE uuid=$1 AND user_uuid=$2 AND archive IS NOT NULL AND expires_at > CURRENT_TIMESTAMP;
+	`, jobUUID, userUUID).Scan(&archive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.ErrExportArchiveExpired
+		}
+
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// DeleteExpiredExportArchives frees the storage of expired archives, the jobs themselves are kept.
+func (repo *ExportJobsRepository) DeleteExpiredExportArchives(ctx context.Context) (int64, error) {
+	result, err := repo.conn.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET archive=NULL
+		WHERE archive IS NOT NULL AND expires_at <= CURRENT_TIMESTAMP;
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+const (
+	importJobsListLimit  = 100
+	importErrorColsCount = 4
+)
+
+type ImportJobsRepository struct {
+	conn *sql.DB
+}
+
+func NewImportJobsRepository(conn *sql.DB) *ImportJobsRepository {
+	return &ImportJobsRepository{conn: conn}
+}
+
+type importJobRow struct {
+	job          entity.ImportJob
+	moduleUUID   sql.NullString
+	moduleUUIDs  sql.NullString
+	strategy     sql.NullString
+	errorMessage sql.NullString
+}
+
+func (row *importJobRow) dest() []any {
+	return []any{
+		&row.job.UUID,
+		&row.job.UserUUID,
+		&row.job.Source,
+		&row.job.Status,
+		&row.job.ModuleName,
+		&row.moduleUUID,
+		&row.moduleUUIDs,
+		&row.strategy,
+		&row.job.CardsCount,
+		&row.job.UpdatedCardsCount,
+		&row.job.SkippedCardsCount,
+		&row.job.ErrorsCount,
+		&row.errorMessage,
+		&row.job.CreatedAt,
+		&row.job.UpdatedAt,
+	}
+}
+
+func (row *importJobRow) toEntity() *entity.ImportJob {
+	job := row.job
+	job.ModuleUUID = row.moduleUUID.String
+	job.Strategy = row.strategy.String
+	job.ErrorMessage = row.errorMessage.String
+
+	if row.moduleUUIDs.String != "" {
+		job.ModuleUUIDs = strings.Split(row.moduleUUIDs.String, ",")
+	}
+
+	return &job
+}
+
+func (repo *ImportJobsRepository) CreateImportJob(
+	ctx context.Context,
+	job *entity.ImportJob,
+) (*entity.ImportJob, error) {
+	var row importJobRow
+
+	err := repo.conn.QueryRowContext(ctx, `
+		INSERT INTO import_jobs (user_uuid, source, module_name, module_uuid, strategy)
+		VALUES
+			($1, $2, $3, NULLIF($4::text, '')::uuid, NULLIF($5::text, ''))
+		RETURNING
+			uuid,
+			user_uuid,
+			source,
+			status,
+			module_name,
+			module_uuid,
+			array_to_string(module_uuids, ','),
+			strategy,
+			cards_count,
+			updated_cards_count,
+			skipped_cards_count,
+			errors_count,
+			error_message,
+			created_at,
+			updated_at;
+	`, job.UserUUID, job.Source, job.ModuleName, job.ModuleUUID, job.Strategy).Scan(row.dest()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// UpdateImportJob stores the job's status and result. Only created and processing jobs are updated,
+// ErrImportJobCanceled is returned for a canceled job and ErrImportJobFinished for a processed or failed one.
+func (repo *ImportJobsRepository) UpdateImportJob(ctx context.Context, job *entity.ImportJob) error {
+	result, err := repo.conn.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET
+			status=$2,
+			module_uuid=NULLIF($3::text, '')::uuid,
+			module_uuids=string_to_array(NULLIF($9::text, ''), ',')::uuid[],
+			cards_count=$4,
+			updated_cards_count=$5,
+			skipped_cards_count=$6,
+			errors_count=$7,
+			error_message=NULLIF($8::text, ''),
+			updated_at=CURRENT_TIMESTAMP
+		WHERE uuid=$1 AND status IN ('created', 'processing');
+	`,
+		job.UUID,
+		job.Status,
+		job.ModuleUUID,
+		job.CardsCount,
+		job.UpdatedCardsCount,
+		job.SkippedCardsCount,
+		job.ErrorsCount,
+		job.ErrorMessage,
+		strings.Join(job.ModuleUUIDs, ","),
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return repo.notUpdatableJobError(ctx, job.UUID)
+	}
+
+	return nil
+}
+
+// notUpdatableJobError tells apart a canceled job and a finished one.
+func (repo *ImportJobsRepository) notUpdatableJobError(ctx context.Context, jobUUID string) error {
+	var status string
+
+	err := repo.conn.QueryRowContext(ctx, `
+		SELECT status
+		FROM import_jobs
+		WHERE uuid=$1;
+	`, jobUUID).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	if status == entity.ImportStatusCanceled {
+		return entity.ErrImportJobCanceled
+	}
+
+	return entity.ErrImportJobFinished
+}
+
+// GetImportJobs returns the latest user's import jobs, newest first.
+func (repo *ImportJobsRepository) GetImportJobs(
+	ctx context.Context,
+	userUUID string,
+) ([]*entity.ImportJob, error) {
+	jobs := make([]*entity.ImportJob, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			uuid,
+			user_uuid,
+			source,
+			status,
+			module_name,
+			module_uuid,
+			array_to_string(module_uuids, ','),
+			strategy,
+			cards_count,
+			updated_cards_count,
+			skipped_cards_count,
+			errors_count,
+			error_message,
+			created_at,
+			updated_at
+		FROM import_jobs
+		WHERE user_uuid=$1
+		ORDER BY created_at DESC
+		LIMIT $2;
+	`, userUUID, importJobsListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var row importJobRow
+
+		err = rows.Scan(row.dest()...)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, row.toEntity())
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return jobs, nil
+		}
+
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (repo *ImportJobsRepository) GetImportJob(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) (*entity.ImportJob, error) {
+	var row importJobRow
+
+	err := repo.conn.QueryRowContext(ctx, `
+		SELECT
+			uuid,
+			user_uuid,
+			source,
+			status,
+			module_name,
+			module_uuid,
+			array_to_string(module_uuids, ','),
+			strategy,
+			cards_count,
+			updated_cards_count,
+			skipped_cards_count,
+			errors_count,
+			error_message,
+			created_at,
+			updated_at
+		FROM import_jobs
+		WHERE uuid=$1 AND user_uuid=$2;
+	`, jobUUID, userUUID).Scan(row.dest()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.ImportJobNotFoundError{UUID: jobUUID}
+		}
+
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// CancelImportJob cancels the job unless it's already finished.
+func (repo *ImportJobsRepository) CancelImportJob(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) (*entity.ImportJob, error) {
+	var row importJobRow
+
+	err := repo.conn.QueryRowContext(ctx, `
+		UPDATE import_jobs
+		SET
+			status='canceled',
+			updated_at=CURRENT_TIMESTAMP
+		WHERE uuid=$1 AND user_uuid=$2 AND status IN ('created', 'processing')
+		RETURNING
+			uuid,
+			user_uuid,
+			source,
+			status,
+			module_name,
+			module_uuid,
+			array_to_string(module_uuids, ','),
+			strategy,
+			cards_count,
+			updated_cards_count,
+			skipped_cards_count,
+			errors_count,
+			error_message,
+			created_at,
+			updated_at;
+	`, jobUUID, userUUID).Scan(row.dest()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repo.notCancelableJobError(ctx, userUUID, jobUUID)
+		}
+
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// notCancelableJobError tells apart a missing job and a finished one.
+func (repo *ImportJobsRepository) notCancelableJobError(ctx context.Context, userUUID string, jobUUID string) error {
+	_, err := repo.GetImportJob(ctx, userUUID, jobUUID)
+	if err != nil {
+		return err
+	}
+
+	return entity.ErrImportJobFinished
+}
+
+// SaveImportJobErrors replaces the job's row errors, so a retried import doesn't duplicate them.
+func (repo *ImportJobsRepository) SaveImportJobErrors(
+	ctx context.Context,
+	jobUUID string,
+	rowErrors []*entity.ImportRowError,
+) error {
+	tx, err := repo.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM import_job_errors
+		WHERE job_uuid=$1;
+	`, jobUUID)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+
+		return err
+	}
+
+	if len(rowErrors) == 0 {
+		return tx.Commit()
+	}
+
+	insertParts := make([]string, 0, len(rowErrors))
+	args := make([]any, 0, len(rowErrors)*importErrorColsCount)
+
+	for i, rowErr := range rowErrors {
+		base := i * importErrorColsCount
+		part := fmt.Sprintf("($%d, $%d, $%d, $%d)", base+1, base+2, base+3, base+4)
+
+		insertParts = append(insertParts, part)
+		args = append(args, jobUUID, rowErr.Line, rowErr.Reason, rowErr.Raw)
+	}
+
+	//nolint:gosec
+	query := fmt.Sprintf(`
+		INSERT INTO import_job_errors (job_uuid, line, reason, raw)
+		VALUES %s;
+	`, strings.Join(insertParts, ","))
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *ImportJobsRepository) GetImportJobErrors(
+	ctx context.Context,
+	jobUUID string,
+) ([]*entity.ImportRowError, error) {
+	rowErrors := make([]*entity.ImportRowError, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT line, reason, raw
+		FROM import_job_errors
+		WHERE job_uuid=$1
+		ORDER BY line, id;
+	`, jobUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var rowErr entity.ImportRowError
+
+		err = rows.Scan(&rowErr.Line, &rowErr.Reason, &rowErr.Raw)
+		if err != nil {
+			return nil, err
+		}
+
+		rowErrors = append(rowErrors, &rowErr)
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rowErrors, nil
+		}
+
+		return nil, err
+	}
+
+	return rowErrors, nil
+}
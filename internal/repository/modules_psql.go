@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/llravell/simple-cards/internal/entity"
 )
 
+const insertCardsBatchSize = 1000
+
 type ModulesRepository struct {
 	conn *sql.DB
 }
@@ -18,17 +22,100 @@ func NewModulesRepository(conn *sql.DB) *ModulesRepository {
 	return &ModulesRepository{conn: conn}
 }
 
+// GetAllModules returns user's modules with learning progress.
+// Cards are due today until the end of the day in the user's time zone, suspended cards are never due.
 func (repo *ModulesRepository) GetAllModules(
 	ctx context.Context,
 	userUUID string,
 ) ([]*entity.Module, error) {
 	modules := make([]*entity.Module, 0)
 
-	rows, err := repo.conn.QueryContext(
-		ctx,
-		"SELECT uuid, name, user_uuid FROM modules WHERE user_uuid=$1",
-		userUUID,
-	)
+	rows, err := repo.conn.QueryContext(ctx, `
+		WITH user_timezone AS (
+			SELECT COALESCE((SELECT timezone FROM user_settings WHERE user_uuid=$1), 'UTC') AS name
+		), today AS (
+			SELECT (DATE_TRUNC('day', CURRENT_TIMESTAMP AT TIME ZONE tz.name) + INTERVAL '1 day') AT TIME ZONE tz.name AS ends_at
+			FROM user_timezone tz
+		)
+		SELECT
+			m.uuid,
+			m.name,
+			m.user_uuid,
+			COUNT(c.uuid),
+			COUNT(c.uuid) FILTER (WHERE rs.card_uuid IS NULL),
+			COUNT(c.uuid) FILTER (WHERE rs.interval_days < $2),
+			COUNT(c.uuid) FILTER (WHERE rs.interval_days >= $2),
+			COUNT(c.uuid) FILTER (WHERE rs.due_at < today.ends_at AND NOT rs.is_suspended),
+			MAX(rs.last_reviewed_at)
+		FROM modules m
+		CROSS JOIN today
+		LEFT JOIN cards c ON c.module_uuid = m.uuid
+		LEFT JOIN review_states rs ON rs.card_uuid = c.uuid AND rs.user_uuid = m.user_uuid
+		WHERE m.user_uuid=$1
+		GROUP BY m.uuid, m.name, m.user_uuid;
+	`, userUUID, entity.MasteredIntervalDays)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			module        entity.Module
+			progress      entity.ModuleProgress
+			lastStudiedAt sql.NullTime
+		)
+
+		err = rows.Scan(
+			&module.UUID,
+			&module.Name,
+			&module.UserUUID,
+			&progress.CardsCount,
+			&progress.NewCount,
+			&progress.LearningCount,
+			&progress.MasteredCount,
+			&progress.DueTodayCount,
+			&lastStudiedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if lastStudiedAt.Valid {
+			progress.LastStudiedAt = &lastStudiedAt.Time
+		}
+
+		module.Progress = &progress
+		modules = append(modules, &module)
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return modules, nil
+		}
+
+		return nil, err
+	}
+
+	return modules, nil
+}
+
+// GetLibraryModules returns user's modules without learning progress in the order of their creation.
+// Only the given modules are returned unless moduleUUIDs is empty.
+func (repo *ModulesRepository) GetLibraryModules(
+	ctx context.Context,
+	userUUID string,
+	moduleUUIDs []string,
+) ([]*entity.Module, error) {
+	modules := make([]*entity.Module, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT uuid, name, user_uuid
+		FROM modules
+		WHERE user_uuid=$1 AND (COALESCE(cardinality($2::uuid[]), 0) = 0 OR uuid = ANY($2::uuid[]))
+		ORDER BY created_at, uuid;
+	`, userUUID, moduleUUIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -82,19 +169,59 @@ func (repo *ModulesRepository) CreateNewModule(
 func (repo *ModulesRepository) CreateNewModuleWithCards(
 	ctx context.Context,
 	moduleWithCards *entity.ModuleWithCards,
+) error {
+	return repo.CreateNewModulesWithCards(ctx, []*entity.ModuleWithCards{moduleWithCards})
+}
+
+// CreateNewModulesWithCards creates all the modules or none of them.
+func (repo *ModulesRepository) CreateNewModulesWithCards(
+	ctx context.Context,
+	modulesWithCards []*entity.ModuleWithCards,
 ) error {
 	tx, err := repo.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	row := tx.QueryRowContext(ctx, `
-		INSERT INTO modules (name, user_uuid)
-		VALUES ($1, $2)
-		RETURNING uuid;
-	`, moduleWithCards.Name, moduleWithCards.UserUUID)
+	for _, moduleWithCards := range modulesWithCards {
+		row := tx.QueryRowContext(ctx, `
+			INSERT INTO modules (name, user_uuid)
+			VALUES ($1, $2)
+			RETURNING uuid;
+		`, moduleWithCards.Name, moduleWithCards.UserUUID)
+
+		err = row.Scan(&moduleWithCards.UUID)
+		if err == nil {
+			err = insertCards(ctx, tx, moduleWithCards.UUID, moduleWithCards.Cards)
+		}
+
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return rollbackErr
+			}
 
-	err = row.Scan(&moduleWithCards.UUID)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddCardsToModule inserts new cards and overwrites meanings of existing ones in a single transaction.
+// Updated cards are released from leeches just like edited ones.
+func (repo *ModulesRepository) AddCardsToModule(
+	ctx context.Context,
+	moduleUUID string,
+	newCards []*entity.Card,
+	updatedCards []*entity.Card,
+) error {
+	tx, err := repo.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = insertCards(ctx, tx, moduleUUID, newCards)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -104,16 +231,55 @@ func (repo *ModulesRepository) CreateNewModuleWithCards(
 		return err
 	}
 
+	for _, card := range updatedCards {
+		_, err = tx.ExecContext(ctx, `
+			WITH updated_card AS (
+				UPDATE cards
+				SET meaning=$3
+				WHERE uuid=$1 AND module_uuid=$2
+				RETURNING uuid
+			)
+			UPDATE review_states
+			SET is_leech=false, is_suspended=false, updated_at=CURRENT_TIMESTAMP
+			WHERE card_uuid IN (SELECT uuid FROM updated_card);
+		`, card.UUID, moduleUUID, card.Meaning)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return rollbackErr
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertCards inserts cards by batches, so a single statement doesn't exceed
+// the limit of bind parameters in large modules.
+func insertCards(ctx context.Context, tx *sql.Tx, moduleUUID string, cards []*entity.Card) error {
+	for batch := range slices.Chunk(cards, insertCardsBatchSize) {
+		err := insertCardsBatch(ctx, tx, moduleUUID, batch)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func insertCardsBatch(ctx context.Context, tx *sql.Tx, moduleUUID string, cards []*entity.Card) error {
 	insertColsAmount := 3
-	insertParts := make([]string, 0, len(moduleWithCards.Cards))
-	args := make([]any, 0, len(moduleWithCards.Cards)*insertColsAmount)
+	insertParts := make([]string, 0, len(cards))
+	args := make([]any, 0, len(cards)*insertColsAmount)
 
-	for i, card := range moduleWithCards.Cards {
+	for i, card := range cards {
 		base := i * insertColsAmount
 		part := fmt.Sprintf("($%d, $%d, $%d)", base+1, base+2, base+3)
 
 		insertParts = append(insertParts, part)
-		args = append(args, moduleWithCards.UUID, card.Term, card.Meaning)
+		args = append(args, moduleUUID, card.Term, card.Meaning)
 	}
 
 	//nolint:gosec
@@ -122,17 +288,9 @@ func (repo *ModulesRepository) CreateNewModuleWithCards(
 		VALUES %s;
 	`, strings.Join(insertParts, ","))
 
-	_, err = tx.ExecContext(ctx, query, args...)
-	if err != nil {
-		rollbackErr := tx.Rollback()
-		if rollbackErr != nil {
-			return rollbackErr
-		}
+	_, err := tx.ExecContext(ctx, query, args...)
 
-		return err
-	}
-
-	return tx.Commit()
+	return err
 }
 
 func (repo *ModulesRepository) UpdateModule(
@@ -222,3 +380,220 @@ func (repo *ModulesRepository) ModuleExists(
 
 	return true, nil
 }
+
+// GetArchivedModule returns the module with its settings, cards and user's review states.
+// Cards are ordered by creation time.
+func (repo *ModulesRepository) GetArchivedModule(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) (*entity.ArchivedModule, error) {
+	module := entity.ArchivedModule{Cards: make([]*entity.ArchivedCard, 0)}
+
+	row := repo.conn.QueryRowContext(ctx, `
+		SELECT uuid, name, created_at, COALESCE(scheduler, '')
+		FROM modules
+		WHERE uuid=$1 AND user_uuid=$2;
+	`, moduleUUID, userUUID)
+
+	err := row.Scan(&module.UUID, &module.Name, &module.CreatedAt, &module.Settings.Scheduler)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.ModuleNotFoundError{UUID: moduleUUID}
+		}
+
+		return nil, err
+	}
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			c.uuid,
+			c.term,
+			c.meaning,
+			c.created_at,
+			rs.card_uuid IS NOT NULL,
+			COALESCE(rs.ease_factor, 0),
+			COALESCE(rs.stability, 0),
+			COALESCE(rs.difficulty, 0),
+			COALESCE(rs.interval_days, 0),
+			COALESCE(rs.repetitions, 0),
+			COALESCE(rs.lapses, 0),
+			COALESCE(rs.due_at, c.created_at),
+			COALESCE(rs.last_reviewed_at, c.created_at),
+			COALESCE(rs.is_leech, false),
+			COALESCE(rs.is_suspended, false)
+		FROM cards c
+		LEFT JOIN review_states rs ON rs.card_uuid = c.uuid AND rs.user_uuid = $2
+		WHERE c.module_uuid=$1
+		ORDER BY c.created_at, c.uuid;
+	`, moduleUUID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			card     entity.ArchivedCard
+			state    entity.ArchivedReviewState
+			reviewed bool
+		)
+
+		err = rows.Scan(
+			&card.UUID,
+			&card.Term,
+			&card.Meaning,
+			&card.CreatedAt,
+			&reviewed,
+			&state.EaseFactor,
+			&state.Stability,
+			&state.Difficulty,
+			&state.IntervalDays,
+			&state.Repetitions,
+			&state.Lapses,
+			&state.DueAt,
+			&state.LastReviewedAt,
+			&state.IsLeech,
+			&state.IsSuspended,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if reviewed {
+			card.ReviewState = &state
+		}
+
+		module.Cards = append(module.Cards, &card)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &module, nil
+}
+
+// CreateModuleFromArchive restores the archived module as a new user's module in a single transaction.
+// The module and its cards get new UUIDs, while creation times, settings and review states are kept.
+func (repo *ModulesRepository) CreateModuleFromArchive(
+	ctx context.Context,
+	userUUID string,
+	archivedModule *entity.ArchivedModule,
+) (*entity.Module, error) {
+	tx, err := repo.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := insertArchivedModule(ctx, tx, userUUID, archivedModule)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, rollbackErr
+		}
+
+		return nil, err
+	}
+
+	return module, tx.Commit()
+}
+
+func insertArchivedModule(
+	ctx context.Context,
+	tx *sql.Tx,
+	userUUID string,
+	archivedModule *entity.ArchivedModule,
+) (*entity.Module, error) {
+	var module entity.Module
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO modules (name, user_uuid, scheduler, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), COALESCE($4, CURRENT_TIMESTAMP))
+		RETURNING uuid, name, user_uuid;
+	`, archivedModule.Name, userUUID, archivedModule.Settings.Scheduler, nullTime(archivedModule.CreatedAt))
+
+	err := row.Scan(&module.UUID, &module.Name, &module.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	cardStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO cards (module_uuid, term, meaning, created_at)
+		VALUES ($1, $2, $3, COALESCE($4, CURRENT_TIMESTAMP))
+		RETURNING uuid;
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cardStmt.Close()
+
+	stateStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO review_states (
+			user_uuid,
+			card_uuid,
+			module_uuid,
+			ease_factor,
+			stability,
+			difficulty,
+			interval_days,
+			repetitions,
+			lapses,
+			due_at,
+			last_reviewed_at,
+			is_leech,
+			is_suspended
+		)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stateStmt.Close()
+
+	for _, card := range archivedModule.Cards {
+		var cardUUID string
+
+		err = cardStmt.QueryRowContext(ctx, module.UUID, card.Term, card.Meaning, nullTime(card.CreatedAt)).
+			Scan(&cardUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		state := card.ReviewState
+		if state == nil {
+			continue
+		}
+
+		_, err = stateStmt.ExecContext(
+			ctx,
+			userUUID,
+			cardUUID,
+			module.UUID,
+			state.EaseFactor,
+			state.Stability,
+			state.Difficulty,
+			state.IntervalDays,
+			state.Repetitions,
+			state.Lapses,
+			state.DueAt,
+			state.LastReviewedAt,
+			state.IsLeech,
+			state.IsSuspended,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &module, nil
+}
+
+// nullTime makes the zero time NULL, so the database default is used instead.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+const deadQueueJobsListLimit = 100
+
+type QueueJobsRepository struct {
+	conn *sql.DB
+}
+
+func NewQueueJobsRepository(conn *sql.DB) *QueueJobsRepository {
+	return &QueueJobsRepository{conn: conn}
+}
+
+type queueJobRow struct {
+	job       entity.QueueJob
+	lastError sql.NullString
+}
+
+func (row *queueJobRow) dest() []any {
+	return []any{
+		&row.job.ID,
+		&row.job.Queue,
+		&row.job.Status,
+		&row.job.Attempts,
+		&row.lastError,
+		&row.job.RunAt,
+		&row.job.CreatedAt,
+		&row.job.UpdatedAt,
+	}
+}
+
+func (row *queueJobRow) toEntity() *entity.QueueJob {
+	job := row.job
+	job.LastError = row.lastError.String
+
+	return &job
+}
+
+// GetDeadJobs returns the latest dead jobs, empty queue means all queues.
+func (repo *QueueJobsRepository) GetDeadJobs(ctx context.Context, queue string) ([]*entity.QueueJob, error) {
+	jobs := make([]*entity.QueueJob, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			id,
+			queue,
+			status,
+			attempts,
+			last_error,
+			run_at,
+			created_at,
+			updated_at
+		FROM queue_jobs
+		WHERE status=$1 AND ($2::text = '' OR queue=$2)
+		ORDER BY updated_at DESC
+		LIMIT $3;
+	`, entity.QueueJobStatusDead, queue, deadQueueJobsListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var row queueJobRow
+
+		err = rows.Scan(row.dest()...)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, row.toEntity())
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return jobs, nil
+		}
+
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// RequeueDeadJob makes the dead job visible to workers again with a fresh set of attempts.
+// The import or export job failed by the dead work is reopened in the same transaction,
+// otherwise the redelivered work would skip it as a finished one.
+func (repo *QueueJobsRepository) RequeueDeadJob(ctx context.Context, id int64) (*entity.QueueJob, error) {
+	var (
+		row     queueJobRow
+		payload []byte
+	)
+
+	tx, err := repo.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE queue_jobs
+		SET
+			status=$2,
+			attempts=0,
+			run_at=CURRENT_TIMESTAMP,
+			updated_at=CURRENT_TIMESTAMP
+		WHERE id=$1 AND status=$3
+		RETURNING
+			id,
+			queue,
+			status,
+			attempts,
+			last_error,
+			run_at,
+			created_at,
+			updated_at,
+			payload;
+	`, id, entity.QueueJobStatusQueued, entity.QueueJobStatusDead).Scan(append(row.dest(), &payload)...)
+	if err == nil {
+		err = reopenFailedJob(ctx, tx, payload)
+	}
+
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, rollbackErr
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.QueueJobNotFoundError{ID: id}
+		}
+
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// reopenFailedJob resets the import or export job referenced by the work payload to created.
+// Payloads of all works keep their job under the "job" key.
+func reopenFailedJob(ctx context.Context, tx *sql.Tx, payload []byte) error {
+	var work struct {
+		Job *struct {
+			UUID string `json:"uuid"`
+		} `json:"job"`
+	}
+
+	if json.Unmarshal(payload, &work) != nil || work.Job == nil || work.Job.UUID == "" {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status=$2, error_message=NULL, updated_at=CURRENT_TIMESTAMP
+		WHERE uuid=$1 AND status=$3;
+	`, work.Job.UUID, entity.ImportStatusCreated, entity.ImportStatusFailed)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET status=$2, error_message=NULL, updated_at=CURRENT_TIMESTAMP
+		WHERE uuid=$1 AND status=$3;
+	`, work.Job.UUID, entity.ExportStatusCreated, entity.ExportStatusFailed)
+
+	return err
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type ReviewRepository struct {
+	conn *sql.DB
+}
+
+func NewReviewRepository(conn *sql.DB) *ReviewRepository {
+	return &ReviewRepository{conn: conn}
+}
+
+func (repo *ReviewRepository) GetReviewStates(
+	ctx context.Context,
+	userUUID string,
+	cardUUIDs []string,
+) (map[string]*entity.ReviewState, error) {
+	states := make(map[string]*entity.ReviewState, len(cardUUIDs))
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			card_uuid,
+			module_uuid,
+			ease_factor,
+			stability,
+			difficulty,
+			interval_days,
+			repetitions,
+			lapses,
+			due_at,
+			last_reviewed_at,
+			is_leech,
+			is_suspended
+		FROM review_states
+		WHERE user_uuid=$1 AND card_uuid = ANY($2::uuid[]);
+	`, userUUID, cardUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var state entity.ReviewState
+
+		err = rows.Scan(
+			&state.CardUUID,
+			&state.ModuleUUID,
+			&state.EaseFactor,
+			&state.Stability,
+			&state.Difficulty,
+			&state.IntervalDays,
+			&state.Repetitions,
+			&state.Lapses,
+			&state.DueAt,
+			&state.LastReviewedAt,
+			&state.IsLeech,
+			&state.IsSuspended,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		states[state.CardUUID] = &state
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return states, nil
+		}
+
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func saveReviewStates(ctx context.Context, tx *sql.Tx, userUUID string, states []*entity.ReviewState) error {
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO review_states (
+			user_uuid,
+			card_uuid,
+			module_uuid,
+			ease_factor,
+			stability,
+			difficulty,
+			interval_days,
+			repetitions,
+			lapses,
+			due_at,
+			last_reviewed_at,
+			is_leech
+		)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (user_uuid, card_uuid) DO UPDATE
+		SET
+			ease_factor=EXCLUDED.ease_factor,
+			stability=EXCLUDED.stability,
+			difficulty=EXCLUDED.difficulty,
+			interval_days=EXCLUDED.interval_days,
+			repetitions=EXCLUDED.repetitions,
+			lapses=EXCLUDED.lapses,
+			due_at=EXCLUDED.due_at,
+			last_reviewed_at=EXCLUDED.last_reviewed_at,
+			is_leech=EXCLUDED.is_leech,
+			updated_at=CURRENT_TIMESTAMP;
+	`)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	for _, state := range states {
+		_, err = stmt.ExecContext(
+			ctx,
+			userUUID,
+			state.CardUUID,
+			state.ModuleUUID,
+			state.EaseFactor,
+			state.Stability,
+			state.Difficulty,
+			state.IntervalDays,
+			state.Repetitions,
+			state.Lapses,
+			state.DueAt,
+			state.LastReviewedAt,
+			state.IsLeech,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetDueCards returns cards which are due at the given time, most overdue first,
+// followed by cards which have never been reviewed. Suspended cards are skipped.
+// Empty moduleUUID means all user's modules.
+func (repo *ReviewRepository) GetDueCards(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	dueAt time.Time,
+	limit int,
+) ([]*entity.DueCard, error) {
+	cards := make([]*entity.DueCard, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			c.uuid,
+			c.term,
+			c.meaning,
+			c.module_uuid,
+			rs.ease_factor,
+			rs.stability,
+			rs.difficulty,
+			rs.interval_days,
+			rs.repetitions,
+			rs.lapses,
+			rs.due_at,
+			rs.last_reviewed_at,
+			rs.is_leech
+		FROM cards c
+		JOIN modules m ON m.uuid = c.module_uuid
+		LEFT JOIN review_states rs ON rs.card_uuid = c.uuid AND rs.user_uuid = m.user_uuid
+		WHERE
+			m.user_uuid=$1
+			AND ($2::text = '' OR c.module_uuid = NULLIF($2::text, '')::uuid)
+			AND (rs.due_at IS NULL OR rs.due_at <= $3)
+			AND NOT COALESCE(rs.is_suspended, false)
+		ORDER BY rs.due_at ASC NULLS LAST, c.created_at
+		LIMIT $4;
+	`, userUUID, moduleUUID, dueAt, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			card           entity.DueCard
+			easeFactor     sql.NullFloat64
+			stability      sql.NullFloat64
+			difficulty     sql.NullFloat64
+			intervalDays   sql.NullInt64
+			repetitions    sql.NullInt64
+			lapses         sql.NullInt64
+			cardDueAt      sql.NullTime
+			lastReviewedAt sql.NullTime
+			isLeech        sql.NullBool
+		)
+
+		err = rows.Scan(
+			&card.UUID,
+			&card.Term,
+			&card.Meaning,
+			&card.ModuleUUID,
+			&easeFactor,
+			&stability,
+			&difficulty,
+			&intervalDays,
+			&repetitions,
+			&lapses,
+			&cardDueAt,
+			&lastReviewedAt,
+			&isLeech,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if cardDueAt.Valid {
+			card.ReviewState = &entity.ReviewState{
+				CardUUID:       card.UUID,
+				ModuleUUID:     card.ModuleUUID,
+				EaseFactor:     easeFactor.Float64,
+				Stability:      stability.Float64,
+				Difficulty:     difficulty.Float64,
+				IntervalDays:   int(intervalDays.Int64),
+				Repetitions:    int(repetitions.Int64),
+				Lapses:         int(lapses.Int64),
+				DueAt:          cardDueAt.Time,
+				LastReviewedAt: lastReviewedAt.Time,
+				IsLeech:        isLeech.Bool,
+			}
+		}
+
+		cards = append(cards, &card)
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cards, nil
+		}
+
+		return nil, err
+	}
+
+	return cards, nil
+}
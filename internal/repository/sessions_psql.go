@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type SessionsRepository struct {
+	conn *sql.DB
+}
+
+func NewSessionsRepository(conn *sql.DB) *SessionsRepository {
+	return &SessionsRepository{conn: conn}
+}
+
+type sessionRow struct {
+	session       entity.StudySession
+	finishedAt    sql.NullTime
+	cardsSeen     sql.NullInt64
+	answersCount  sql.NullInt64
+	correctCount  sql.NullInt64
+	promotedCount sql.NullInt64
+	demotedCount  sql.NullInt64
+}
+
+func (row *sessionRow) dest() []any {
+	return []any{
+		&row.session.UUID,
+		&row.session.ModuleUUID,
+		&row.session.StartedAt,
+		&row.finishedAt,
+		&row.cardsSeen,
+		&row.answersCount,
+		&row.correctCount,
+		&row.promotedCount,
+		&row.demotedCount,
+	}
+}
+
+func (row *sessionRow) toEntity() *entity.StudySession {
+	session := row.session
+
+	if row.finishedAt.Valid {
+		session.FinishedAt = &row.finishedAt.Time
+		session.Summary = &entity.SessionSummary{
+			CardsSeen:     int(row.cardsSeen.Int64),
+			AnswersCount:  int(row.answersCount.Int64),
+			CorrectCount:  int(row.correctCount.Int64),
+			PromotedCount: int(row.promotedCount.Int64),
+			DemotedCount:  int(row.demotedCount.Int64),
+		}
+	}
+
+	return &session
+}
+
+func (repo *SessionsRepository) CreateSession(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) (*entity.StudySession, error) {
+	session := &entity.StudySession{ModuleUUID: moduleUUID}
+
+	row := repo.conn.QueryRowContext(ctx, `
+		INSERT INTO study_sessions (user_uuid, module_uuid)
+		VALUES ($1, $2)
+		RETURNING uuid, started_at;
+	`, userUUID, moduleUUID)
+
+	err := row.Scan(&session.UUID, &session.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// FinishSession closes the session and saves its summary built from linked answers.
+func (repo *SessionsRepository) FinishSession(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	sessionUUID string,
+) (*entity.StudySession, error) {
+	tx, err := repo.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var finishedAt sql.NullTime
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT finished_at
+		FROM study_sessions
+		WHERE uuid=$1 AND user_uuid=$2 AND module_uuid=$3
+		FOR UPDATE;
+	`, sessionUUID, userUUID, moduleUUID)
+
+	err = row.Scan(&finishedAt)
+	if err == nil && finishedAt.Valid {
+		err = entity.ErrSessionFinished
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = &entity.SessionNotFoundError{UUID: sessionUUID}
+		}
+
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, rollbackErr
+		}
+
+		return nil, err
+	}
+
+	var session sessionRow
+
+	row = tx.QueryRowContext(ctx, `
+		WITH session_cards AS (
+			SELECT
+				card_uuid,
+				COUNT(*) AS answers_count,
+				COUNT(*) FILTER (WHERE is_correct) AS correct_count,
+				(ARRAY_AGG(interval_before ORDER BY answered_at))[1] AS interval_before,
+				(ARRAY_AGG(interval_after ORDER BY answered_at DESC))[1] AS interval_after
+			FROM answers
+			WHERE session_uuid=$1
+			GROUP BY card_uuid
+		), summary AS (
+			SELECT
+				COUNT(*) AS cards_seen,
+				COALESCE(SUM(answers_count), 0) AS answers_count,
+				COALESCE(SUM(correct_count), 0) AS correct_count,
+				COUNT(*) FILTER (WHERE interval_after > interval_before) AS promoted_count,
+				COUNT(*) FILTER (WHERE interval_after < interval_before) AS demoted_count
+			FROM session_cards
+		)
+		UPDATE study_sessions
+		SET
+			finished_at=CURRENT_TIMESTAMP,
+			cards_seen=summary.cards_seen,
+			answers_count=summary.answers_count,
+			correct_count=summary.correct_count,
+			promoted_count=summary.promoted_count,
+			demoted_count=summary.demoted_count
+		FROM summary
+		WHERE uuid=$1
+		RETURNING
+			uuid,
+			module_uuid,
+			started_at,
+			finished_at,
+			cards_seen,
+			answers_count,
+			correct_count,
+			promoted_count,
+			demoted_count;
+	`, sessionUUID)
+
+	err = row.Scan(session.dest()...)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, rollbackErr
+		}
+
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return session.toEntity(), nil
+}
+
+func (repo *SessionsRepository) GetModuleSessions(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) ([]*entity.StudySession, error) {
+	sessions := make([]*entity.StudySession, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			uuid,
+			module_uuid,
+			started_at,
+			finished_at,
+			cards_seen,
+			answers_count,
+			correct_count,
+			promoted_count,
+			demoted_count
+		FROM study_sessions
+		WHERE user_uuid=$1 AND module_uuid=$2
+		ORDER BY started_at DESC;
+	`, userUUID, moduleUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var session sessionRow
+
+		err = rows.Scan(session.dest()...)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session.toEntity())
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sessions, nil
+		}
+
+		return nil, err
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type SettingsRepository struct {
+	conn *sql.DB
+}
+
+func NewSettingsRepository(conn *sql.DB) *SettingsRepository {
+	return &SettingsRepository{conn: conn}
+}
+
+// GetUserSettings returns default settings for users who have never changed them.
+func (repo *SettingsRepository) GetUserSettings(
+	ctx context.Context,
+	userUUID string,
+) (*entity.UserSettings, error) {
+	settings := entity.UserSettings{
+		Scheduler:        entity.DefaultScheduler,
+		DesiredRetention: entity.DefaultDesiredRetention,
+		Timezone:         entity.DefaultTimezone,
+		DailyGoalType:    entity.DefaultDailyGoalType,
+		DailyGoalValue:   entity.DefaultDailyGoalValue,
+		LeechThreshold:   entity.DefaultLeechThreshold,
+	}
+
+	row := repo.conn.QueryRowContext(ctx, `
+		SELECT scheduler, desired_retention, timezone, daily_goal_type, daily_goal_value, leech_threshold
+		FROM user_settings
+		WHERE user_uuid=$1;
+	`, userUUID)
+
+	err := row.Scan(
+		&settings.Scheduler,
+		&settings.DesiredRetention,
+		&settings.Timezone,
+		&settings.DailyGoalType,
+		&settings.DailyGoalValue,
+		&settings.LeechThreshold,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (repo *SettingsRepository) SaveUserSettings(
+	ctx context.Context,
+	userUUID string,
+	settings *entity.UserSettings,
+) (*entity.UserSettings, error) {
+	var storedSettings entity.UserSettings
+
+	row := repo.conn.QueryRowContext(ctx, `
+		INSERT INTO user_settings (
+			user_uuid,
+			scheduler,
+			desired_retention,
+			timezone,
+			daily_goal_type,
+			daily_goal_value,
+			leech_threshold
+		)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_uuid) DO UPDATE
+		SET
+			scheduler=EXCLUDED.scheduler,
+			desired_retention=EXCLUDED.desired_retention,
+			timezone=EXCLUDED.timezone,
+			daily_goal_type=EXCLUDED.daily_goal_type,
+			daily_goal_value=EXCLUDED.daily_goal_value,
+			leech_threshold=EXCLUDED.leech_threshold,
+			updated_at=CURRENT_TIMESTAMP
+		RETURNING scheduler, desired_retention, timezone, daily_goal_type, daily_goal_value, leech_threshold;
+	`,
+		userUUID,
+		settings.Scheduler,
+		settings.DesiredRetention,
+		settings.Timezone,
+		settings.DailyGoalType,
+		settings.DailyGoalValue,
+		settings.LeechThreshold,
+	)
+
+	err := row.Scan(
+		&storedSettings.Scheduler,
+		&storedSettings.DesiredRetention,
+		&storedSettings.Timezone,
+		&storedSettings.DailyGoalType,
+		&storedSettings.DailyGoalValue,
+		&storedSettings.LeechThreshold,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storedSettings, nil
+}
+
+func (repo *SettingsRepository) GetModuleSettings(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) (*entity.ModuleSettings, error) {
+	var settings entity.ModuleSettings
+
+	row := repo.conn.QueryRowContext(ctx, `
+		SELECT COALESCE(scheduler, '')
+		FROM modules
+		WHERE uuid=$1 AND user_uuid=$2;
+	`, moduleUUID, userUUID)
+
+	err := row.Scan(&settings.Scheduler)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.ModuleNotFoundError{UUID: moduleUUID}
+		}
+
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (repo *SettingsRepository) SaveModuleSettings(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	settings *entity.ModuleSettings,
+) (*entity.ModuleSettings, error) {
+	var storedSettings entity.ModuleSettings
+
+	row := repo.conn.QueryRowContext(ctx, `
+		UPDATE modules
+		SET scheduler=NULLIF($1, '')
+		WHERE uuid=$2 AND user_uuid=$3
+		RETURNING COALESCE(scheduler, '');
+	`, settings.Scheduler, moduleUUID, userUUID)
+
+	err := row.Scan(&storedSettings.Scheduler)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &entity.ModuleNotFoundError{UUID: moduleUUID}
+		}
+
+		return nil, err
+	}
+
+	return &storedSettings, nil
+}
+
+// GetModuleSchedulers returns schedulers of modules which override the user's one.
+func (repo *SettingsRepository) GetModuleSchedulers(
+	ctx context.Context,
+	userUUID string,
+	moduleUUIDs []string,
+) (map[string]string, error) {
+	schedulers := make(map[string]string, len(moduleUUIDs))
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT uuid, scheduler
+		FROM modules
+		WHERE user_uuid=$1 AND uuid = ANY($2::uuid[]) AND scheduler IS NOT NULL;
+	`, userUUID, moduleUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var moduleUUID, scheduler string
+
+		err = rows.Scan(&moduleUUID, &scheduler)
+		if err != nil {
+			return nil, err
+		}
+
+		schedulers[moduleUUID] = scheduler
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return schedulers, nil
+		}
+
+		return nil, err
+	}
+
+	return schedulers, nil
+}
@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type StatsRepository struct {
+	conn *sql.DB
+}
+
+func NewStatsRepository(conn *sql.DB) *StatsRepository {
+	return &StatsRepository{conn: conn}
+}
+
+// SaveAnswers stores answers together with review states of the answered cards in a single transaction
+// and returns client IDs of the answers which were already stored.
+// Such answers are skipped, so concurrently uploaded batches don't fail on duplicated client IDs.
+func (repo *StatsRepository) SaveAnswers(
+	ctx context.Context,
+	userUUID string,
+	answers []*entity.Answer,
+	states []*entity.ReviewState,
+) ([]string, error) {
+	tx, err := repo.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates, err := insertAnswers(ctx, tx, userUUID, answers)
+	if err == nil {
+		err = saveReviewStates(ctx, tx, userUUID, states)
+	}
+
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, rollbackErr
+		}
+
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+func insertAnswers(ctx context.Context, tx *sql.Tx, userUUID string, answers []*entity.Answer) ([]string, error) {
+	duplicates := make([]string, 0)
+
+	stmt, err := tx.PrepareContext(ctx, `
+		WITH source AS (
+			SELECT
+				m.user_uuid,
+				c.uuid AS card_uuid,
+				c.module_uuid,
+				s.uuid AS session_uuid
+			FROM cards c
+			JOIN modules m ON m.uuid = c.module_uuid
+			LEFT JOIN study_sessions s ON
+				s.uuid = NULLIF($4::text, '')::uuid
+				AND s.module_uuid = c.module_uuid
+				AND s.user_uuid = m.user_uuid
+				AND s.finished_at IS NULL
+			WHERE c.uuid=$2 AND c.module_uuid=$3 AND m.user_uuid=$1
+		), inserted AS (
+			INSERT INTO answers (
+				user_uuid,
+				card_uuid,
+				module_uuid,
+				session_uuid,
+				is_correct,
+				grade,
+				response_time_ms,
+				interval_before,
+				interval_after,
+				answered_at,
+				client_id
+			)
+			SELECT
+				user_uuid,
+				card_uuid,
+				module_uuid,
+				session_uuid,
+				$5::boolean,
+				$6::smallint,
+				$7::integer,
+				$8::integer,
+				$9::integer,
+				$10::timestamptz,
+				NULLIF($11::text, '')
+			FROM source
+			ON CONFLICT (user_uuid, client_id) WHERE client_id IS NOT NULL DO NOTHING
+			RETURNING uuid
+		)
+		SELECT inserted.uuid, source.session_uuid
+		FROM source
+		LEFT JOIN inserted ON true;
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	for _, answer := range answers {
+		var answerUUID, sessionUUID sql.NullString
+
+		row := stmt.QueryRowContext(
+			ctx,
+			userUUID,
+			answer.CardUUID,
+			answer.ModuleUUID,
+			answer.SessionUUID,
+			answer.IsCorrect,
+			answer.Grade,
+			answer.ResponseTimeMs,
+			answer.IntervalBefore,
+			answer.IntervalAfter,
+			answer.AnsweredAt,
+			answer.ClientID,
+		)
+
+		err = row.Scan(&answerUUID, &sessionUUID)
+		if err == nil && answer.SessionUUID != "" && !sessionUUID.Valid {
+			err = &entity.SessionNotFoundError{UUID: answer.SessionUUID}
+		}
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = &entity.CardNotFoundError{UUID: answer.CardUUID}
+			}
+
+			return nil, err
+		}
+
+		if !answerUUID.Valid {
+			duplicates = append(duplicates, answer.ClientID)
+
+			continue
+		}
+
+		answer.UUID = answerUUID.String
+	}
+
+	return duplicates, nil
+}
+
+// GetAnswersByClientIDs returns user's answers which were uploaded with the given client IDs.
+func (repo *StatsRepository) GetAnswersByClientIDs(
+	ctx context.Context,
+	userUUID string,
+	clientIDs []string,
+) (map[string]*entity.Answer, error) {
+	answers := make(map[string]*entity.Answer, len(clientIDs))
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			uuid,
+			client_id,
+			card_uuid,
+			module_uuid,
+			is_correct,
+			grade,
+			response_time_ms,
+			answered_at
+		FROM answers
+		WHERE user_uuid=$1 AND client_id = ANY($2::text[]);
+	`, userUUID, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		answer := entity.Answer{UserUUID: userUUID}
+
+		err = rows.Scan(
+			&answer.UUID,
+			&answer.ClientID,
+			&answer.CardUUID,
+			&answer.ModuleUUID,
+			&answer.IsCorrect,
+			&answer.Grade,
+			&answer.ResponseTimeMs,
+			&answer.AnsweredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		answers[answer.ClientID] = &answer
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return answers, nil
+		}
+
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+// GetCardsAnswers returns all user's answers to the given cards in chronological order.
+func (repo *StatsRepository) GetCardsAnswers(
+	ctx context.Context,
+	userUUID string,
+	cardUUIDs []string,
+) ([]*entity.Answer, error) {
+	answers := make([]*entity.Answer, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			uuid,
+			card_uuid,
+			module_uuid,
+			is_correct,
+			grade,
+			answered_at
+		FROM answers
+		WHERE user_uuid=$1 AND card_uuid = ANY($2::uuid[])
+		ORDER BY answered_at;
+	`, userUUID, cardUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		answer := entity.Answer{UserUUID: userUUID}
+
+		err = rows.Scan(
+			&answer.UUID,
+			&answer.CardUUID,
+			&answer.ModuleUUID,
+			&answer.IsCorrect,
+			&answer.Grade,
+			&answer.AnsweredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		answers = append(answers, &answer)
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return answers, nil
+		}
+
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+func (repo *StatsRepository) GetModuleStats(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	from time.Time,
+	to time.Time,
+) ([]*entity.CardStats, error) {
+	stats := make([]*entity.CardStats, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		WITH period_answers AS (
+			SELECT
+				card_uuid,
+				is_correct,
+				answered_at,
+				MAX(answered_at) FILTER (WHERE NOT is_correct) OVER (PARTITION BY card_uuid) AS last_failed_at
+			FROM answers
+			WHERE module_uuid=$1 AND user_uuid=$2 AND answered_at >= $3 AND answered_at < $4
+		)
+		SELECT
+			c.uuid,
+			c.term,
+			c.meaning,
+			COUNT(pa.card_uuid) FILTER (WHERE pa.is_correct),
+			COUNT(pa.card_uuid) FILTER (WHERE NOT pa.is_correct),
+			MAX(pa.answered_at),
+			COUNT(pa.card_uuid) FILTER (
+				WHERE pa.is_correct AND (pa.last_failed_at IS NULL OR pa.answered_at > pa.last_failed_at)
+			)
+		FROM cards c
+		LEFT JOIN period_answers pa ON pa.card_uuid = c.uuid
+		WHERE c.module_uuid=$1
+		GROUP BY c.uuid, c.term, c.meaning, c.created_at
+		ORDER BY c.created_at;
+	`, moduleUUID, userUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cardStats      entity.CardStats
+			lastReviewedAt sql.NullTime
+		)
+
+		err = rows.Scan(
+			&cardStats.CardUUID,
+			&cardStats.Term,
+			&cardStats.Meaning,
+			&cardStats.CorrectCount,
+			&cardStats.IncorrectCount,
+			&lastReviewedAt,
+			&cardStats.Streak,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if lastReviewedAt.Valid {
+			cardStats.LastReviewedAt = &lastReviewedAt.Time
+		}
+
+		stats = append(stats, &cardStats)
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stats, nil
+		}
+
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetDailyActivity returns user's days with answers in the period, day boundaries are taken in the given time zone.
+func (repo *StatsRepository) GetDailyActivity(
+	ctx context.Context,
+	userUUID string,
+	timezone string,
+	from time.Time,
+	to time.Time,
+) ([]*entity.DailyActivity, error) {
+	activity := make([]*entity.DailyActivity, 0)
+
+	rows, err := repo.conn.QueryContext(ctx, `
+		SELECT
+			TO_CHAR((answered_at AT TIME ZONE $2)::date, 'YYYY-MM-DD') AS day,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE is_correct),
+			COUNT(*) FILTER (WHERE is_correct)::float8 / COUNT(*),
+			COALESCE(SUM(response_time_ms), 0)
+		FROM answers
+		WHERE user_uuid=$1 AND answered_at >= $3 AND answered_at < $4
+		GROUP BY day
+		ORDER BY day;
+	`, userUUID, timezone, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var day entity.DailyActivity
+
+		err = rows.Scan(&day.Date, &day.AnswersCount, &day.CorrectCount, &day.Accuracy, &day.TimeSpentMs)
+		if err != nil {
+			return nil, err
+		}
+
+		activity = append(activity, &day)
+	}
+
+	if err = rows.Err(); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return activity, nil
+		}
+
+		return nil, err
+	}
+
+	return activity, nil
+}
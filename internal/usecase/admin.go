@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type AdminUseCase struct {
+	queueJobsRepo QueueJobsRepository
+}
+
+func NewAdminUseCase(queueJobsRepo QueueJobsRepository) *AdminUseCase {
+	return &AdminUseCase{
+		queueJobsRepo: queueJobsRepo,
+	}
+}
+
+func (uc *AdminUseCase) GetDeadJobs(ctx context.Context, queue string) ([]*entity.QueueJob, error) {
+	return uc.queueJobsRepo.GetDeadJobs(ctx, queue)
+}
+
+func (uc *AdminUseCase) RequeueDeadJob(ctx context.Context, id int64) (*entity.QueueJob, error) {
+	return uc.queueJobsRepo.RequeueDeadJob(ctx, id)
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+// moduleArchiveMigrations upgrade archives of older versions one version at a time,
+// the migration with index i converts an archive of version i+1 to version i+2.
+// Bumping entity.ModuleArchiveVersion requires appending a migration from the previous version,
+// so archives of every released version stay importable.
+var moduleArchiveMigrations = []func(archive map[string]any) error{}
+
+// decodeModuleArchive decodes an archive of any supported version and validates it.
+func decodeModuleArchive(data []byte) (*entity.ModuleArchive, error) {
+	var header struct {
+		Format  string `json:"format"`
+		Version int    `json:"version"`
+	}
+
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, &entity.ModuleArchiveError{Reason: err.Error()}
+	}
+
+	if header.Format != entity.ModuleArchiveFormat {
+		return nil, &entity.ModuleArchiveError{
+			Reason: fmt.Sprintf("format must be \"%s\"", entity.ModuleArchiveFormat),
+		}
+	}
+
+	if header.Version < 1 || header.Version > entity.ModuleArchiveVersion {
+		return nil, &entity.ModuleArchiveError{
+			Reason: fmt.Sprintf(
+				"version %d is not supported, the latest version is %d",
+				header.Version,
+				entity.ModuleArchiveVersion,
+			),
+		}
+	}
+
+	if header.Version < entity.ModuleArchiveVersion {
+		migrated, err := migrateModuleArchive(data, header.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		data = migrated
+	}
+
+	var archive entity.ModuleArchive
+
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, &entity.ModuleArchiveError{Reason: err.Error()}
+	}
+
+	if err := validateArchivedModule(archive.Module); err != nil {
+		return nil, err
+	}
+
+	return &archive, nil
+}
+
+// migrateModuleArchive upgrades the archive to the latest version. Numbers are kept as json.Number,
+// so migrations don't lose precision of the fields they don't touch.
+func migrateModuleArchive(data []byte, version int) ([]byte, error) {
+	var archive map[string]any
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&archive); err != nil {
+		return nil, &entity.ModuleArchiveError{Reason: err.Error()}
+	}
+
+	for ; version < entity.ModuleArchiveVersion; version++ {
+		if err := moduleArchiveMigrations[version-1](archive); err != nil {
+			return nil, &entity.ModuleArchiveError{
+				Reason: fmt.Sprintf("migration from version %d failed: %s", version, err),
+			}
+		}
+	}
+
+	archive["version"] = entity.ModuleArchiveVersion
+
+	return json.Marshal(archive)
+}
+
+func validateArchivedModule(module *entity.ArchivedModule) error {
+	if module == nil {
+		return &entity.ModuleArchiveError{Reason: "module is missing"}
+	}
+
+	if strings.TrimSpace(module.Name) == "" || utf8.RuneCountInString(module.Name) > maxImportModuleNameLength {
+		return &entity.ModuleArchiveError{
+			Reason: fmt.Sprintf("module name must have from 1 to %d characters", maxImportModuleNameLength),
+		}
+	}
+
+	switch module.Settings.Scheduler {
+	case "", entity.SchedulerSM2, entity.SchedulerFSRS:
+	default:
+		return &entity.ModuleArchiveError{
+			Reason: fmt.Sprintf("scheduler \"%s\" is unknown", module.Settings.Scheduler),
+		}
+	}
+
+	for i, card := range module.Cards {
+		if card == nil || strings.TrimSpace(card.Term) == "" || strings.TrimSpace(card.Meaning) == "" {
+			return &entity.ModuleArchiveError{Reason: fmt.Sprintf("card %d: term or meaning is empty", i+1)}
+		}
+
+		if state := card.ReviewState; state != nil && (state.DueAt.IsZero() || state.LastReviewedAt.IsZero()) {
+			return &entity.ModuleArchiveError{
+				Reason: fmt.Sprintf("card %d: review state must have due_at and last_reviewed_at", i+1),
+			}
+		}
+	}
+
+	return nil
+}
@@ -2,10 +2,16 @@ package usecase
 
 import (
 	"context"
+	"slices"
+	"unicode/utf8"
 
 	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/pkg/textnorm"
 )
 
+// charsPerTypo is an answer length which allows one more typo in an almost correct answer.
+const charsPerTypo = 4
+
 type CardsUseCase struct {
 	repo CardsRepository
 }
@@ -31,3 +37,101 @@ func (uc *CardsUseCase) SaveCard(ctx context.Context, card *entity.Card) (*entit
 func (uc *CardsUseCase) DeleteCard(ctx context.Context, moduleUUID string, cardUUID string) error {
 	return uc.repo.DeleteCard(ctx, moduleUUID, cardUUID)
 }
+
+func (uc *CardsUseCase) GetLeeches(ctx context.Context, userUUID string) ([]*entity.LeechCard, error) {
+	return uc.repo.GetLeeches(ctx, userUUID, "")
+}
+
+func (uc *CardsUseCase) GetModuleLeeches(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) ([]*entity.LeechCard, error) {
+	return uc.repo.GetLeeches(ctx, userUUID, moduleUUID)
+}
+
+func (uc *CardsUseCase) SuspendCard(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	cardUUID string,
+) (*entity.ReviewState, error) {
+	return uc.repo.SetCardSuspended(ctx, userUUID, moduleUUID, cardUUID, true)
+}
+
+func (uc *CardsUseCase) ResumeCard(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	cardUUID string,
+) (*entity.ReviewState, error) {
+	return uc.repo.SetCardSuspended(ctx, userUUID, moduleUUID, cardUUID, false)
+}
+
+// CheckAnswer grades the typed answer against the card's meaning or term,
+// articles are ignored only when the language of the checked side is given.
+func (uc *CardsUseCase) CheckAnswer(
+	ctx context.Context,
+	moduleUUID string,
+	cardUUID string,
+	side string,
+	answer string,
+	language string,
+) (*entity.AnswerCheck, error) {
+	card, err := uc.repo.GetCard(ctx, moduleUUID, cardUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := card.Meaning
+	if side == entity.CardSideTerm {
+		expected = card.Term
+	}
+
+	return checkAnswer(expected, answer, language), nil
+}
+
+// checkAnswer compares the answer with each acceptable variant of the expected text,
+// an answer with a few typos is almost correct.
+func checkAnswer(expected string, answer string, language string) *entity.AnswerCheck {
+	check := &entity.AnswerCheck{
+		Verdict:  entity.AnswerVerdictIncorrect,
+		Grade:    entity.DefaultIncorrectAnswerGrade,
+		Expected: expected,
+	}
+
+	normalizedAnswer := textnorm.Normalize(answer, language)
+	if normalizedAnswer == "" {
+		check.Distance = utf8.RuneCountInString(textnorm.Normalize(expected, language))
+
+		return check
+	}
+
+	alternatives := textnorm.Alternatives(expected, language)
+	if whole := textnorm.Normalize(expected, language); !slices.Contains(alternatives, whole) {
+		alternatives = append(alternatives, whole)
+	}
+
+	closest := ""
+	check.Distance = -1
+
+	for _, alternative := range alternatives {
+		distance := textnorm.Levenshtein(normalizedAnswer, alternative)
+
+		if check.Distance == -1 || distance < check.Distance {
+			check.Distance = distance
+			closest = alternative
+		}
+	}
+
+	switch {
+	case check.Distance == 0:
+		check.Verdict = entity.AnswerVerdictCorrect
+		check.Grade = entity.DefaultCorrectAnswerGrade
+	case check.Distance <= utf8.RuneCountInString(closest)/charsPerTypo:
+		check.Verdict = entity.AnswerVerdictAlmostCorrect
+		check.Grade = entity.AlmostCorrectAnswerGrade
+	}
+
+	return check
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/rs/zerolog"
+)
+
+// maxEventReasonLength limits the failure reason in bytes, so that the escaped event
+// stays below the 8000 bytes limit of a Postgres notification payload.
+const maxEventReasonLength = 1000
+
+// JobEvents publishes lifecycle events of users' jobs and streams them to the users.
+// Events are best effort, a client which has missed one can still fetch the job.
+type JobEvents struct {
+	notifier JobEventsNotifier
+	log      *zerolog.Logger
+}
+
+func NewJobEvents(notifier JobEventsNotifier, log *zerolog.Logger) *JobEvents {
+	return &JobEvents{
+		notifier: notifier,
+		log:      log,
+	}
+}
+
+// Subscribe streams the user's job events till the context is done.
+func (e *JobEvents) Subscribe(ctx context.Context, userUUID string) <-chan *entity.JobEvent {
+	notifications, unsubscribe := e.notifier.Subscribe()
+	events := make(chan *entity.JobEvent)
+
+	go func() {
+		defer close(events)
+		defer unsubscribe()
+
+		for {
+			var payload []byte
+
+			select {
+			case <-ctx.Done():
+				return
+			case notification, ok := <-notifications:
+				if !ok {
+					return
+				}
+
+				payload = notification
+			}
+
+			var event entity.JobEvent
+
+			if err := json.Unmarshal(payload, &event); err != nil {
+				e.log.Error().Err(err).Msg("job event decoding failed")
+
+				continue
+			}
+
+			if event.UserUUID != userUUID {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case events <- &event:
+			}
+		}
+	}()
+
+	return events
+}
+
+// publish only logs a failure, since an event must not break the job.
+func (e *JobEvents) publish(ctx context.Context, event *entity.JobEvent) {
+	payload, err := json.Marshal(event)
+	if err == nil {
+		err = e.notifier.Notify(context.WithoutCancel(ctx), payload)
+	}
+
+	if err != nil {
+		e.log.Error().Err(err).Str("job_uuid", event.JobUUID).Msg("job event publishing failed")
+	}
+}
+
+func (e *JobEvents) publishImport(ctx context.Context, eventType string, job *entity.ImportJob, progress int) {
+	event := &entity.JobEvent{
+		Type:     eventType,
+		JobKind:  entity.JobKindImport,
+		JobUUID:  job.UUID,
+		UserUUID: job.UserUUID,
+		Progress: progress,
+	}
+
+	switch eventType {
+	case entity.JobEventCompleted:
+		event.ModuleUUID = job.ModuleUUID
+	case entity.JobEventFailed:
+		event.Reason = eventReason(job.ErrorMessage)
+	}
+
+	e.publish(ctx, event)
+}
+
+func (e *JobEvents) publishExport(ctx context.Context, eventType string, job *entity.ExportJob, progress int) {
+	event := &entity.JobEvent{
+		Type:     eventType,
+		JobKind:  entity.JobKindExport,
+		JobUUID:  job.UUID,
+		UserUUID: job.UserUUID,
+		Progress: progress,
+	}
+
+	if eventType == entity.JobEventFailed {
+		event.Reason = eventReason(job.ErrorMessage)
+	}
+
+	e.publish(ctx, event)
+}
+
+// eventReason cuts a long error message by a rune boundary, the whole message is kept by the job.
+func eventReason(errorMessage string) string {
+	if len(errorMessage) <= maxEventReasonLength {
+		return errorMessage
+	}
+
+	cut := maxEventReasonLength - len("…")
+	for cut > 0 && !utf8.RuneStart(errorMessage[cut]) {
+		cut--
+	}
+
+	return errorMessage[:cut] + "…"
+}
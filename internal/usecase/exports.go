@@ -0,0 +1,511 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/pkg/apkg"
+	"github.com/llravell/simple-cards/pkg/xlsx"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// exportArchiveTTL is how long an export archive can be downloaded after it has been built.
+	exportArchiveTTL = 24 * time.Hour
+	// maxExportArchiveSize limits the archive, which is built in memory and stored in the database as a whole.
+	maxExportArchiveSize = 256 << 20
+
+	// Export progress is reported in steps as modules are written to the archive.
+	exportProgressStep      = 10
+	exportProgressCompleted = 100
+
+	defaultExportFileName = "module"
+)
+
+// exportFileNameReplacer replaces characters which aren't allowed in file names on common file systems.
+var exportFileNameReplacer = strings.NewReplacer(
+	"/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_",
+)
+
+type LibraryExportWork struct {
+	modulesRepo ModulesRepository
+	cardsRepo   CardsRepository
+	reviewRepo  ReviewRepository
+	jobsRepo    ExportJobsRepository
+	jobEvents   *JobEvents
+	log         *zerolog.Logger
+	job         *entity.ExportJob
+}
+
+// Do builds the zip archive with a file per module and stores it till it expires.
+// Modules deleted after the job has been created are skipped, the job fails if none is left
+// or the archive grows too big. Other errors are returned to retry the work later.
+func (w *LibraryExportWork) Do(ctx context.Context) error {
+	w.job.Status = entity.ExportStatusProcessing
+
+	if updateExportJob(ctx, w.jobsRepo, w.log, w.job) == nil {
+		w.jobEvents.publishExport(ctx, entity.JobEventStarted, w.job, 0)
+	}
+
+	modules, err := w.modulesRepo.GetLibraryModules(ctx, w.job.UserUUID, w.job.ModuleUUIDs)
+	if err != nil {
+		w.log.Error().Err(err).Msg("exported modules fetching failed")
+
+		return err
+	}
+
+	archive, err := w.writeArchive(ctx, modules)
+	if errors.Is(err, entity.ErrExportArchiveTooBig) {
+		failExportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, err)
+
+		return nil
+	}
+
+	if err != nil {
+		w.log.Error().Err(err).Msg("export archive building failed")
+
+		return err
+	}
+
+	if w.job.ModulesCount == 0 {
+		failExportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, entity.ErrNoModulesToExport)
+
+		return nil
+	}
+
+	expiresAt := time.Now().Add(exportArchiveTTL)
+
+	w.job.Status = entity.ExportStatusProcessed
+	w.job.ArchiveSize = int64(len(archive))
+	w.job.ExpiresAt = &expiresAt
+
+	err = w.jobsRepo.SaveExportArchive(context.WithoutCancel(ctx), w.job, archive)
+	if err != nil {
+		w.log.Error().Err(err).Msg("export archive storing failed")
+
+		return err
+	}
+
+	w.log.Info().Msgf("%d modules exported to %s", w.job.ModulesCount, w.job.Format)
+	w.jobEvents.publishExport(ctx, entity.JobEventCompleted, w.job, exportProgressCompleted)
+
+	return nil
+}
+
+// Dead fails the export job when the work won't be retried anymore.
+func (w *LibraryExportWork) Dead(ctx context.Context, err error) {
+	failExportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, err)
+}
+
+// IsRetryableExportError tells whether a failed export work may succeed on the next attempt:
+// the network or the database are temporarily unavailable.
+func IsRetryableExportError(err error) bool {
+	return isTemporaryError(err)
+}
+
+// writeArchive writes a file per module, the job gets the numbers of exported modules and cards.
+// Building stops as soon as the archive exceeds the size limit.
+func (w *LibraryExportWork) writeArchive(ctx context.Context, modules []*entity.Module) ([]byte, error) {
+	var archive bytes.Buffer
+
+	zipWriter := zip.NewWriter(&archive)
+	usedFileNames := make(map[string]bool, len(modules))
+	reportedProgress := 0
+
+	w.job.ModulesCount = 0
+	w.job.CardsCount = 0
+
+	for i, module := range modules {
+		content, cardsCount, err := w.moduleFile(ctx, module)
+
+		var notFoundErr *entity.ModuleNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     exportFileName(module.Name, w.job.Format, usedFileNames),
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = file.Write(content); err != nil {
+			return nil, err
+		}
+
+		if archive.Len() > maxExportArchiveSize {
+			return nil, entity.ErrExportArchiveTooBig
+		}
+
+		w.job.ModulesCount++
+		w.job.CardsCount += cardsCount
+
+		progress := (i + 1) * exportProgressCompleted / len(modules)
+		if progress >= reportedProgress+exportProgressStep && progress < exportProgressCompleted {
+			reportedProgress = progress - progress%exportProgressStep
+			w.jobEvents.publishExport(ctx, entity.JobEventProgress, w.job, reportedProgress)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	if archive.Len() > maxExportArchiveSize {
+		return nil, entity.ErrExportArchiveTooBig
+	}
+
+	return archive.Bytes(), nil
+}
+
+// moduleFile builds the module's file of the job's format and returns it with the number of its cards.
+func (w *LibraryExportWork) moduleFile(ctx context.Context, module *entity.Module) ([]byte, int, error) {
+	if w.job.Format == entity.ExportFormatJSON {
+		moduleArchive, err := buildModuleArchive(ctx, w.modulesRepo, w.job.UserUUID, module.UUID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		content, err := json.MarshalIndent(moduleArchive, "", "  ")
+
+		return content, len(moduleArchive.Module.Cards), err
+	}
+
+	cards, err := w.cardsRepo.GetModuleCards(ctx, module.UUID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	moduleWithCards := &entity.ModuleWithCards{
+		Module: *module,
+		Cards:  cards,
+	}
+
+	var content []byte
+
+	switch w.job.Format {
+	case entity.ExportFormatCSV:
+		content, err = buildModuleCSV(moduleWithCards)
+	case entity.ExportFormatXLSX:
+		content, err = buildModuleXLSX(moduleWithCards)
+	case entity.ExportFormatAPKG:
+		content, err = buildModuleAPKG(ctx, w.reviewRepo, w.job.UserUUID, moduleWithCards, false)
+	default:
+		err = fmt.Errorf("export format \"%s\" is unknown", w.job.Format)
+	}
+
+	return content, len(cards), err
+}
+
+// exportFileName makes a file name of the module name which is unique within the archive,
+// repeated names get numbers. Names are compared case-insensitively, since some file systems do so.
+func exportFileName(moduleName string, format string, used map[string]bool) string {
+	base := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < ' ' {
+			return -1
+		}
+
+		return r
+	}, exportFileNameReplacer.Replace(moduleName)))
+
+	if strings.Trim(base, ".") == "" {
+		base = defaultExportFileName
+	}
+
+	name := base + "." + format
+
+	for n := 2; used[strings.ToLower(name)]; n++ {
+		name = fmt.Sprintf("%s (%d).%s", base, n, format)
+	}
+
+	used[strings.ToLower(name)] = true
+
+	return name
+}
+
+// buildModuleCSV writes the module's cards as csv records of a term and a meaning.
+func buildModuleCSV(moduleWithCards *entity.ModuleWithCards) ([]byte, error) {
+	var content bytes.Buffer
+
+	csvWriter := csv.NewWriter(&content)
+
+	for _, card := range moduleWithCards.Cards {
+		if err := csvWriter.Write([]string{card.Term, card.Meaning}); err != nil {
+			return nil, err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return content.Bytes(), csvWriter.Error()
+}
+
+// buildModuleXLSX writes the module's cards to a single sheet named after the module.
+func buildModuleXLSX(moduleWithCards *entity.ModuleWithCards) ([]byte, error) {
+	sheet := &xlsx.Sheet{
+		Name: moduleWithCards.Name,
+		Rows: make([][]string, 0, len(moduleWithCards.Cards)),
+	}
+
+	for _, card := range moduleWithCards.Cards {
+		sheet.Rows = append(sheet.Rows, []string{card.Term, card.Meaning})
+	}
+
+	var workbook bytes.Buffer
+
+	if err := xlsx.Write(&workbook, []*xlsx.Sheet{sheet}); err != nil {
+		return nil, err
+	}
+
+	return workbook.Bytes(), nil
+}
+
+// buildModuleAPKG writes the module as an anki deck, user's review states of the cards
+// become anki scheduling data.
+func buildModuleAPKG(
+	ctx context.Context,
+	reviewRepo ReviewRepository,
+	userUUID string,
+	moduleWithCards *entity.ModuleWithCards,
+	reversed bool,
+) ([]byte, error) {
+	cardUUIDs := make([]string, 0, len(moduleWithCards.Cards))
+
+	for _, card := range moduleWithCards.Cards {
+		cardUUIDs = append(cardUUIDs, card.UUID)
+	}
+
+	states, err := reviewRepo.GetReviewStates(ctx, userUUID, cardUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := &apkg.Deck{
+		Name:  moduleWithCards.Name,
+		Cards: make([]apkg.Card, 0, len(moduleWithCards.Cards)),
+	}
+
+	for _, card := range moduleWithCards.Cards {
+		deckCard := apkg.Card{
+			ID:      card.UUID,
+			Term:    card.Term,
+			Meaning: card.Meaning,
+		}
+
+		if state, ok := states[card.UUID]; ok {
+			deckCard.Schedule = &apkg.Schedule{
+				DueAt:        state.DueAt,
+				IntervalDays: state.IntervalDays,
+				EaseFactor:   state.EaseFactor,
+				Repetitions:  state.Repetitions,
+				Lapses:       state.Lapses,
+				Stability:    state.Stability,
+				Difficulty:   state.Difficulty,
+				Suspended:    state.IsSuspended,
+			}
+		}
+
+		deck.Cards = append(deck.Cards, deckCard)
+	}
+
+	var apkgPackage bytes.Buffer
+
+	err = apkg.WritePackage(&apkgPackage, []*apkg.Deck{deck}, apkg.ExportOptions{Reversed: reversed})
+	if err != nil {
+		return nil, err
+	}
+
+	return apkgPackage.Bytes(), nil
+}
+
+// buildModuleArchive builds a lossless archive of the module of the latest version.
+func buildModuleArchive(
+	ctx context.Context,
+	modulesRepo ModulesRepository,
+	userUUID string,
+	moduleUUID string,
+) (*entity.ModuleArchive, error) {
+	module, err := modulesRepo.GetArchivedModule(ctx, userUUID, moduleUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ModuleArchive{
+		Format:     entity.ModuleArchiveFormat,
+		Version:    entity.ModuleArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Module:     module,
+	}, nil
+}
+
+type libraryExportPayload struct {
+	Job *entity.ExportJob `json:"job"`
+}
+
+// LibraryExportCodec stores library export works in a durable queue, modules are read when the work is done.
+type LibraryExportCodec struct {
+	modulesRepo ModulesRepository
+	cardsRepo   CardsRepository
+	reviewRepo  ReviewRepository
+	jobsRepo    ExportJobsRepository
+	jobEvents   *JobEvents
+	log         *zerolog.Logger
+}
+
+func NewLibraryExportCodec(
+	modulesRepo ModulesRepository,
+	cardsRepo CardsRepository,
+	reviewRepo ReviewRepository,
+	jobsRepo ExportJobsRepository,
+	jobEvents *JobEvents,
+	log *zerolog.Logger,
+) *LibraryExportCodec {
+	return &LibraryExportCodec{
+		modulesRepo: modulesRepo,
+		cardsRepo:   cardsRepo,
+		reviewRepo:  reviewRepo,
+		jobsRepo:    jobsRepo,
+		jobEvents:   jobEvents,
+		log:         log,
+	}
+}
+
+func (c *LibraryExportCodec) Encode(w *LibraryExportWork) ([]byte, error) {
+	return json.Marshal(libraryExportPayload{Job: w.job})
+}
+
+func (c *LibraryExportCodec) Decode(payload []byte) (*LibraryExportWork, error) {
+	var data libraryExportPayload
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	return &LibraryExportWork{
+		modulesRepo: c.modulesRepo,
+		cardsRepo:   c.cardsRepo,
+		reviewRepo:  c.reviewRepo,
+		jobsRepo:    c.jobsRepo,
+		jobEvents:   c.jobEvents,
+		log:         c.log,
+		job:         data.Job,
+	}, nil
+}
+
+func failExportJob(
+	ctx context.Context,
+	repo ExportJobsRepository,
+	events *JobEvents,
+	log *zerolog.Logger,
+	job *entity.ExportJob,
+	err error,
+) {
+	job.Status = entity.ExportStatusFailed
+	job.ErrorMessage = err.Error()
+
+	if updateExportJob(ctx, repo, log, job) == nil {
+		events.publishExport(ctx, entity.JobEventFailed, job, 0)
+	}
+}
+
+// updateExportJob stores the job's progress even if the work has been interrupted.
+// A failed update is only logged and doesn't stop the export.
+func updateExportJob(ctx context.Context, repo ExportJobsRepository, log *zerolog.Logger, job *entity.ExportJob) error {
+	err := repo.UpdateExportJob(context.WithoutCancel(ctx), job)
+	if err != nil {
+		log.Error().Err(err).Str("job_uuid", job.UUID).Msg("export job updating failed")
+	}
+
+	return err
+}
+
+// ExportArchivesCleaner periodically frees the storage of expired export archives.
+type ExportArchivesCleaner struct {
+	jobsRepo ExportJobsRepository
+	interval time.Duration
+	log      *zerolog.Logger
+	ctx      context.Context
+	cancel   context.CancelFunc
+	runOnce  sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewExportArchivesCleaner(
+	jobsRepo ExportJobsRepository,
+	interval time.Duration,
+	log *zerolog.Logger,
+) *ExportArchivesCleaner {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &ExportArchivesCleaner{
+		jobsRepo: jobsRepo,
+		interval: interval,
+		log:      log,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Run removes expired archives in background right away and then every interval till the cleaner is closed.
+func (c *ExportArchivesCleaner) Run() {
+	c.runOnce.Do(func() {
+		c.wg.Add(1)
+
+		go func() {
+			defer c.wg.Done()
+
+			ticker := time.NewTicker(c.interval)
+			defer ticker.Stop()
+
+			for {
+				c.removeExpiredArchives()
+
+				select {
+				case <-c.ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	})
+}
+
+func (c *ExportArchivesCleaner) Close() {
+	c.cancel()
+}
+
+func (c *ExportArchivesCleaner) Wait() {
+	c.wg.Wait()
+}
+
+func (c *ExportArchivesCleaner) removeExpiredArchives() {
+	removed, err := c.jobsRepo.DeleteExpiredExportArchives(c.ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			c.log.Error().Err(err).Msg("expired export archives removing failed")
+		}
+
+		return
+	}
+
+	if removed > 0 {
+		c.log.Info().Msgf("%d expired export archives removed", removed)
+	}
+}
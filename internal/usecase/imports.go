@@ -1,39 +1,125 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
-	"encoding/csv"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"io"
+	"net"
+	"slices"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/pkg/apkg"
+	"github.com/llravell/simple-cards/pkg/csvcards"
+	"github.com/llravell/simple-cards/pkg/quizlet"
+	"github.com/llravell/simple-cards/pkg/textnorm"
+	"github.com/llravell/simple-cards/pkg/xlsx"
 	"github.com/rs/zerolog"
 )
 
-const csvRecordMinLength = 2
+const (
+	// maxImportRowErrors limits row errors stored with an import job.
+	maxImportRowErrors = 1000
+
+	// Import progress is reported in steps, parsing takes most of it and storing takes the rest.
+	importProgressStep      = 10
+	importProgressParsed    = 90
+	importProgressCompleted = 100
+
+	// maxImportModuleNameLength is the limit of module names, longer names of imported decks are cut.
+	maxImportModuleNameLength = 100
+)
+
+// RunningImports keeps cancel functions of import works running in this process,
+// so a canceled import job interrupts its work right away.
+type RunningImports struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelCauseFunc
+}
+
+func NewRunningImports() *RunningImports {
+	return &RunningImports{
+		cancels: make(map[string]context.CancelCauseFunc),
+	}
+}
+
+// track returns the work's context and a function which must be called when the work is done.
+func (ri *RunningImports) track(ctx context.Context, jobUUID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	ri.mu.Lock()
+	ri.cancels[jobUUID] = cancel
+	ri.mu.Unlock()
+
+	return ctx, func() {
+		ri.mu.Lock()
+		delete(ri.cancels, jobUUID)
+		ri.mu.Unlock()
+
+		cancel(nil)
+	}
+}
+
+// cancel interrupts the job's work if it's running in this process.
+func (ri *RunningImports) cancel(jobUUID string) {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	if cancel, ok := ri.cancels[jobUUID]; ok {
+		cancel(entity.ErrImportJobCanceled)
+	}
+}
 
 type QuizletImportWork struct {
 	repo                ModulesRepository
+	cardsRepo           CardsRepository
+	jobsRepo            ImportJobsRepository
 	quizletModuleParser QuizletModuleParser
+	runningImports      *RunningImports
+	jobEvents           *JobEvents
 	log                 *zerolog.Logger
+	job                 *entity.ImportJob
 	module              *entity.Module
 	quizletModuleID     string
 }
 
-func (w *QuizletImportWork) Do(ctx context.Context) {
+// Do imports the quizlet module. Errors are returned to retry the work later,
+// the import job fails right away only when the module has no cards.
+// A canceled job is left as is.
+func (w *QuizletImportWork) Do(ctx context.Context) error {
+	ctx, done := w.runningImports.track(ctx, w.job.UUID)
+	defer done()
+
+	err := startImportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job)
+	if isImportJobClosed(err) {
+		return nil
+	}
+
 	quizletCards, err := w.quizletModuleParser.Parse(ctx, w.quizletModuleID)
 	if err != nil {
+		if isImportInterrupted(ctx) {
+			return nil
+		}
+
 		w.log.Error().Err(err).Msg("quizlet module parsing failed")
 
-		return
+		return err
 	}
 
 	if len(quizletCards) == 0 {
-		return
+		failImportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, entity.ErrNoCardsToImport)
+
+		return nil
 	}
 
 	w.log.Info().Msgf("quizlet module \"%s\" parsed", w.quizletModuleID)
+	w.jobEvents.publishImport(ctx, entity.JobEventProgress, w.job, importProgressParsed)
 
 	moduleCards := make([]*entity.Card, 0, len(quizletCards))
 
@@ -46,83 +132,896 @@ func (w *QuizletImportWork) Do(ctx context.Context) {
 		moduleCards = append(moduleCards, card)
 	}
 
-	err = w.repo.CreateNewModuleWithCards(
-		ctx,
-		&entity.ModuleWithCards{
-			Module: *w.module,
-			Cards:  moduleCards,
-		},
-	)
+	if isImportCanceled(ctx, w.jobsRepo, w.job) {
+		return nil
+	}
+
+	err = storeImportedCards(ctx, w.repo, w.cardsRepo, w.job, w.module, moduleCards)
 	if err != nil {
 		w.log.Error().Err(err).Msg("module from quizlet storing failed")
-	} else {
-		w.log.Info().Msgf("quizlet module \"%s\" imported", w.quizletModuleID)
+
+		return err
+	}
+
+	w.log.Info().Msgf("quizlet module \"%s\" imported", w.quizletModuleID)
+
+	return finishImportJob(ctx, w.repo, w.jobsRepo, w.jobEvents, w.log, w.job)
+}
+
+// Dead fails the import job when the work won't be retried anymore.
+func (w *QuizletImportWork) Dead(ctx context.Context, err error) {
+	failImportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, err)
+}
+
+// tableImport imports cards of a table, a csv file or a spreadsheet sheet, which is read with csvcards.
+type tableImport struct {
+	repo           ModulesRepository
+	cardsRepo      CardsRepository
+	jobsRepo       ImportJobsRepository
+	runningImports *RunningImports
+	jobEvents      *JobEvents
+	log            *zerolog.Logger
+	job            *entity.ImportJob
+	module         *entity.Module
+}
+
+// run imports the module from the table. A table which can't be read or a table without cards
+// fails the import job, other errors are returned to retry the work later. A canceled job is left as is.
+// Skipped rows are stored with the job as its row errors.
+func (ti *tableImport) run(ctx context.Context, openReader func() (*csvcards.Reader, error)) error {
+	ctx, done := ti.runningImports.track(ctx, ti.job.UUID)
+	defer done()
+
+	err := startImportJob(ctx, ti.jobsRepo, ti.jobEvents, ti.log, ti.job)
+	if isImportJobClosed(err) {
+		return nil
 	}
+
+	tableReader, err := openReader()
+	if err != nil {
+		ti.log.Error().Err(err).Msgf("%s opening error", ti.job.Source)
+		failImportJob(ctx, ti.jobsRepo, ti.jobEvents, ti.log, ti.job, err)
+
+		return nil
+	}
+
+	moduleCards, rowErrors, readErr := ti.readCards(ctx, tableReader)
+	if ctx.Err() != nil {
+		if isImportInterrupted(ctx) {
+			return nil
+		}
+
+		ti.log.Error().Msg("import work has been interrupted")
+
+		return ctx.Err()
+	}
+
+	err = saveImportRowErrors(ctx, ti.jobsRepo, ti.job, rowErrors)
+	if err != nil {
+		ti.log.Error().Err(err).Msg("import row errors storing failed")
+
+		return err
+	}
+
+	if readErr != nil {
+		ti.log.Error().Err(readErr).Msgf("%s reading error", ti.job.Source)
+		failImportJob(ctx, ti.jobsRepo, ti.jobEvents, ti.log, ti.job, readErr)
+
+		return nil
+	}
+
+	if len(moduleCards) == 0 {
+		failImportJob(ctx, ti.jobsRepo, ti.jobEvents, ti.log, ti.job, entity.ErrNoCardsToImport)
+
+		return nil
+	}
+
+	if isImportCanceled(ctx, ti.jobsRepo, ti.job) {
+		return nil
+	}
+
+	err = storeImportedCards(ctx, ti.repo, ti.cardsRepo, ti.job, ti.module, moduleCards)
+	if err != nil {
+		ti.log.Error().Err(err).Msgf("module from %s storing failed", ti.job.Source)
+
+		return err
+	}
+
+	ti.log.Info().Msgf("%s module imported", ti.job.Source)
+
+	return finishImportJob(ctx, ti.repo, ti.jobsRepo, ti.jobEvents, ti.log, ti.job)
+}
+
+// readCards reads cards till the end of the table and collects skipped rows.
+// Reading stops on a malformed row unless the dialect is lenient, or when the work is interrupted.
+func (ti *tableImport) readCards(
+	ctx context.Context,
+	tableReader *csvcards.Reader,
+) ([]*entity.Card, []*entity.ImportRowError, error) {
+	moduleCards := make([]*entity.Card, 0)
+	rowErrors := make([]*entity.ImportRowError, 0)
+	reportedProgress := 0
+
+	for ctx.Err() == nil {
+		progress := tableReader.Progress() * importProgressParsed / 100
+		if progress >= reportedProgress+importProgressStep {
+			reportedProgress = progress - progress%importProgressStep
+			ti.jobEvents.publishImport(ctx, entity.JobEventProgress, ti.job, reportedProgress)
+		}
+
+		tableCard, err := tableReader.Read()
+		if errors.Is(err, io.EOF) {
+			return moduleCards, rowErrors, nil
+		}
+
+		var rowErr *csvcards.RowError
+
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, &entity.ImportRowError{
+				Line:   rowErr.Line,
+				Reason: rowErr.Reason,
+				Raw:    rowErr.Raw,
+			})
+
+			continue
+		}
+
+		if err != nil {
+			return moduleCards, rowErrors, err
+		}
+
+		moduleCards = append(moduleCards, &entity.Card{
+			Term:       tableCard.Term,
+			Meaning:    tableCard.Meaning,
+			ModuleUUID: ti.module.UUID,
+		})
+	}
+
+	return moduleCards, rowErrors, ctx.Err()
+}
+
+// Dead fails the import job when the work won't be retried anymore.
+func (ti *tableImport) Dead(ctx context.Context, err error) {
+	failImportJob(ctx, ti.jobsRepo, ti.jobEvents, ti.log, ti.job, err)
 }
 
 type CSVImportWork struct {
-	repo   ModulesRepository
-	log    *zerolog.Logger
-	module *entity.Module
-	reader io.ReadCloser
+	tableImport
+	dialect csvcards.Dialect
+	reader  io.ReadCloser
 }
 
-func (w *CSVImportWork) Do(ctx context.Context) {
+// Do imports the module from csv of the dialect, a malformed csv fails the import job.
+func (w *CSVImportWork) Do(ctx context.Context) error {
 	defer w.reader.Close()
 
-	moduleCards := make([]*entity.Card, 0)
-	csvReader := csv.NewReader(w.reader)
+	return w.run(ctx, func() (*csvcards.Reader, error) {
+		return csvcards.NewReader(w.reader, w.dialect)
+	})
+}
 
-	for {
-		var (
-			record []string
-			err    error
-		)
+type XLSXImportWork struct {
+	tableImport
+	sheet   string
+	dialect csvcards.Dialect
+	content []byte
+}
 
-		select {
-		case <-ctx.Done():
-			w.log.Error().Msg("import work has been interrupted")
+// Do imports the module from rows of the workbook's sheet, columns are mapped with the dialect.
+// A corrupted workbook or a missing sheet fails the import job.
+func (w *XLSXImportWork) Do(ctx context.Context) error {
+	return w.run(ctx, func() (*csvcards.Reader, error) {
+		workbook, err := xlsx.Open(bytes.NewReader(w.content), int64(len(w.content)))
+		if err != nil {
+			return nil, err
+		}
 
-			return
-		default:
-			record, err = csvReader.Read()
+		rows, err := workbook.Rows(w.sheet)
+		if err != nil {
+			return nil, err
+		}
+
+		return csvcards.NewRecordsReader(rows, w.dialect), nil
+	})
+}
+
+type APKGImportWork struct {
+	repo           ModulesRepository
+	jobsRepo       ImportJobsRepository
+	runningImports *RunningImports
+	jobEvents      *JobEvents
+	log            *zerolog.Logger
+	job            *entity.ImportJob
+	mapping        apkg.FieldMapping
+	collection     []byte
+}
+
+// Do imports every deck of the anki collection as a new module. An unreadable collection
+// or a collection without cards fails the import job, other errors are returned to retry the work later.
+// A canceled job is left as is. Skipped notes are stored with the job as its row errors.
+func (w *APKGImportWork) Do(ctx context.Context) error {
+	ctx, done := w.runningImports.track(ctx, w.job.UUID)
+	defer done()
+
+	err := startImportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job)
+	if isImportJobClosed(err) {
+		return nil
+	}
+
+	decks, noteErrors, err := apkg.ReadDecks(ctx, w.collection, w.mapping)
+	if err != nil {
+		if isImportInterrupted(ctx) {
+			return nil
+		}
+
+		// the lease has been lost, the collection is read again by another worker
+		if ctx.Err() != nil {
+			return err
+		}
+
+		w.log.Error().Err(err).Msg("anki collection reading error")
+		failImportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, err)
+
+		return nil
+	}
+
+	w.jobEvents.publishImport(ctx, entity.JobEventProgress, w.job, importProgressParsed)
+
+	rowErrors := make([]*entity.ImportRowError, 0, len(noteErrors))
+
+	for _, noteErr := range noteErrors {
+		rowErrors = append(rowErrors, &entity.ImportRowError{
+			Line:   noteErr.Number,
+			Reason: noteErr.Reason,
+			Raw:    noteErr.Raw,
+		})
+	}
+
+	err = saveImportRowErrors(ctx, w.jobsRepo, w.job, rowErrors)
+	if err != nil {
+		w.log.Error().Err(err).Msg("import row errors storing failed")
+
+		return err
+	}
+
+	if len(decks) == 0 {
+		failImportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, entity.ErrNoCardsToImport)
+
+		return nil
+	}
+
+	modules := make([]*entity.ModuleWithCards, 0, len(decks))
+	cardsCount := 0
+
+	for _, deck := range decks {
+		moduleCards := make([]*entity.Card, 0, len(deck.Cards))
+
+		for _, deckCard := range deck.Cards {
+			moduleCards = append(moduleCards, &entity.Card{
+				Term:    deckCard.Term,
+				Meaning: deckCard.Meaning,
+			})
+		}
+
+		modules = append(modules, &entity.ModuleWithCards{
+			Module: entity.Module{
+				Name:     importModuleName(deck.Name),
+				UserUUID: w.job.UserUUID,
+			},
+			Cards: moduleCards,
+		})
+		cardsCount += len(moduleCards)
+	}
+
+	if isImportCanceled(ctx, w.jobsRepo, w.job) {
+		return nil
+	}
+
+	err = w.repo.CreateNewModulesWithCards(ctx, modules)
+	if err != nil {
+		w.log.Error().Err(err).Msg("modules from anki decks storing failed")
+
+		return err
+	}
+
+	w.job.ModuleUUIDs = make([]string, 0, len(modules))
+
+	for _, module := range modules {
+		w.job.ModuleUUIDs = append(w.job.ModuleUUIDs, module.UUID)
+	}
+
+	w.job.ModuleUUID = w.job.ModuleUUIDs[0]
+	w.job.CardsCount = cardsCount
+
+	w.log.Info().Msgf("%d anki decks imported", len(modules))
+
+	return finishImportJob(ctx, w.repo, w.jobsRepo, w.jobEvents, w.log, w.job)
+}
+
+// Dead fails the import job when the work won't be retried anymore.
+func (w *APKGImportWork) Dead(ctx context.Context, err error) {
+	failImportJob(ctx, w.jobsRepo, w.jobEvents, w.log, w.job, err)
+}
+
+// importModuleName cuts the name to the module name limit.
+func importModuleName(name string) string {
+	if utf8.RuneCountInString(name) <= maxImportModuleNameLength {
+		return name
+	}
+
+	return string([]rune(name)[:maxImportModuleNameLength])
+}
+
+type quizletImportPayload struct {
+	Job             *entity.ImportJob `json:"job"`
+	Module          *entity.Module    `json:"module"`
+	QuizletModuleID string            `json:"quizlet_module_id"`
+}
+
+// QuizletImportCodec stores quizlet import works in a durable queue
+// and provides restored works with their dependencies.
+type QuizletImportCodec struct {
+	repo                ModulesRepository
+	cardsRepo           CardsRepository
+	jobsRepo            ImportJobsRepository
+	quizletModuleParser QuizletModuleParser
+	runningImports      *RunningImports
+	jobEvents           *JobEvents
+	log                 *zerolog.Logger
+}
+
+func NewQuizletImportCodec(
+	repo ModulesRepository,
+	cardsRepo CardsRepository,
+	jobsRepo ImportJobsRepository,
+	quizletModuleParser QuizletModuleParser,
+	runningImports *RunningImports,
+	jobEvents *JobEvents,
+	log *zerolog.Logger,
+) *QuizletImportCodec {
+	return &QuizletImportCodec{
+		repo:                repo,
+		cardsRepo:           cardsRepo,
+		jobsRepo:            jobsRepo,
+		quizletModuleParser: quizletModuleParser,
+		runningImports:      runningImports,
+		jobEvents:           jobEvents,
+		log:                 log,
+	}
+}
+
+func (c *QuizletImportCodec) Encode(w *QuizletImportWork) ([]byte, error) {
+	return json.Marshal(quizletImportPayload{
+		Job:             w.job,
+		Module:          w.module,
+		QuizletModuleID: w.quizletModuleID,
+	})
+}
+
+func (c *QuizletImportCodec) Decode(payload []byte) (*QuizletImportWork, error) {
+	var data quizletImportPayload
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	return &QuizletImportWork{
+		repo:                c.repo,
+		cardsRepo:           c.cardsRepo,
+		jobsRepo:            c.jobsRepo,
+		quizletModuleParser: c.quizletModuleParser,
+		runningImports:      c.runningImports,
+		jobEvents:           c.jobEvents,
+		log:                 c.log,
+		job:                 data.Job,
+		module:              data.Module,
+		quizletModuleID:     data.QuizletModuleID,
+	}, nil
+}
+
+type csvImportPayload struct {
+	Job     *entity.ImportJob `json:"job"`
+	Module  *entity.Module    `json:"module"`
+	Dialect csvcards.Dialect  `json:"dialect"`
+	Content []byte            `json:"content"`
+}
+
+// CSVImportCodec stores csv import works in a durable queue together with the csv content,
+// restored works read the stored content.
+type CSVImportCodec struct {
+	repo           ModulesRepository
+	cardsRepo      CardsRepository
+	jobsRepo       ImportJobsRepository
+	runningImports *RunningImports
+	jobEvents      *JobEvents
+	log            *zerolog.Logger
+}
+
+func NewCSVImportCodec(
+	repo ModulesRepository,
+	cardsRepo CardsRepository,
+	jobsRepo ImportJobsRepository,
+	runningImports *RunningImports,
+	jobEvents *JobEvents,
+	log *zerolog.Logger,
+) *CSVImportCodec {
+	return &CSVImportCodec{
+		repo:           repo,
+		cardsRepo:      cardsRepo,
+		jobsRepo:       jobsRepo,
+		runningImports: runningImports,
+		jobEvents:      jobEvents,
+		log:            log,
+	}
+}
+
+// Encode reads the whole csv content and closes the work's reader.
+func (c *CSVImportCodec) Encode(w *CSVImportWork) ([]byte, error) {
+	defer w.reader.Close()
+
+	content, err := io.ReadAll(w.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(csvImportPayload{
+		Job:     w.job,
+		Module:  w.module,
+		Dialect: w.dialect,
+		Content: content,
+	})
+}
+
+func (c *CSVImportCodec) Decode(payload []byte) (*CSVImportWork, error) {
+	var data csvImportPayload
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	return &CSVImportWork{
+		tableImport: tableImport{
+			repo:           c.repo,
+			cardsRepo:      c.cardsRepo,
+			jobsRepo:       c.jobsRepo,
+			runningImports: c.runningImports,
+			jobEvents:      c.jobEvents,
+			log:            c.log,
+			job:            data.Job,
+			module:         data.Module,
+		},
+		dialect: data.Dialect,
+		reader:  io.NopCloser(bytes.NewReader(data.Content)),
+	}, nil
+}
+
+type xlsxImportPayload struct {
+	Job     *entity.ImportJob `json:"job"`
+	Module  *entity.Module    `json:"module"`
+	Sheet   string            `json:"sheet"`
+	Dialect csvcards.Dialect  `json:"dialect"`
+	Content []byte            `json:"content"`
+}
+
+// XLSXImportCodec stores xlsx import works in a durable queue together with the workbook.
+type XLSXImportCodec struct {
+	repo           ModulesRepository
+	cardsRepo      CardsRepository
+	jobsRepo       ImportJobsRepository
+	runningImports *RunningImports
+	jobEvents      *JobEvents
+	log            *zerolog.Logger
+}
+
+func NewXLSXImportCodec(
+	repo ModulesRepository,
+	cardsRepo CardsRepository,
+	jobsRepo ImportJobsRepository,
+	runningImports *RunningImports,
+	jobEvents *JobEvents,
+	log *zerolog.Logger,
+) *XLSXImportCodec {
+	return &XLSXImportCodec{
+		repo:           repo,
+		cardsRepo:      cardsRepo,
+		jobsRepo:       jobsRepo,
+		runningImports: runningImports,
+		jobEvents:      jobEvents,
+		log:            log,
+	}
+}
+
+func (c *XLSXImportCodec) Encode(w *XLSXImportWork) ([]byte, error) {
+	return json.Marshal(xlsxImportPayload{
+		Job:     w.job,
+		Module:  w.module,
+		Sheet:   w.sheet,
+		Dialect: w.dialect,
+		Content: w.content,
+	})
+}
+
+func (c *XLSXImportCodec) Decode(payload []byte) (*XLSXImportWork, error) {
+	var data xlsxImportPayload
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	return &XLSXImportWork{
+		tableImport: tableImport{
+			repo:           c.repo,
+			cardsRepo:      c.cardsRepo,
+			jobsRepo:       c.jobsRepo,
+			runningImports: c.runningImports,
+			jobEvents:      c.jobEvents,
+			log:            c.log,
+			job:            data.Job,
+			module:         data.Module,
+		},
+		sheet:   data.Sheet,
+		dialect: data.Dialect,
+		content: data.Content,
+	}, nil
+}
+
+type apkgImportPayload struct {
+	Job        *entity.ImportJob `json:"job"`
+	Mapping    apkg.FieldMapping `json:"mapping"`
+	Collection []byte            `json:"collection"`
+}
+
+// APKGImportCodec stores anki import works in a durable queue together with the collection database,
+// media files of the package are not stored.
+type APKGImportCodec struct {
+	repo           ModulesRepository
+	jobsRepo       ImportJobsRepository
+	runningImports *RunningImports
+	jobEvents      *JobEvents
+	log            *zerolog.Logger
+}
+
+func NewAPKGImportCodec(
+	repo ModulesRepository,
+	jobsRepo ImportJobsRepository,
+	runningImports *RunningImports,
+	jobEvents *JobEvents,
+	log *zerolog.Logger,
+) *APKGImportCodec {
+	return &APKGImportCodec{
+		repo:           repo,
+		jobsRepo:       jobsRepo,
+		runningImports: runningImports,
+		jobEvents:      jobEvents,
+		log:            log,
+	}
+}
+
+func (c *APKGImportCodec) Encode(w *APKGImportWork) ([]byte, error) {
+	return json.Marshal(apkgImportPayload{
+		Job:        w.job,
+		Mapping:    w.mapping,
+		Collection: w.collection,
+	})
+}
+
+func (c *APKGImportCodec) Decode(payload []byte) (*APKGImportWork, error) {
+	var data apkgImportPayload
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	return &APKGImportWork{
+		repo:           c.repo,
+		jobsRepo:       c.jobsRepo,
+		runningImports: c.runningImports,
+		jobEvents:      c.jobEvents,
+		log:            c.log,
+		job:            data.Job,
+		mapping:        data.Mapping,
+		collection:     data.Collection,
+	}, nil
+}
+
+// storeImportedCards creates a new module with the imported cards,
+// or merges them into the existing module according to the job's strategy. The job gets the import results.
+func storeImportedCards(
+	ctx context.Context,
+	modulesRepo ModulesRepository,
+	cardsRepo CardsRepository,
+	job *entity.ImportJob,
+	module *entity.Module,
+	cards []*entity.Card,
+) error {
+	if job.Strategy == "" {
+		moduleWithCards := &entity.ModuleWithCards{
+			Module: *module,
+			Cards:  cards,
 		}
 
+		err := modulesRepo.CreateNewModuleWithCards(ctx, moduleWithCards)
 		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
+			return err
+		}
+
+		job.ModuleUUID = moduleWithCards.UUID
+		job.CardsCount = len(cards)
+
+		return nil
+	}
+
+	exists, err := modulesRepo.ModuleExists(ctx, module.UserUUID, module.UUID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return &entity.ModuleNotFoundError{UUID: module.UUID}
+	}
+
+	existingCards, err := cardsRepo.GetModuleCards(ctx, module.UUID)
+	if err != nil {
+		return err
+	}
+
+	newCards, updatedCards, skippedCount := mergeImportedCards(existingCards, cards, job.Strategy)
+
+	err = modulesRepo.AddCardsToModule(ctx, module.UUID, newCards, updatedCards)
+	if err != nil {
+		return err
+	}
 
-			w.log.Error().Err(err).Msg("csv reading error")
+	job.CardsCount = len(newCards)
+	job.UpdatedCardsCount = len(updatedCards)
+	job.SkippedCardsCount = skippedCount
 
-			return
+	return nil
+}
+
+// mergeImportedCards splits imported cards into new cards and existing cards with overwritten meanings.
+// Duplicates are matched by normalized terms, both with existing cards and within the import itself.
+func mergeImportedCards(
+	existingCards []*entity.Card,
+	importedCards []*entity.Card,
+	strategy string,
+) ([]*entity.Card, []*entity.Card, int) {
+	if strategy == entity.ImportStrategyAppend {
+		return importedCards, nil, 0
+	}
+
+	cardsByTerm := make(map[string]*entity.Card, len(existingCards)+len(importedCards))
+	isExisting := make(map[*entity.Card]bool, len(existingCards))
+
+	for _, card := range existingCards {
+		key := importTermKey(card.Term)
+
+		if _, ok := cardsByTerm[key]; !ok {
+			cardsByTerm[key] = card
+			isExisting[card] = true
 		}
+	}
+
+	newCards := make([]*entity.Card, 0, len(importedCards))
+	updatedCards := make([]*entity.Card, 0)
+	isUpdated := make(map[*entity.Card]bool)
+	skippedCount := 0
+
+	for _, card := range importedCards {
+		key := importTermKey(card.Term)
+
+		duplicate, ok := cardsByTerm[key]
+		if !ok {
+			cardsByTerm[key] = card
+			newCards = append(newCards, card)
 
-		if len(record) < csvRecordMinLength {
 			continue
 		}
 
-		card := &entity.Card{
-			Term:       strings.TrimSpace(record[0]),
-			Meaning:    strings.TrimSpace(record[1]),
-			ModuleUUID: w.module.UUID,
+		if strategy == entity.ImportStrategySkipDuplicates || duplicate.Meaning == card.Meaning {
+			skippedCount++
+
+			continue
 		}
 
-		if card.Term != "" && card.Meaning != "" {
-			moduleCards = append(moduleCards, card)
+		duplicate.Meaning = card.Meaning
+
+		if isExisting[duplicate] && !isUpdated[duplicate] {
+			isUpdated[duplicate] = true
+			updatedCards = append(updatedCards, duplicate)
 		}
 	}
 
-	err := w.repo.CreateNewModuleWithCards(
-		ctx,
-		&entity.ModuleWithCards{
-			Module: *w.module,
-			Cards:  moduleCards,
-		},
-	)
+	return newCards, updatedCards, skippedCount
+}
+
+// importTermKey matches terms regardless of case, diacritics and punctuation,
+// terms without letters and digits are matched as is.
+func importTermKey(term string) string {
+	key := textnorm.Normalize(term, "")
+	if key == "" {
+		return strings.TrimSpace(term)
+	}
+
+	return key
+}
+
+// saveImportRowErrors stores first maxImportRowErrors row errors, the job counts all of them.
+func saveImportRowErrors(
+	ctx context.Context,
+	repo ImportJobsRepository,
+	job *entity.ImportJob,
+	rowErrors []*entity.ImportRowError,
+) error {
+	job.ErrorsCount = len(rowErrors)
+
+	if len(rowErrors) == 0 {
+		return nil
+	}
+
+	return repo.SaveImportJobErrors(ctx, job.UUID, rowErrors[:min(len(rowErrors), maxImportRowErrors)])
+}
+
+// startImportJob marks the job as processing. A queued work may be delivered again after it has been done,
+// so ErrImportJobCanceled or ErrImportJobFinished is returned for a job which must not be imported anymore.
+func startImportJob(
+	ctx context.Context,
+	repo ImportJobsRepository,
+	events *JobEvents,
+	log *zerolog.Logger,
+	job *entity.ImportJob,
+) error {
+	job.Status = entity.ImportStatusProcessing
+
+	err := updateImportJob(ctx, repo, log, job)
+	if err == nil {
+		events.publishImport(ctx, entity.JobEventStarted, job, 0)
+	}
+
+	return err
+}
+
+// finishImportJob removes the imported modules if the job has been canceled while the modules were stored,
+// or finished by another delivery of the work, so the modules would be duplicates.
+// Cards merged into an existing module are kept. Modules which can't be removed are reported with the error,
+// so the failed cleanup ends up in the queue instead of leaving the modules silently.
+// A failed update of the job is returned as well to retry the work.
+func finishImportJob(
+	ctx context.Context,
+	modulesRepo ModulesRepository,
+	repo ImportJobsRepository,
+	events *JobEvents,
+	log *zerolog.Logger,
+	job *entity.ImportJob,
+) error {
+	job.Status = entity.ImportStatusProcessed
+
+	err := updateImportJob(ctx, repo, log, job)
+
+	switch {
+	case err == nil:
+		events.publishImport(ctx, entity.JobEventCompleted, job, importProgressCompleted)
+
+		return nil
+	case !isImportJobClosed(err):
+		// the job is still open, so the retried work finishes it
+		return err
+	case job.Strategy != "":
+		return nil
+	}
+
+	moduleUUIDs := job.ModuleUUIDs
+	if len(moduleUUIDs) == 0 {
+		moduleUUIDs = []string{job.ModuleUUID}
+	}
+
+	var deleteErrs []error
+
+	for _, moduleUUID := range moduleUUIDs {
+		err = modulesRepo.DeleteModule(context.WithoutCancel(ctx), job.UserUUID, moduleUUID)
+		if err != nil {
+			log.Error().Err(err).Str("job_uuid", job.UUID).Msg("canceled import module deleting failed")
+
+			deleteErrs = append(deleteErrs, err)
+		}
+	}
+
+	return errors.Join(deleteErrs...)
+}
+
+func failImportJob(
+	ctx context.Context,
+	repo ImportJobsRepository,
+	events *JobEvents,
+	log *zerolog.Logger,
+	job *entity.ImportJob,
+	err error,
+) {
+	job.Status = entity.ImportStatusFailed
+	job.ErrorMessage = err.Error()
+
+	if updateImportJob(ctx, repo, log, job) == nil {
+		events.publishImport(ctx, entity.JobEventFailed, job, 0)
+	}
+}
+
+// updateImportJob stores the job's progress even if the work has been interrupted.
+// A failed update is only logged and doesn't stop the import,
+// the error is returned to let the work know that the job has been canceled or finished.
+func updateImportJob(ctx context.Context, repo ImportJobsRepository, log *zerolog.Logger, job *entity.ImportJob) error {
+	err := repo.UpdateImportJob(context.WithoutCancel(ctx), job)
 	if err != nil {
-		w.log.Error().Err(err).Msg("module from csv storing failed")
-	} else {
-		w.log.Info().Msg("csv module imported")
+		switch {
+		case errors.Is(err, entity.ErrImportJobCanceled):
+			log.Info().Str("job_uuid", job.UUID).Msg("import job has been canceled")
+		case errors.Is(err, entity.ErrImportJobFinished):
+			log.Info().Str("job_uuid", job.UUID).Msg("import job is already finished")
+		default:
+			log.Error().Err(err).Str("job_uuid", job.UUID).Msg("import job updating failed")
+		}
 	}
+
+	return err
+}
+
+// isImportJobClosed tells whether the job has been canceled or finished by another delivery of the work.
+func isImportJobClosed(err error) bool {
+	return errors.Is(err, entity.ErrImportJobCanceled) || errors.Is(err, entity.ErrImportJobFinished)
+}
+
+// isImportInterrupted tells whether the work has been interrupted because its job has been canceled.
+func isImportInterrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), entity.ErrImportJobCanceled)
+}
+
+// isImportCanceled checks the stored job too, since it may be canceled by another process.
+func isImportCanceled(ctx context.Context, repo ImportJobsRepository, job *entity.ImportJob) bool {
+	if isImportInterrupted(ctx) {
+		return true
+	}
+
+	storedJob, err := repo.GetImportJob(context.WithoutCancel(ctx), job.UserUUID, job.UUID)
+
+	return err == nil && storedJob.Status == entity.ImportStatusCanceled
+}
+
+// Postgres error codes and classes of failures which may pass on the next attempt:
+// connection exceptions, insufficient resources, serialization failures, deadlocks,
+// lock timeouts and server shutdowns.
+var (
+	retryablePgErrorCodes   = []string{"40001", "40P01", "55P03", "57P01", "57P02", "57P03"}
+	retryablePgErrorClasses = []string{"08", "53"}
+)
+
+const pgErrorClassLength = 2
+
+// IsRetryableImportError tells whether a failed import work may succeed on the next attempt:
+// quizlet has refused to give the module away, or the network or the database are temporarily unavailable.
+func IsRetryableImportError(err error) bool {
+	var fetchingErr *quizlet.ModuleFetchingError
+	if errors.As(err, &fetchingErr) {
+		return true
+	}
+
+	return isTemporaryError(err)
+}
+
+// isTemporaryError tells whether the error is caused by the network or the database being temporarily unavailable.
+func isTemporaryError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return slices.Contains(retryablePgErrorCodes, pgErr.Code) ||
+			(len(pgErr.Code) >= pgErrorClassLength &&
+				slices.Contains(retryablePgErrorClasses, pgErr.Code[:pgErrorClassLength]))
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return pgconn.SafeToRetry(err) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded)
 }
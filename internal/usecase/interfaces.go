@@ -6,6 +6,7 @@ import (
 
 	"github.com/llravell/simple-cards/internal/entity"
 	"github.com/llravell/simple-cards/pkg/quizlet"
+	"github.com/llravell/simple-cards/pkg/srs"
 )
 
 //go:generate ../../bin/mockgen -source=interfaces.go -destination=../mocks/mock_usecase.go -package=mocks
@@ -22,19 +23,131 @@ type (
 
 	ModulesRepository interface {
 		GetAllModules(ctx context.Context, userUUID string) ([]*entity.Module, error)
+		GetLibraryModules(ctx context.Context, userUUID string, moduleUUIDs []string) ([]*entity.Module, error)
 		GetModule(ctx context.Context, userUUID string, moduleUUID string) (*entity.Module, error)
 		CreateNewModule(ctx context.Context, userUUID string, moduleName string) (*entity.Module, error)
 		CreateNewModuleWithCards(ctx context.Context, moduleWithCards *entity.ModuleWithCards) error
+		CreateNewModulesWithCards(ctx context.Context, modulesWithCards []*entity.ModuleWithCards) error
+		AddCardsToModule(
+			ctx context.Context,
+			moduleUUID string,
+			newCards []*entity.Card,
+			updatedCards []*entity.Card,
+		) error
 		UpdateModule(ctx context.Context, userUUID string, moduleUUID string, moduleName string) (*entity.Module, error)
 		DeleteModule(ctx context.Context, userUUID string, moduleUUID string) error
 		ModuleExists(ctx context.Context, userUUID string, moduleUUID string) (bool, error)
+		GetArchivedModule(ctx context.Context, userUUID string, moduleUUID string) (*entity.ArchivedModule, error)
+		CreateModuleFromArchive(
+			ctx context.Context,
+			userUUID string,
+			archivedModule *entity.ArchivedModule,
+		) (*entity.Module, error)
+	}
+
+	ImportJobsRepository interface {
+		CreateImportJob(ctx context.Context, job *entity.ImportJob) (*entity.ImportJob, error)
+		UpdateImportJob(ctx context.Context, job *entity.ImportJob) error
+		GetImportJobs(ctx context.Context, userUUID string) ([]*entity.ImportJob, error)
+		GetImportJob(ctx context.Context, userUUID string, jobUUID string) (*entity.ImportJob, error)
+		CancelImportJob(ctx context.Context, userUUID string, jobUUID string) (*entity.ImportJob, error)
+		SaveImportJobErrors(ctx context.Context, jobUUID string, rowErrors []*entity.ImportRowError) error
+		GetImportJobErrors(ctx context.Context, jobUUID string) ([]*entity.ImportRowError, error)
+	}
+
+	ExportJobsRepository interface {
+		CreateExportJob(ctx context.Context, job *entity.ExportJob) (*entity.ExportJob, error)
+		UpdateExportJob(ctx context.Context, job *entity.ExportJob) error
+		SaveExportArchive(ctx context.Context, job *entity.ExportJob, archive []byte) error
+		GetExportJob(ctx context.Context, userUUID string, jobUUID string) (*entity.ExportJob, error)
+		GetExportArchive(ctx context.Context, userUUID string, jobUUID string) ([]byte, error)
+		DeleteExpiredExportArchives(ctx context.Context) (int64, error)
+	}
+
+	QueueJobsRepository interface {
+		GetDeadJobs(ctx context.Context, queue string) ([]*entity.QueueJob, error)
+		RequeueDeadJob(ctx context.Context, id int64) (*entity.QueueJob, error)
 	}
 
 	CardsRepository interface {
 		GetModuleCards(ctx context.Context, moduleUUID string) ([]*entity.Card, error)
+		GetCard(ctx context.Context, moduleUUID string, cardUUID string) (*entity.Card, error)
 		CreateCard(ctx context.Context, card *entity.Card) (*entity.Card, error)
 		SaveCard(ctx context.Context, card *entity.Card) (*entity.Card, error)
 		DeleteCard(ctx context.Context, moduleUUID string, cardUUID string) error
+		GetLeeches(ctx context.Context, userUUID string, moduleUUID string) ([]*entity.LeechCard, error)
+		SetCardSuspended(
+			ctx context.Context,
+			userUUID string,
+			moduleUUID string,
+			cardUUID string,
+			isSuspended bool,
+		) (*entity.ReviewState, error)
+	}
+
+	StatsRepository interface {
+		SaveAnswers(
+			ctx context.Context,
+			userUUID string,
+			answers []*entity.Answer,
+			states []*entity.ReviewState,
+		) ([]string, error)
+		GetAnswersByClientIDs(ctx context.Context, userUUID string, clientIDs []string) (map[string]*entity.Answer, error)
+		GetCardsAnswers(ctx context.Context, userUUID string, cardUUIDs []string) ([]*entity.Answer, error)
+		GetModuleStats(
+			ctx context.Context,
+			userUUID string,
+			moduleUUID string,
+			from time.Time,
+			to time.Time,
+		) ([]*entity.CardStats, error)
+		GetDailyActivity(
+			ctx context.Context,
+			userUUID string,
+			timezone string,
+			from time.Time,
+			to time.Time,
+		) ([]*entity.DailyActivity, error)
+	}
+
+	ReviewRepository interface {
+		GetReviewStates(ctx context.Context, userUUID string, cardUUIDs []string) (map[string]*entity.ReviewState, error)
+		GetDueCards(
+			ctx context.Context,
+			userUUID string,
+			moduleUUID string,
+			dueAt time.Time,
+			limit int,
+		) ([]*entity.DueCard, error)
+	}
+
+	SettingsRepository interface {
+		GetUserSettings(ctx context.Context, userUUID string) (*entity.UserSettings, error)
+		SaveUserSettings(ctx context.Context, userUUID string, settings *entity.UserSettings) (*entity.UserSettings, error)
+		GetModuleSettings(ctx context.Context, userUUID string, moduleUUID string) (*entity.ModuleSettings, error)
+		SaveModuleSettings(
+			ctx context.Context,
+			userUUID string,
+			moduleUUID string,
+			settings *entity.ModuleSettings,
+		) (*entity.ModuleSettings, error)
+		GetModuleSchedulers(ctx context.Context, userUUID string, moduleUUIDs []string) (map[string]string, error)
+	}
+
+	SessionsRepository interface {
+		CreateSession(ctx context.Context, userUUID string, moduleUUID string) (*entity.StudySession, error)
+		FinishSession(
+			ctx context.Context,
+			userUUID string,
+			moduleUUID string,
+			sessionUUID string,
+		) (*entity.StudySession, error)
+		GetModuleSessions(ctx context.Context, userUUID string, moduleUUID string) ([]*entity.StudySession, error)
+	}
+
+	// Scheduler is a spaced repetition algorithm which computes the next review of a card.
+	Scheduler interface {
+		Review(state srs.State, grade srs.Grade, reviewedAt time.Time) srs.State
 	}
 
 	JWTIssuer interface {
@@ -52,4 +165,22 @@ type (
 	CSVImportWorkerPool interface {
 		QueueWork(w *CSVImportWork) error
 	}
+
+	APKGImportWorkerPool interface {
+		QueueWork(w *APKGImportWork) error
+	}
+
+	XLSXImportWorkerPool interface {
+		QueueWork(w *XLSXImportWork) error
+	}
+
+	LibraryExportWorkerPool interface {
+		QueueWork(w *LibraryExportWork) error
+	}
+
+	// JobEventsNotifier delivers job events to subscribers of every process.
+	JobEventsNotifier interface {
+		Notify(ctx context.Context, payload []byte) error
+		Subscribe() (<-chan []byte, func())
+	}
 )
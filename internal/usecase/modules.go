@@ -1,36 +1,68 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"slices"
+	"time"
 
 	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/pkg/apkg"
+	"github.com/llravell/simple-cards/pkg/csvcards"
+	"github.com/llravell/simple-cards/pkg/xlsx"
 	"github.com/rs/zerolog"
 )
 
 type ModulesUseCase struct {
 	modulesRepo         ModulesRepository
 	cardsRepo           CardsRepository
+	reviewRepo          ReviewRepository
+	importJobsRepo      ImportJobsRepository
+	exportJobsRepo      ExportJobsRepository
 	quizletModuleParser QuizletModuleParser
 	quizletImportWP     QuizletImportWorkerPool
 	csvImportWP         CSVImportWorkerPool
+	apkgImportWP        APKGImportWorkerPool
+	xlsxImportWP        XLSXImportWorkerPool
+	libraryExportWP     LibraryExportWorkerPool
+	runningImports      *RunningImports
+	jobEvents           *JobEvents
 	log                 *zerolog.Logger
 }
 
 func NewModulesUseCase(
 	modulesRepo ModulesRepository,
 	cardsRepo CardsRepository,
+	reviewRepo ReviewRepository,
+	importJobsRepo ImportJobsRepository,
+	exportJobsRepo ExportJobsRepository,
 	quizletModuleParser QuizletModuleParser,
 	quizletImportWP QuizletImportWorkerPool,
 	csvImportWP CSVImportWorkerPool,
+	apkgImportWP APKGImportWorkerPool,
+	xlsxImportWP XLSXImportWorkerPool,
+	libraryExportWP LibraryExportWorkerPool,
+	runningImports *RunningImports,
+	jobEvents *JobEvents,
 	log *zerolog.Logger,
 ) *ModulesUseCase {
 	return &ModulesUseCase{
 		modulesRepo:         modulesRepo,
 		cardsRepo:           cardsRepo,
+		reviewRepo:          reviewRepo,
+		importJobsRepo:      importJobsRepo,
+		exportJobsRepo:      exportJobsRepo,
 		quizletModuleParser: quizletModuleParser,
 		quizletImportWP:     quizletImportWP,
 		csvImportWP:         csvImportWP,
+		apkgImportWP:        apkgImportWP,
+		xlsxImportWP:        xlsxImportWP,
+		libraryExportWP:     libraryExportWP,
+		runningImports:      runningImports,
+		jobEvents:           jobEvents,
 		log:                 log,
 	}
 }
@@ -89,31 +121,548 @@ func (uc *ModulesUseCase) GetModuleWithCards(
 	}, nil
 }
 
+// ExportModuleToAPKG builds an anki package with the module as a deck,
+// user's review states of the cards become anki scheduling data.
+func (uc *ModulesUseCase) ExportModuleToAPKG(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	reversed bool,
+) (*entity.Module, []byte, error) {
+	moduleWithCards, err := uc.GetModuleWithCards(ctx, userUUID, moduleUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apkgPackage, err := buildModuleAPKG(ctx, uc.reviewRepo, userUUID, moduleWithCards, reversed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &moduleWithCards.Module, apkgPackage, nil
+}
+
+// ExportModuleToXLSX builds a workbook with the module's cards on a single sheet, terms are in the first column
+// and meanings are in the second one.
+func (uc *ModulesUseCase) ExportModuleToXLSX(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) (*entity.Module, []byte, error) {
+	moduleWithCards, err := uc.GetModuleWithCards(ctx, userUUID, moduleUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workbook, err := buildModuleXLSX(moduleWithCards)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &moduleWithCards.Module, workbook, nil
+}
+
+// ExportModuleToArchive builds a lossless archive of the module of the latest version.
+func (uc *ModulesUseCase) ExportModuleToArchive(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) (*entity.ModuleArchive, error) {
+	return buildModuleArchive(ctx, uc.modulesRepo, userUUID, moduleUUID)
+}
+
+// ImportModuleFromArchive restores the archived module as a new module of the user.
+// Archives of older versions are migrated, invalid archives are rejected with ModuleArchiveError.
+func (uc *ModulesUseCase) ImportModuleFromArchive(
+	ctx context.Context,
+	userUUID string,
+	data []byte,
+) (*entity.Module, error) {
+	archive, err := decodeModuleArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.modulesRepo.CreateModuleFromArchive(ctx, userUUID, archive.Module)
+}
+
+// QueueQuizletModuleImport creates an import job and queues the quizlet module import.
+// A module with UUID is an existing module which the cards are merged into with the strategy.
 func (uc *ModulesUseCase) QueueQuizletModuleImport(
+	ctx context.Context,
 	module *entity.Module,
+	strategy string,
 	quizletModuleID string,
-) error {
+) (*entity.ImportJob, error) {
+	module, job, err := uc.createImportJob(ctx, module, entity.ImportSourceQuizlet, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// the work updates its own copy of the job to not race with the caller
+	workJob := *job
+
 	importWork := &QuizletImportWork{
 		repo:                uc.modulesRepo,
+		cardsRepo:           uc.cardsRepo,
+		jobsRepo:            uc.importJobsRepo,
 		quizletModuleParser: uc.quizletModuleParser,
+		runningImports:      uc.runningImports,
+		jobEvents:           uc.jobEvents,
 		log:                 uc.log,
+		job:                 &workJob,
 		quizletModuleID:     quizletModuleID,
 		module:              module,
 	}
 
-	return uc.quizletImportWP.QueueWork(importWork)
+	uc.jobEvents.publishImport(ctx, entity.JobEventQueued, job, 0)
+
+	err = uc.quizletImportWP.QueueWork(importWork)
+	if err != nil {
+		failImportJob(ctx, uc.importJobsRepo, uc.jobEvents, uc.log, job, err)
+
+		return nil, err
+	}
+
+	return job, nil
 }
 
+// QueueCSVModuleImport creates an import job and queues the csv module import.
+// A module with UUID is an existing module which the cards are merged into with the strategy.
 func (uc *ModulesUseCase) QueueCSVModuleImport(
+	ctx context.Context,
 	module *entity.Module,
+	strategy string,
+	dialect csvcards.Dialect,
 	reader io.ReadCloser,
-) error {
+) (*entity.ImportJob, error) {
+	module, job, err := uc.createImportJob(ctx, module, entity.ImportSourceCSV, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	workJob := *job
+
 	importWork := &CSVImportWork{
-		repo:   uc.modulesRepo,
-		log:    uc.log,
-		module: module,
-		reader: reader,
+		tableImport: tableImport{
+			repo:           uc.modulesRepo,
+			cardsRepo:      uc.cardsRepo,
+			jobsRepo:       uc.importJobsRepo,
+			runningImports: uc.runningImports,
+			jobEvents:      uc.jobEvents,
+			log:            uc.log,
+			job:            &workJob,
+			module:         module,
+		},
+		dialect: dialect,
+		reader:  reader,
+	}
+
+	uc.jobEvents.publishImport(ctx, entity.JobEventQueued, job, 0)
+
+	err = uc.csvImportWP.QueueWork(importWork)
+	if err != nil {
+		failImportJob(ctx, uc.importJobsRepo, uc.jobEvents, uc.log, job, err)
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// QueueXLSXModuleImport creates an import job and queues the import of the workbook's sheet,
+// columns are mapped with the dialect. The workbook is opened right away, so a file which isn't a workbook
+// or doesn't have the sheet is rejected without a job.
+// A module with UUID is an existing module which the cards are merged into with the strategy.
+func (uc *ModulesUseCase) QueueXLSXModuleImport(
+	ctx context.Context,
+	module *entity.Module,
+	strategy string,
+	sheet string,
+	dialect csvcards.Dialect,
+	reader io.ReaderAt,
+	size int64,
+) (*entity.ImportJob, error) {
+	content, err := io.ReadAll(io.NewSectionReader(reader, 0, size))
+	if err != nil {
+		return nil, err
+	}
+
+	workbook, err := xlsx.Open(bytes.NewReader(content), size)
+	if err != nil {
+		return nil, err
+	}
+
+	if !workbook.HasSheet(sheet) {
+		return nil, &xlsx.SheetNotFoundError{Sheet: sheet}
+	}
+
+	module, job, err := uc.createImportJob(ctx, module, entity.ImportSourceXLSX, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	workJob := *job
+
+	importWork := &XLSXImportWork{
+		tableImport: tableImport{
+			repo:           uc.modulesRepo,
+			cardsRepo:      uc.cardsRepo,
+			jobsRepo:       uc.importJobsRepo,
+			runningImports: uc.runningImports,
+			jobEvents:      uc.jobEvents,
+			log:            uc.log,
+			job:            &workJob,
+			module:         module,
+		},
+		sheet:   sheet,
+		dialect: dialect,
+		content: content,
+	}
+
+	uc.jobEvents.publishImport(ctx, entity.JobEventQueued, job, 0)
+
+	err = uc.xlsxImportWP.QueueWork(importWork)
+	if err != nil {
+		failImportJob(ctx, uc.importJobsRepo, uc.jobEvents, uc.log, job, err)
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// QueueAPKGModuleImport creates an import job and queues the anki package import, every deck becomes a new module.
+// The collection is extracted from the package right away, so a package which can't be imported is rejected
+// without a job.
+func (uc *ModulesUseCase) QueueAPKGModuleImport(
+	ctx context.Context,
+	userUUID string,
+	packageName string,
+	mapping apkg.FieldMapping,
+	reader io.ReaderAt,
+	size int64,
+) (*entity.ImportJob, error) {
+	collection, err := apkg.ExtractCollection(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	module := &entity.Module{
+		UserUUID: userUUID,
+		Name:     packageName,
+	}
+
+	_, job, err := uc.createImportJob(ctx, module, entity.ImportSourceAPKG, "")
+	if err != nil {
+		return nil, err
+	}
+
+	workJob := *job
+
+	importWork := &APKGImportWork{
+		repo:           uc.modulesRepo,
+		jobsRepo:       uc.importJobsRepo,
+		runningImports: uc.runningImports,
+		jobEvents:      uc.jobEvents,
+		log:            uc.log,
+		job:            &workJob,
+		mapping:        mapping,
+		collection:     collection,
+	}
+
+	uc.jobEvents.publishImport(ctx, entity.JobEventQueued, job, 0)
+
+	err = uc.apkgImportWP.QueueWork(importWork)
+	if err != nil {
+		failImportJob(ctx, uc.importJobsRepo, uc.jobEvents, uc.log, job, err)
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// PreviewCSVImport reads first limit cards of the csv without storing anything.
+// The delimiter, the encoding and the header are detected unless the dialect sets them.
+// Skipped rows and a reading error are reported as warnings.
+func (uc *ModulesUseCase) PreviewCSVImport(
+	dialect csvcards.Dialect,
+	reader io.Reader,
+	limit int,
+) (*entity.ImportPreview, error) {
+	if dialect.Delimiter == "" {
+		dialect.Delimiter = csvcards.DelimiterAuto
+	}
+
+	if dialect.Encoding == "" {
+		dialect.Encoding = csvcards.EncodingAuto
+	}
+
+	if dialect.Header == "" {
+		dialect.Header = csvcards.HeaderAuto
+	}
+
+	csvReader, err := csvcards.NewReader(reader, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &entity.ImportPreview{
+		Source:   entity.ImportSourceCSV,
+		Cards:    make([]*entity.ImportPreviewCard, 0, limit),
+		Warnings: make([]string, 0),
+	}
+
+	for {
+		csvCard, readErr := csvReader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		var rowErr *csvcards.RowError
+
+		if errors.As(readErr, &rowErr) {
+			preview.Warnings = append(preview.Warnings, rowErr.Error())
+
+			continue
+		}
+
+		if readErr != nil {
+			preview.Warnings = append(preview.Warnings, readErr.Error())
+
+			break
+		}
+
+		if len(preview.Cards) == limit {
+			preview.HasMore = true
+
+			break
+		}
+
+		preview.Cards = append(preview.Cards, &entity.ImportPreviewCard{
+			Term:    csvCard.Term,
+			Meaning: csvCard.Meaning,
+		})
+	}
+
+	if len(preview.Cards) == 0 {
+		preview.Warnings = append(preview.Warnings, entity.ErrNoCardsToImport.Error())
+	}
+
+	resolvedDialect := csvReader.Dialect()
+	preview.Dialect = &resolvedDialect
+
+	return preview, nil
+}
+
+// PreviewQuizletImport parses the quizlet module and returns its first limit cards without storing anything.
+func (uc *ModulesUseCase) PreviewQuizletImport(
+	ctx context.Context,
+	quizletModuleID string,
+	limit int,
+) (*entity.ImportPreview, error) {
+	quizletCards, err := uc.quizletModuleParser.Parse(ctx, quizletModuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &entity.ImportPreview{
+		Source:   entity.ImportSourceQuizlet,
+		Cards:    make([]*entity.ImportPreviewCard, 0, min(len(quizletCards), limit)),
+		HasMore:  len(quizletCards) > limit,
+		Warnings: make([]string, 0),
+	}
+
+	for i, quizletCard := range quizletCards {
+		if quizletCard.Front == "" || quizletCard.Back == "" {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("card %d: term or meaning is empty", i+1))
+		}
+
+		if i < limit {
+			preview.Cards = append(preview.Cards, &entity.ImportPreviewCard{
+				Term:    quizletCard.Front,
+				Meaning: quizletCard.Back,
+			})
+		}
+	}
+
+	if len(quizletCards) == 0 {
+		preview.Warnings = append(preview.Warnings, entity.ErrNoCardsToImport.Error())
+	}
+
+	return preview, nil
+}
+
+func (uc *ModulesUseCase) GetImportJobs(ctx context.Context, userUUID string) ([]*entity.ImportJob, error) {
+	return uc.importJobsRepo.GetImportJobs(ctx, userUUID)
+}
+
+func (uc *ModulesUseCase) GetImportJob(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) (*entity.ImportJob, error) {
+	return uc.importJobsRepo.GetImportJob(ctx, userUUID, jobUUID)
+}
+
+// GetImportJobErrors returns rows skipped by the user's import job.
+func (uc *ModulesUseCase) GetImportJobErrors(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) ([]*entity.ImportRowError, error) {
+	_, err := uc.importJobsRepo.GetImportJob(ctx, userUUID, jobUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.importJobsRepo.GetImportJobErrors(ctx, jobUUID)
+}
+
+// CancelImportJob cancels a queued job or interrupts a running one.
+func (uc *ModulesUseCase) CancelImportJob(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) (*entity.ImportJob, error) {
+	job, err := uc.importJobsRepo.CancelImportJob(ctx, userUUID, jobUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.runningImports.cancel(job.UUID)
+	uc.jobEvents.publishImport(ctx, entity.JobEventCanceled, job, 0)
+
+	return job, nil
+}
+
+// QueueLibraryExport creates an export job and queues the export of the user's modules to a zip archive.
+// Only the selected modules are exported, the whole library is exported when none is selected.
+// Selected modules must exist, so a typo in a module UUID is reported before the job is created.
+func (uc *ModulesUseCase) QueueLibraryExport(
+	ctx context.Context,
+	userUUID string,
+	format string,
+	moduleUUIDs []string,
+) (*entity.ExportJob, error) {
+	modules, err := uc.modulesRepo.GetLibraryModules(ctx, userUUID, moduleUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, moduleUUID := range moduleUUIDs {
+		if !slices.ContainsFunc(modules, func(module *entity.Module) bool { return module.UUID == moduleUUID }) {
+			return nil, &entity.ModuleNotFoundError{UUID: moduleUUID}
+		}
+	}
+
+	if len(modules) == 0 {
+		return nil, entity.ErrNoModulesToExport
+	}
+
+	job, err := uc.exportJobsRepo.CreateExportJob(ctx, &entity.ExportJob{
+		UserUUID:    userUUID,
+		Format:      format,
+		ModuleUUIDs: moduleUUIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workJob := *job
+
+	exportWork := &LibraryExportWork{
+		modulesRepo: uc.modulesRepo,
+		cardsRepo:   uc.cardsRepo,
+		reviewRepo:  uc.reviewRepo,
+		jobsRepo:    uc.exportJobsRepo,
+		jobEvents:   uc.jobEvents,
+		log:         uc.log,
+		job:         &workJob,
+	}
+
+	uc.jobEvents.publishExport(ctx, entity.JobEventQueued, job, 0)
+
+	err = uc.libraryExportWP.QueueWork(exportWork)
+	if err != nil {
+		failExportJob(ctx, uc.exportJobsRepo, uc.jobEvents, uc.log, job, err)
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (uc *ModulesUseCase) GetExportJob(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) (*entity.ExportJob, error) {
+	return uc.exportJobsRepo.GetExportJob(ctx, userUUID, jobUUID)
+}
+
+// GetExportArchive returns the job's zip archive. ErrExportNotReady is returned while the job isn't processed
+// and ErrExportArchiveExpired after the archive has expired.
+func (uc *ModulesUseCase) GetExportArchive(
+	ctx context.Context,
+	userUUID string,
+	jobUUID string,
+) (*entity.ExportJob, []byte, error) {
+	job, err := uc.exportJobsRepo.GetExportJob(ctx, userUUID, jobUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status != entity.ExportStatusProcessed {
+		return nil, nil, entity.ErrExportNotReady
+	}
+
+	if job.ExpiresAt == nil || !time.Now().Before(*job.ExpiresAt) {
+		return nil, nil, entity.ErrExportArchiveExpired
+	}
+
+	archive, err := uc.exportJobsRepo.GetExportArchive(ctx, userUUID, jobUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return job, archive, nil
+}
+
+// createImportJob returns the stored module for imports into an existing module, "append" is the default strategy.
+// The strategy is ignored for imports into a new module.
+func (uc *ModulesUseCase) createImportJob(
+	ctx context.Context,
+	module *entity.Module,
+	source string,
+	strategy string,
+) (*entity.Module, *entity.ImportJob, error) {
+	if module.UUID == "" {
+		strategy = ""
+	} else {
+		storedModule, err := uc.modulesRepo.GetModule(ctx, module.UserUUID, module.UUID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		module = storedModule
+
+		if strategy == "" {
+			strategy = entity.ImportStrategyAppend
+		}
+	}
+
+	job, err := uc.importJobsRepo.CreateImportJob(ctx, &entity.ImportJob{
+		UserUUID:   module.UserUUID,
+		Source:     source,
+		ModuleName: module.Name,
+		ModuleUUID: module.UUID,
+		Strategy:   strategy,
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return uc.csvImportWP.QueueWork(importWork)
+	return module, job, nil
 }
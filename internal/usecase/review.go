@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/pkg/srs"
+)
+
+type ReviewUseCase struct {
+	repo ReviewRepository
+}
+
+func NewReviewUseCase(repo ReviewRepository) *ReviewUseCase {
+	return &ReviewUseCase{
+		repo: repo,
+	}
+}
+
+func (uc *ReviewUseCase) GetDueCards(ctx context.Context, userUUID string, limit int) ([]*entity.DueCard, error) {
+	return uc.repo.GetDueCards(ctx, userUUID, "", time.Now(), limit)
+}
+
+func (uc *ReviewUseCase) GetModuleDueCards(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	limit int,
+) ([]*entity.DueCard, error) {
+	return uc.repo.GetDueCards(ctx, userUUID, moduleUUID, time.Now(), limit)
+}
+
+// NewScheduler returns a scheduler implementing the algorithm, SM-2 is used for unknown ones.
+func NewScheduler(algorithm string, desiredRetention float64) Scheduler {
+	if algorithm == entity.SchedulerFSRS {
+		return srs.NewFSRS(desiredRetention)
+	}
+
+	return srs.NewSM2()
+}
+
+func toSRSState(state *entity.ReviewState) srs.State {
+	return srs.State{
+		EaseFactor:  state.EaseFactor,
+		Stability:   state.Stability,
+		Difficulty:  state.Difficulty,
+		Interval:    state.IntervalDays,
+		Repetitions: state.Repetitions,
+		Lapses:      state.Lapses,
+		Due:         state.DueAt,
+		LastReview:  state.LastReviewedAt,
+	}
+}
+
+func applySRSState(state *entity.ReviewState, srsState srs.State) {
+	state.EaseFactor = srsState.EaseFactor
+	state.Stability = srsState.Stability
+	state.Difficulty = srsState.Difficulty
+	state.IntervalDays = srsState.Interval
+	state.Repetitions = srsState.Repetitions
+	state.Lapses = srsState.Lapses
+	state.DueAt = srsState.Due
+	state.LastReviewedAt = srsState.LastReview
+}
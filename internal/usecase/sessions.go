@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type SessionsUseCase struct {
+	repo SessionsRepository
+}
+
+func NewSessionsUseCase(repo SessionsRepository) *SessionsUseCase {
+	return &SessionsUseCase{
+		repo: repo,
+	}
+}
+
+func (uc *SessionsUseCase) StartSession(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) (*entity.StudySession, error) {
+	return uc.repo.CreateSession(ctx, userUUID, moduleUUID)
+}
+
+func (uc *SessionsUseCase) FinishSession(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	sessionUUID string,
+) (*entity.StudySession, error) {
+	session, err := uc.repo.FinishSession(ctx, userUUID, moduleUUID, sessionUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	completeSummary(session)
+
+	return session, nil
+}
+
+func (uc *SessionsUseCase) GetModuleSessions(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) ([]*entity.StudySession, error) {
+	sessions, err := uc.repo.GetModuleSessions(ctx, userUUID, moduleUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		completeSummary(session)
+	}
+
+	return sessions, nil
+}
+
+// completeSummary fills summary fields which are derived from stored ones.
+func completeSummary(session *entity.StudySession) {
+	if session.Summary == nil || session.FinishedAt == nil {
+		return
+	}
+
+	if session.Summary.AnswersCount > 0 {
+		session.Summary.Accuracy = float64(session.Summary.CorrectCount) / float64(session.Summary.AnswersCount)
+	}
+
+	session.Summary.TimeSpentMs = session.FinishedAt.Sub(session.StartedAt).Milliseconds()
+}
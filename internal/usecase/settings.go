@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/llravell/simple-cards/internal/entity"
+)
+
+type SettingsUseCase struct {
+	repo SettingsRepository
+}
+
+func NewSettingsUseCase(repo SettingsRepository) *SettingsUseCase {
+	return &SettingsUseCase{
+		repo: repo,
+	}
+}
+
+func (uc *SettingsUseCase) GetUserSettings(ctx context.Context, userUUID string) (*entity.UserSettings, error) {
+	return uc.repo.GetUserSettings(ctx, userUUID)
+}
+
+func (uc *SettingsUseCase) SaveUserSettings(
+	ctx context.Context,
+	userUUID string,
+	settings *entity.UserSettings,
+) (*entity.UserSettings, error) {
+	return uc.repo.SaveUserSettings(ctx, userUUID, settings)
+}
+
+func (uc *SettingsUseCase) GetModuleSettings(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+) (*entity.ModuleSettings, error) {
+	return uc.repo.GetModuleSettings(ctx, userUUID, moduleUUID)
+}
+
+func (uc *SettingsUseCase) SaveModuleSettings(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	settings *entity.ModuleSettings,
+) (*entity.ModuleSettings, error) {
+	return uc.repo.SaveModuleSettings(ctx, userUUID, moduleUUID, settings)
+}
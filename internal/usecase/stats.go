@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/llravell/simple-cards/internal/entity"
+	"github.com/llravell/simple-cards/pkg/srs"
+)
+
+type StatsUseCase struct {
+	repo         StatsRepository
+	reviewRepo   ReviewRepository
+	settingsRepo SettingsRepository
+}
+
+func NewStatsUseCase(
+	repo StatsRepository,
+	reviewRepo ReviewRepository,
+	settingsRepo SettingsRepository,
+) *StatsUseCase {
+	return &StatsUseCase{
+		repo:         repo,
+		reviewRepo:   reviewRepo,
+		settingsRepo: settingsRepo,
+	}
+}
+
+func (uc *StatsUseCase) SaveAnswers(ctx context.Context, userUUID string, answers []*entity.Answer) error {
+	for _, answer := range answers {
+		answer.UserUUID = userUUID
+	}
+
+	states, err := uc.rescheduleCards(ctx, userUUID, answers, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.repo.SaveAnswers(ctx, userUUID, answers, states)
+
+	return err
+}
+
+// SyncAnswers saves answers uploaded by an offline client.
+// Answers with already known client IDs are skipped, so a batch can be safely retried,
+// even concurrently with the same batch.
+// Answers older than the last review of their cards rebuild review states from the whole history.
+func (uc *StatsUseCase) SyncAnswers(
+	ctx context.Context,
+	userUUID string,
+	answers []*entity.Answer,
+) (*entity.SyncResult, error) {
+	result := &entity.SyncResult{
+		Accepted:   make([]*entity.Answer, 0, len(answers)),
+		Duplicates: make([]string, 0),
+	}
+
+	uniqueAnswers := make([]*entity.Answer, 0, len(answers))
+	clientIDs := make([]string, 0, len(answers))
+
+	for _, answer := range answers {
+		answer.UserUUID = userUUID
+
+		if slices.Contains(clientIDs, answer.ClientID) {
+			result.Duplicates = append(result.Duplicates, answer.ClientID)
+
+			continue
+		}
+
+		uniqueAnswers = append(uniqueAnswers, answer)
+		clientIDs = append(clientIDs, answer.ClientID)
+	}
+
+	storedAnswers, err := uc.repo.GetAnswersByClientIDs(ctx, userUUID, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, answer := range uniqueAnswers {
+		if _, ok := storedAnswers[answer.ClientID]; ok {
+			result.Duplicates = append(result.Duplicates, answer.ClientID)
+		} else {
+			result.Accepted = append(result.Accepted, answer)
+		}
+	}
+
+	if len(result.Accepted) == 0 {
+		return result, nil
+	}
+
+	states, err := uc.rescheduleCards(ctx, userUUID, result.Accepted, true)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates, err := uc.repo.SaveAnswers(ctx, userUUID, result.Accepted, states)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(duplicates) > 0 {
+		result.Accepted = slices.DeleteFunc(result.Accepted, func(answer *entity.Answer) bool {
+			return slices.Contains(duplicates, answer.ClientID)
+		})
+		result.Duplicates = append(result.Duplicates, duplicates...)
+	}
+
+	return result, nil
+}
+
+// rescheduleCards applies answers to review states of answered cards in chronological order
+// and remembers card intervals before and after each answer.
+// A card becomes a leech when it is failed after reaching the user's lapses threshold.
+// With replayOutdated, states of cards answered before their last review are rebuilt
+// by replaying the stored answers together with the new ones.
+func (uc *StatsUseCase) rescheduleCards(
+	ctx context.Context,
+	userUUID string,
+	answers []*entity.Answer,
+	replayOutdated bool,
+) ([]*entity.ReviewState, error) {
+	sortedAnswers := slices.Clone(answers)
+	slices.SortStableFunc(sortedAnswers, compareAnswersTime)
+
+	cardUUIDs := make([]string, 0, len(sortedAnswers))
+	moduleUUIDs := make([]string, 0)
+
+	for _, answer := range sortedAnswers {
+		if !slices.Contains(cardUUIDs, answer.CardUUID) {
+			cardUUIDs = append(cardUUIDs, answer.CardUUID)
+		}
+
+		if !slices.Contains(moduleUUIDs, answer.ModuleUUID) {
+			moduleUUIDs = append(moduleUUIDs, answer.ModuleUUID)
+		}
+	}
+
+	settings, err := uc.settingsRepo.GetUserSettings(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedulers, err := uc.moduleSchedulers(ctx, userUUID, settings, moduleUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := uc.reviewRepo.GetReviewStates(ctx, userUUID, cardUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if replayOutdated {
+		sortedAnswers, err = uc.withOutdatedHistory(ctx, userUUID, states, sortedAnswers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, answer := range sortedAnswers {
+		state, ok := states[answer.CardUUID]
+		if !ok {
+			state = &entity.ReviewState{
+				CardUUID:   answer.CardUUID,
+				ModuleUUID: answer.ModuleUUID,
+			}
+			states[answer.CardUUID] = state
+		}
+
+		answer.IntervalBefore = state.IntervalDays
+
+		scheduler := schedulers[answer.ModuleUUID]
+		applySRSState(state, scheduler.Review(toSRSState(state), srs.Grade(answer.Grade), answer.AnsweredAt))
+
+		answer.IntervalAfter = state.IntervalDays
+
+		if answer.Grade < entity.PassingAnswerGrade && state.Lapses >= settings.LeechThreshold {
+			state.IsLeech = true
+		}
+	}
+
+	updatedStates := make([]*entity.ReviewState, 0, len(cardUUIDs))
+
+	for _, cardUUID := range cardUUIDs {
+		updatedStates = append(updatedStates, states[cardUUID])
+	}
+
+	return updatedStates, nil
+}
+
+// withOutdatedHistory resets states of cards which get answers older than their last review
+// and merges the stored answers of these cards into the sorted answers to replay them from scratch.
+func (uc *StatsUseCase) withOutdatedHistory(
+	ctx context.Context,
+	userUUID string,
+	states map[string]*entity.ReviewState,
+	sortedAnswers []*entity.Answer,
+) ([]*entity.Answer, error) {
+	outdatedCardUUIDs := make([]string, 0)
+
+	for _, answer := range sortedAnswers {
+		state, ok := states[answer.CardUUID]
+		if ok && answer.AnsweredAt.Before(state.LastReviewedAt) && !slices.Contains(outdatedCardUUIDs, answer.CardUUID) {
+			outdatedCardUUIDs = append(outdatedCardUUIDs, answer.CardUUID)
+		}
+	}
+
+	if len(outdatedCardUUIDs) == 0 {
+		return sortedAnswers, nil
+	}
+
+	history, err := uc.repo.GetCardsAnswers(ctx, userUUID, outdatedCardUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cardUUID := range outdatedCardUUIDs {
+		state := states[cardUUID]
+
+		states[cardUUID] = &entity.ReviewState{
+			CardUUID:    state.CardUUID,
+			ModuleUUID:  state.ModuleUUID,
+			IsLeech:     state.IsLeech,
+			IsSuspended: state.IsSuspended,
+		}
+	}
+
+	replayedAnswers := slices.Concat(history, sortedAnswers)
+	slices.SortStableFunc(replayedAnswers, compareAnswersTime)
+
+	return replayedAnswers, nil
+}
+
+func compareAnswersTime(a, b *entity.Answer) int {
+	return a.AnsweredAt.Compare(b.AnsweredAt)
+}
+
+// moduleSchedulers resolves schedulers of modules, module settings take precedence over user settings.
+func (uc *StatsUseCase) moduleSchedulers(
+	ctx context.Context,
+	userUUID string,
+	settings *entity.UserSettings,
+	moduleUUIDs []string,
+) (map[string]Scheduler, error) {
+	overriddenAlgorithms, err := uc.settingsRepo.GetModuleSchedulers(ctx, userUUID, moduleUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	schedulers := make(map[string]Scheduler, len(moduleUUIDs))
+
+	for _, moduleUUID := range moduleUUIDs {
+		algorithm, ok := overriddenAlgorithms[moduleUUID]
+		if !ok {
+			algorithm = settings.Scheduler
+		}
+
+		schedulers[moduleUUID] = NewScheduler(algorithm, settings.DesiredRetention)
+	}
+
+	return schedulers, nil
+}
+
+func (uc *StatsUseCase) GetModuleStats(
+	ctx context.Context,
+	userUUID string,
+	moduleUUID string,
+	from time.Time,
+	to time.Time,
+) ([]*entity.CardStats, error) {
+	stats, err := uc.repo.GetModuleStats(ctx, userUUID, moduleUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cardStats := range stats {
+		answersAmount := cardStats.CorrectCount + cardStats.IncorrectCount
+
+		if answersAmount > 0 {
+			cardStats.Accuracy = float64(cardStats.CorrectCount) / float64(answersAmount)
+		}
+	}
+
+	return stats, nil
+}
+
+// GetActivity returns user's daily activity between the from and to dates inclusive.
+// Empty timezone means the user's one, zero dates mean the last year.
+func (uc *StatsUseCase) GetActivity(
+	ctx context.Context,
+	userUUID string,
+	timezone string,
+	from time.Time,
+	to time.Time,
+) ([]*entity.DailyActivity, error) {
+	if timezone == "" {
+		settings, err := uc.settingsRepo.GetUserSettings(ctx, userUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		timezone = settings.Timezone
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if to.IsZero() {
+		to = time.Now().In(location)
+	}
+
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 1)
+	}
+
+	periodStart := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	periodEnd := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, location)
+
+	return uc.repo.GetDailyActivity(ctx, userUUID, timezone, periodStart, periodEnd)
+}
+
+// GetStreak counts consecutive days with answers in the user's time zone.
+// The current streak is kept until the end of the day after the last study day.
+func (uc *StatsUseCase) GetStreak(ctx context.Context, userUUID string) (*entity.StudyStreak, error) {
+	settings, err := uc.settingsRepo.GetUserSettings(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(location)
+
+	activity, err := uc.repo.GetDailyActivity(ctx, userUUID, settings.Timezone, time.Time{}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	streak := &entity.StudyStreak{
+		Timezone:       settings.Timezone,
+		DailyGoalType:  settings.DailyGoalType,
+		DailyGoalValue: settings.DailyGoalValue,
+	}
+
+	today := now.Format(time.DateOnly)
+	yesterday := now.AddDate(0, 0, -1).Format(time.DateOnly)
+
+	var (
+		previousDate time.Time
+		lastDay      string
+		run          int
+	)
+
+	for _, day := range activity {
+		date, parseErr := time.Parse(time.DateOnly, day.Date)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+
+		if run > 0 && date.Equal(previousDate.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+
+		previousDate = date
+		lastDay = day.Date
+		streak.LongestStreak = max(streak.LongestStreak, run)
+
+		if day.Date == today {
+			streak.TodayProgress = dailyGoalProgress(settings.DailyGoalType, day)
+		}
+	}
+
+	if lastDay == today || lastDay == yesterday {
+		streak.CurrentStreak = run
+	}
+
+	streak.GoalReached = streak.TodayProgress >= streak.DailyGoalValue
+
+	return streak, nil
+}
+
+func dailyGoalProgress(goalType string, day *entity.DailyActivity) int {
+	if goalType == entity.DailyGoalMinutes {
+		return int(day.TimeSpentMs / time.Minute.Milliseconds())
+	}
+
+	return day.AnswersCount
+}
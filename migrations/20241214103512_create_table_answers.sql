@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE answers (
+  uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_uuid UUID NOT NULL,
+  card_uuid UUID NOT NULL,
+  module_uuid UUID NOT NULL,
+  is_correct BOOLEAN NOT NULL,
+  response_time_ms INTEGER NOT NULL DEFAULT 0,
+  answered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid),
+  CONSTRAINT fk_card FOREIGN KEY(card_uuid) REFERENCES cards(uuid) ON DELETE CASCADE,
+  CONSTRAINT fk_module FOREIGN KEY(module_uuid) REFERENCES modules(uuid) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX answers_user_module_idx ON answers (user_uuid, module_uuid, answered_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE answers;
+-- +goose StatementEnd
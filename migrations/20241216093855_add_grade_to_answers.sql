@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE answers ADD COLUMN grade SMALLINT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE answers SET grade = CASE WHEN is_correct THEN 4 ELSE 1 END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE answers ALTER COLUMN grade SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE answers DROP COLUMN grade;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE review_states (
+  user_uuid UUID NOT NULL,
+  card_uuid UUID NOT NULL,
+  module_uuid UUID NOT NULL,
+  ease_factor DOUBLE PRECISION NOT NULL,
+  interval_days INTEGER NOT NULL,
+  repetitions INTEGER NOT NULL,
+  lapses INTEGER NOT NULL DEFAULT 0,
+  due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  last_reviewed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_uuid, card_uuid),
+  CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid),
+  CONSTRAINT fk_card FOREIGN KEY(card_uuid) REFERENCES cards(uuid) ON DELETE CASCADE,
+  CONSTRAINT fk_module FOREIGN KEY(module_uuid) REFERENCES modules(uuid) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX review_states_user_due_idx ON review_states (user_uuid, due_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE review_states;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_settings (
+  user_uuid UUID PRIMARY KEY,
+  scheduler VARCHAR(16) NOT NULL DEFAULT 'sm2',
+  desired_retention DOUBLE PRECISION NOT NULL DEFAULT 0.9,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE modules ADD COLUMN scheduler VARCHAR(16);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE modules DROP COLUMN scheduler;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE user_settings;
+-- +goose StatementEnd
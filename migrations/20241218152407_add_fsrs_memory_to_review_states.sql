@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE review_states
+  ADD COLUMN stability DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ADD COLUMN difficulty DOUBLE PRECISION NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE review_states
+  DROP COLUMN stability,
+  DROP COLUMN difficulty;
+-- +goose StatementEnd
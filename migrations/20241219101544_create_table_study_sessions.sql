@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE study_sessions (
+  uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_uuid UUID NOT NULL,
+  module_uuid UUID NOT NULL,
+  started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_at TIMESTAMP WITH TIME ZONE,
+  cards_seen INTEGER,
+  answers_count INTEGER,
+  correct_count INTEGER,
+  promoted_count INTEGER,
+  demoted_count INTEGER,
+  CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid),
+  CONSTRAINT fk_module FOREIGN KEY(module_uuid) REFERENCES modules(uuid) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX study_sessions_user_module_idx ON study_sessions (user_uuid, module_uuid, started_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE answers
+  ADD COLUMN session_uuid UUID REFERENCES study_sessions(uuid) ON DELETE SET NULL,
+  ADD COLUMN interval_before INTEGER,
+  ADD COLUMN interval_after INTEGER;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX answers_session_idx ON answers (session_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE answers
+  DROP COLUMN session_uuid,
+  DROP COLUMN interval_before,
+  DROP COLUMN interval_after;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE study_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_settings
+  ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+  ADD COLUMN daily_goal_type VARCHAR(16) NOT NULL DEFAULT 'cards',
+  ADD COLUMN daily_goal_value INTEGER NOT NULL DEFAULT 20;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_settings
+  DROP COLUMN timezone,
+  DROP COLUMN daily_goal_type,
+  DROP COLUMN daily_goal_value;
+-- +goose StatementEnd
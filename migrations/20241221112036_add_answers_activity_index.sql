@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX answers_user_answered_at_idx ON answers (user_uuid, answered_at) INCLUDE (is_correct, response_time_ms);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX answers_user_answered_at_idx;
+-- +goose StatementEnd
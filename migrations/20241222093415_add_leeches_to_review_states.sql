@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE review_states
+  ADD COLUMN is_leech BOOLEAN NOT NULL DEFAULT false,
+  ADD COLUMN is_suspended BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX review_states_user_leech_idx ON review_states (user_uuid) WHERE is_leech;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE user_settings ADD COLUMN leech_threshold INTEGER NOT NULL DEFAULT 8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_settings DROP COLUMN leech_threshold;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE review_states
+  DROP COLUMN is_leech,
+  DROP COLUMN is_suspended;
+-- +goose StatementEnd
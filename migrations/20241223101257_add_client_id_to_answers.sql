@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE answers ADD COLUMN client_id TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX answers_user_client_id_idx ON answers (user_uuid, client_id) WHERE client_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX answers_user_card_idx ON answers (user_uuid, card_uuid, answered_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX answers_user_card_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE answers DROP COLUMN client_id;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE import_jobs (
+  uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_uuid UUID NOT NULL,
+  source TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'created',
+  module_name TEXT NOT NULL,
+  module_uuid UUID,
+  cards_count INTEGER NOT NULL DEFAULT 0,
+  error_message TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid),
+  CONSTRAINT fk_module FOREIGN KEY(module_uuid) REFERENCES modules(uuid) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX import_jobs_user_idx ON import_jobs (user_uuid, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE import_jobs;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE queue_jobs ADD COLUMN last_error TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX queue_jobs_dead_idx ON queue_jobs (queue, updated_at) WHERE status = 'dead';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX queue_jobs_dead_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE queue_jobs DROP COLUMN last_error;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE import_jobs
+  ADD COLUMN strategy TEXT,
+  ADD COLUMN updated_cards_count INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN skipped_cards_count INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE import_jobs
+  DROP COLUMN strategy,
+  DROP COLUMN updated_cards_count,
+  DROP COLUMN skipped_cards_count;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE import_job_errors (
+  id BIGSERIAL PRIMARY KEY,
+  job_uuid UUID NOT NULL,
+  line INTEGER NOT NULL,
+  reason TEXT NOT NULL,
+  raw TEXT NOT NULL,
+  CONSTRAINT fk_import_job FOREIGN KEY(job_uuid) REFERENCES import_jobs(uuid) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX import_job_errors_job_idx ON import_job_errors (job_uuid, line);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE import_jobs ADD COLUMN errors_count INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE import_jobs DROP COLUMN errors_count;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE import_job_errors;
+-- +goose StatementEnd
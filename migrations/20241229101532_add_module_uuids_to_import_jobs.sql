@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE import_jobs
+  ADD COLUMN module_uuids UUID[];
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE import_jobs
+  DROP COLUMN module_uuids;
+-- +goose StatementEnd
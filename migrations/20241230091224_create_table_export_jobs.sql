@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE export_jobs (
+  uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_uuid UUID NOT NULL,
+  format TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'created',
+  module_uuids UUID[],
+  modules_count INTEGER NOT NULL DEFAULT 0,
+  cards_count INTEGER NOT NULL DEFAULT 0,
+  archive BYTEA,
+  archive_size BIGINT NOT NULL DEFAULT 0,
+  error_message TEXT,
+  expires_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX export_jobs_user_idx ON export_jobs (user_uuid, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX export_jobs_expires_at_idx ON export_jobs (expires_at) WHERE archive IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE export_jobs;
+-- +goose StatementEnd
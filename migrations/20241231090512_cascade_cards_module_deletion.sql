@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cards
+  DROP CONSTRAINT fk_module,
+  ADD CONSTRAINT fk_module FOREIGN KEY(module_uuid) REFERENCES modules(uuid) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE cards
+  DROP CONSTRAINT fk_module,
+  ADD CONSTRAINT fk_module FOREIGN KEY(module_uuid) REFERENCES modules(uuid);
+-- +goose StatementEnd
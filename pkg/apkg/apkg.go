@@ -0,0 +1,354 @@
+// Package apkg reads and writes cards of Anki packages, zip archives of a SQLite collection and media files.
+//
+// Packages exported with the "Support older Anki versions" option contain collection.anki21 or collection.anki2.
+// Packages which only have the compressed collection.anki21b are not supported.
+// Note fields are converted to plain text: html tags, sounds and cloze markup are removed.
+// Written packages have the same legacy format, which every Anki version imports.
+package apkg
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/llravell/simple-cards/pkg/sqlitefile"
+)
+
+const (
+	collectionAnki21  = "collection.anki21"
+	collectionAnki2   = "collection.anki2"
+	collectionAnki21b = "collection.anki21b"
+
+	// MaxCollectionSize limits the unpacked collection, media files are not unpacked at all.
+	MaxCollectionSize = 64 << 20
+
+	defaultTermField    = "1"
+	defaultMeaningField = "2"
+
+	fieldSeparator = "\x1f"
+	rawSeparator   = " | "
+)
+
+var (
+	ErrNotPackage         = errors.New("not an anki package")
+	ErrUnsupportedPackage = errors.New(
+		"anki package format is not supported, export it with \"Support older Anki versions\" option",
+	)
+	ErrCollectionTooLarge = errors.New("anki collection is too large")
+)
+
+var (
+	clozePattern     = regexp.MustCompile(`{{c\d+::(.*?)(?:::.*?)?}}`)
+	soundPattern     = regexp.MustCompile(`\[sound:[^\]]*\]`)
+	lineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</div>|</p>|</li>`)
+	tagPattern       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// FieldMapping refers note fields by numbers starting from 1 or by names.
+// The zero FieldMapping takes terms from the first field and meanings from the second one.
+type FieldMapping struct {
+	TermField    string `json:"term_field,omitempty"`
+	MeaningField string `json:"meaning_field,omitempty"`
+}
+
+// Card is a note of term and meaning. ID and Schedule are only used by WritePackage.
+type Card struct {
+	ID       string
+	Term     string
+	Meaning  string
+	Schedule *Schedule
+}
+
+type Deck struct {
+	Name  string
+	Cards []Card
+}
+
+// NoteError describes a skipped note, Number is the note's position in the collection starting from 1
+// and Raw is its fields.
+type NoteError struct {
+	Number int
+	Reason string
+	Raw    string
+}
+
+func (e *NoteError) Error() string {
+	return fmt.Sprintf("note %d: %s", e.Number, e.Reason)
+}
+
+type noteType struct {
+	Name   string `json:"name"`
+	Fields []struct {
+		Name string `json:"name"`
+		Ord  int    `json:"ord"`
+	} `json:"flds"`
+}
+
+type deckInfo struct {
+	Name string `json:"name"`
+}
+
+// ExtractCollection reads the collection database from the package, media files are skipped.
+func ExtractCollection(r io.ReaderAt, size int64) ([]byte, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrNotPackage
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	collection, ok := files[collectionAnki21]
+	if !ok {
+		if _, ok = files[collectionAnki21b]; ok {
+			return nil, ErrUnsupportedPackage
+		}
+
+		collection, ok = files[collectionAnki2]
+	}
+
+	if !ok {
+		return nil, ErrNotPackage
+	}
+
+	if collection.UncompressedSize64 > MaxCollectionSize {
+		return nil, ErrCollectionTooLarge
+	}
+
+	reader, err := collection.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(io.LimitReader(reader, MaxCollectionSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > MaxCollectionSize {
+		return nil, ErrCollectionTooLarge
+	}
+
+	return content, nil
+}
+
+// ReadDecks reads cards of the collection grouped by decks, decks are sorted by name.
+// A note belongs to the deck of its first card. Notes without the mapped fields or with empty ones
+// are skipped with NoteError. Reading stops with the context error when the context is done.
+func ReadDecks(ctx context.Context, collection []byte, mapping FieldMapping) ([]*Deck, []*NoteError, error) {
+	db, err := sqlitefile.Open(collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	noteTypes, deckInfos, err := readCollectionInfo(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	noteDecks, err := readNoteDecks(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notes, err := db.Rows(ctx, "notes")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if mapping.TermField == "" {
+		mapping.TermField = defaultTermField
+	}
+
+	if mapping.MeaningField == "" {
+		mapping.MeaningField = defaultMeaningField
+	}
+
+	decks := make(map[int64]*Deck)
+	noteErrors := make([]*NoteError, 0)
+
+	for i, note := range notes {
+		fields := strings.Split(note.Text("flds"), fieldSeparator)
+
+		card, reason := noteCard(fields, noteTypes[strconv.FormatInt(note.Int("mid"), 10)], mapping)
+		if reason != "" {
+			noteErrors = append(noteErrors, &NoteError{
+				Number: i + 1,
+				Reason: reason,
+				Raw:    strings.Join(fields, rawSeparator),
+			})
+
+			continue
+		}
+
+		deckID, ok := noteDecks[note.Int("id")]
+		if !ok {
+			noteErrors = append(noteErrors, &NoteError{
+				Number: i + 1,
+				Reason: "note has no cards",
+				Raw:    strings.Join(fields, rawSeparator),
+			})
+
+			continue
+		}
+
+		deck, ok := decks[deckID]
+		if !ok {
+			deck = &Deck{Name: deckName(deckInfos, deckID)}
+			decks[deckID] = deck
+		}
+
+		deck.Cards = append(deck.Cards, card)
+	}
+
+	sortedDecks := make([]*Deck, 0, len(decks))
+
+	for _, deck := range decks {
+		sortedDecks = append(sortedDecks, deck)
+	}
+
+	slices.SortFunc(sortedDecks, func(a, b *Deck) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return sortedDecks, noteErrors, nil
+}
+
+// readCollectionInfo reads note types and decks, they are stored as json in the only row of col table.
+func readCollectionInfo(ctx context.Context, db *sqlitefile.DB) (map[string]noteType, map[string]deckInfo, error) {
+	rows, err := db.Rows(ctx, "col")
+
+	var notFoundErr *sqlitefile.TableNotFoundError
+
+	if errors.As(err, &notFoundErr) {
+		return nil, nil, ErrUnsupportedPackage
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(rows) == 0 || rows[0].Text("models") == "" {
+		return nil, nil, ErrUnsupportedPackage
+	}
+
+	var (
+		noteTypes map[string]noteType
+		deckInfos map[string]deckInfo
+	)
+
+	if err = json.Unmarshal([]byte(rows[0].Text("models")), &noteTypes); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrUnsupportedPackage, err)
+	}
+
+	if err = json.Unmarshal([]byte(rows[0].Text("decks")), &deckInfos); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrUnsupportedPackage, err)
+	}
+
+	return noteTypes, deckInfos, nil
+}
+
+// readNoteDecks maps notes to decks of their first cards.
+func readNoteDecks(ctx context.Context, db *sqlitefile.DB) (map[int64]int64, error) {
+	cards, err := db.Rows(ctx, "cards")
+	if err != nil {
+		return nil, err
+	}
+
+	noteDecks := make(map[int64]int64, len(cards))
+	noteOrds := make(map[int64]int64, len(cards))
+
+	for _, card := range cards {
+		noteID := card.Int("nid")
+
+		if ord, ok := noteOrds[noteID]; ok && ord <= card.Int("ord") {
+			continue
+		}
+
+		noteDecks[noteID] = card.Int("did")
+		noteOrds[noteID] = card.Int("ord")
+	}
+
+	return noteDecks, nil
+}
+
+func deckName(deckInfos map[string]deckInfo, deckID int64) string {
+	if info, ok := deckInfos[strconv.FormatInt(deckID, 10)]; ok && info.Name != "" {
+		return info.Name
+	}
+
+	return "Deck " + strconv.FormatInt(deckID, 10)
+}
+
+// noteCard takes the mapped fields of the note, a reason is returned when the note can't be a card.
+func noteCard(fields []string, nt noteType, mapping FieldMapping) (Card, string) {
+	termIndex, ok := fieldIndex(nt, mapping.TermField)
+	if !ok || termIndex >= len(fields) {
+		return Card{}, fmt.Sprintf("field \"%s\" is missing", mapping.TermField)
+	}
+
+	meaningIndex, ok := fieldIndex(nt, mapping.MeaningField)
+	if !ok || meaningIndex >= len(fields) {
+		return Card{}, fmt.Sprintf("field \"%s\" is missing", mapping.MeaningField)
+	}
+
+	card := Card{
+		Term:    PlainText(fields[termIndex]),
+		Meaning: PlainText(fields[meaningIndex]),
+	}
+
+	if card.Term == "" || card.Meaning == "" {
+		return Card{}, "term or meaning is empty"
+	}
+
+	return card, ""
+}
+
+// fieldIndex resolves a field number or a field name of the note type.
+func fieldIndex(nt noteType, field string) (int, bool) {
+	number, err := strconv.Atoi(field)
+	if err == nil && number >= 1 {
+		return number - 1, true
+	}
+
+	for _, ntField := range nt.Fields {
+		if strings.EqualFold(strings.TrimSpace(ntField.Name), strings.TrimSpace(field)) {
+			return ntField.Ord, true
+		}
+	}
+
+	return 0, false
+}
+
+// PlainText converts a note field to plain text, line breaks are kept.
+func PlainText(field string) string {
+	text := clozePattern.ReplaceAllString(field, "$1")
+	text = soundPattern.ReplaceAllString(text, "")
+	text = lineBreakPattern.ReplaceAllString(text, "\n")
+	text = tagPattern.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(html.UnescapeString(text), " ", " ")
+
+	lines := strings.Split(text, "\n")
+	plainLines := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			plainLines = append(plainLines, line)
+		}
+	}
+
+	return strings.Join(plainLines, "\n")
+}
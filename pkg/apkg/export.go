@@ -0,0 +1,501 @@
+package apkg
+
+import (
+	"archive/zip"
+	"crypto/sha1" //nolint:gosec
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/llravell/simple-cards/pkg/sqlitefile"
+)
+
+const (
+	collectionVersion   = 11
+	schedulerVersion    = 2
+	defaultDeckID       = 1
+	defaultDeckConfigID = 1
+
+	// Note type ids are fixed, so notes of several exported packages share their note types in Anki.
+	basicNoteTypeID         = 1700000000000
+	basicReversedNoteTypeID = 1700000000001
+
+	defaultEaseFactor = 2500
+	minEaseFactor     = 1300
+	learningLeft      = 1001
+
+	day = 24 * time.Hour
+
+	guidAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#$%&()*+,-./:;<=>?@[]^_`{|}~"
+
+	cardTemplateCSS = ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n" +
+		" color: black;\n background-color: white;\n}\n"
+	latexPre = "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n" +
+		"\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n"
+	latexPost = "\\end{document}"
+)
+
+// Anki card types and queues.
+const (
+	cardTypeNew      = 0
+	cardTypeLearning = 1
+	cardTypeReview   = 2
+
+	cardQueueSuspended = -1
+	cardQueueNew       = 0
+	cardQueueLearning  = 1
+	cardQueueReview    = 2
+)
+
+// Schema of the legacy collection, Anki upgrades it on import.
+var (
+	collectionTables = []*sqlitefile.Table{
+		{Name: "col", SQL: "CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, " +
+			"scm integer not null, ver integer not null, dty integer not null, usn integer not null, " +
+			"ls integer not null, conf text not null, models text not null, decks text not null, " +
+			"dconf text not null, tags text not null)"},
+		{Name: "notes", SQL: "CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, " +
+			"mod integer not null, usn integer not null, tags text not null, flds text not null, " +
+			"sfld integer not null, csum integer not null, flags integer not null, data text not null)"},
+		{Name: "cards", SQL: "CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, " +
+			"ord integer not null, mod integer not null, usn integer not null, type integer not null, " +
+			"queue integer not null, due integer not null, ivl integer not null, factor integer not null, " +
+			"reps integer not null, lapses integer not null, left integer not null, odue integer not null, " +
+			"odid integer not null, flags integer not null, data text not null)"},
+		{Name: "revlog", SQL: "CREATE TABLE revlog (id integer primary key, cid integer not null, " +
+			"usn integer not null, ease integer not null, ivl integer not null, lastIvl integer not null, " +
+			"factor integer not null, time integer not null, type integer not null)"},
+		{Name: "graves", SQL: "CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null)"},
+	}
+	collectionIndexes = []*sqlitefile.Index{
+		{Name: "ix_notes_usn", Table: "notes", SQL: "CREATE INDEX ix_notes_usn on notes (usn)"},
+		{Name: "ix_cards_usn", Table: "cards", SQL: "CREATE INDEX ix_cards_usn on cards (usn)"},
+		{Name: "ix_revlog_usn", Table: "revlog", SQL: "CREATE INDEX ix_revlog_usn on revlog (usn)"},
+		{Name: "ix_cards_nid", Table: "cards", SQL: "CREATE INDEX ix_cards_nid on cards (nid)"},
+		{Name: "ix_cards_sched", Table: "cards", SQL: "CREATE INDEX ix_cards_sched on cards (did, queue, due)"},
+		{Name: "ix_revlog_cid", Table: "revlog", SQL: "CREATE INDEX ix_revlog_cid on revlog (cid)"},
+		{Name: "ix_notes_csum", Table: "notes", SQL: "CREATE INDEX ix_notes_csum on notes (csum)"},
+	}
+)
+
+// Schedule is a review state of a card, cards without it are new.
+// Stability and difficulty are FSRS memory state, they are exported when stability is known.
+type Schedule struct {
+	DueAt        time.Time
+	IntervalDays int
+	EaseFactor   float64
+	Repetitions  int
+	Lapses       int
+	Stability    float64
+	Difficulty   float64
+	Suspended    bool
+}
+
+type ExportOptions struct {
+	// Reversed adds a meaning to term card to every note, reversed cards are new.
+	Reversed bool
+	// Now is the export time, it's the current time by default.
+	Now time.Time
+}
+
+type exportTemplate struct {
+	Name  string `json:"name"`
+	Ord   int    `json:"ord"`
+	QFmt  string `json:"qfmt"`
+	AFmt  string `json:"afmt"`
+	BQFmt string `json:"bqfmt"`
+	BAFmt string `json:"bafmt"`
+	DID   *int64 `json:"did"`
+	BFont string `json:"bfont"`
+	BSize int    `json:"bsize"`
+}
+
+type exportField struct {
+	Name   string   `json:"name"`
+	Ord    int      `json:"ord"`
+	Sticky bool     `json:"sticky"`
+	RTL    bool     `json:"rtl"`
+	Font   string   `json:"font"`
+	Size   int      `json:"size"`
+	Media  []string `json:"media"`
+}
+
+// WritePackage writes the decks as a package of the legacy format, every deck keeps its name.
+// Cards are "Basic" notes with "Front" and "Back" fields, or "Basic (and reversed card)" notes.
+func WritePackage(w io.Writer, decks []*Deck, opts ExportOptions) error {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	collection, err := buildCollection(decks, opts)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+
+	file, err := archive.Create(collectionAnki21)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(collection); err != nil {
+		return err
+	}
+
+	// media maps numbered media files of the package to their names, exported cards have no media
+	media, err := archive.Create("media")
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(media, "{}"); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+//nolint:funlen
+func buildCollection(decks []*Deck, opts ExportOptions) ([]byte, error) {
+	now := opts.Now.UTC()
+	nowMillis := now.UnixMilli()
+	createdAt := collectionCreatedAt(decks, now)
+	today := int64(now.Sub(createdAt) / day)
+
+	noteTypeID := int64(basicNoteTypeID)
+	if opts.Reversed {
+		noteTypeID = basicReversedNoteTypeID
+	}
+
+	deckInfos := map[string]any{
+		strconv.Itoa(defaultDeckID): exportDeckInfo(defaultDeckID, "Default", now),
+	}
+	notes := make([][]any, 0)
+	cards := make([][]any, 0)
+	position, cardsCount := int64(0), int64(0)
+
+	for i, deck := range decks {
+		// ids are milliseconds like the ones Anki makes, they only have to be unique in the package
+		deckID := nowMillis + int64(i)
+		deckInfos[strconv.FormatInt(deckID, 10)] = exportDeckInfo(deckID, deck.Name, now)
+
+		for _, card := range deck.Cards {
+			position++
+
+			noteID := nowMillis + position
+			sortField := card.Term
+
+			notes = append(notes, []any{
+				noteID,
+				noteGUID(deck.Name, card),
+				noteTypeID,
+				now.Unix(),
+				-1,
+				"",
+				htmlField(card.Term) + fieldSeparator + htmlField(card.Meaning),
+				sortField,
+				fieldChecksum(sortField),
+				0,
+				"",
+			})
+
+			cardsCount++
+			cards = append(cards, exportCard(
+				nowMillis+cardsCount, noteID, deckID, 0, position, card.Schedule, createdAt, today, now,
+			))
+
+			if opts.Reversed {
+				cardsCount++
+				cards = append(cards, exportCard(nowMillis+cardsCount, noteID, deckID, 1, position, nil, createdAt, today, now))
+			}
+		}
+	}
+
+	firstDeckID := int64(defaultDeckID)
+	if len(decks) > 0 {
+		firstDeckID = nowMillis
+	}
+
+	models, err := json.Marshal(map[string]any{
+		strconv.FormatInt(noteTypeID, 10): exportNoteType(noteTypeID, firstDeckID, opts.Reversed, now),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decksJSON, err := json.Marshal(deckInfos)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := json.Marshal(map[string]any{
+		"activeDecks":   []int64{firstDeckID},
+		"curDeck":       firstDeckID,
+		"curModel":      noteTypeID,
+		"nextPos":       position + 1,
+		"newSpread":     0,
+		"collapseTime":  1200, //nolint:mnd
+		"timeLim":       0,
+		"estTimes":      true,
+		"dueCounts":     true,
+		"sortType":      "noteFld",
+		"sortBackwards": false,
+		"addToCur":      true,
+		"schedVer":      schedulerVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deckConfigs, err := json.Marshal(map[string]any{
+		strconv.Itoa(defaultDeckConfigID): exportDeckConfig(now),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*sqlitefile.Table, 0, len(collectionTables))
+
+	for _, table := range collectionTables {
+		filled := *table
+
+		switch table.Name {
+		case "col":
+			filled.Rows = [][]any{{
+				1, createdAt.Unix(), nowMillis, nowMillis, collectionVersion, 0, 0, 0,
+				string(conf), string(models), string(decksJSON), string(deckConfigs), "{}",
+			}}
+		case "notes":
+			filled.Rows = notes
+		case "cards":
+			filled.Rows = cards
+		}
+
+		tables = append(tables, &filled)
+	}
+
+	return sqlitefile.Write(tables, collectionIndexes)
+}
+
+// collectionCreatedAt is the day when the collection has been created, due days of review cards count from it.
+// It's set before every due date, so all of them are positive.
+func collectionCreatedAt(decks []*Deck, now time.Time) time.Time {
+	createdAt := now
+
+	for _, deck := range decks {
+		for _, card := range deck.Cards {
+			if card.Schedule != nil && !card.Schedule.DueAt.IsZero() && card.Schedule.DueAt.Before(createdAt) {
+				createdAt = card.Schedule.DueAt.UTC()
+			}
+		}
+	}
+
+	return createdAt.Truncate(day)
+}
+
+// exportCard maps the schedule to Anki's review data: reviewed cards with an interval of a day
+// or more are review cards, other reviewed cards are learning ones.
+//
+//nolint:mnd
+func exportCard(
+	id int64,
+	noteID int64,
+	deckID int64,
+	ord int,
+	position int64,
+	schedule *Schedule,
+	createdAt time.Time,
+	today int64,
+	now time.Time,
+) []any {
+	cardType, queue, due := cardTypeNew, cardQueueNew, position
+	interval, factor, reps, lapses, left, data := 0, 0, 0, 0, 0, "{}"
+
+	if schedule != nil && schedule.Repetitions > 0 {
+		interval, reps, lapses = schedule.IntervalDays, schedule.Repetitions, schedule.Lapses
+
+		factor = int(math.Round(schedule.EaseFactor * 1000))
+		if factor <= 0 {
+			factor = defaultEaseFactor
+		}
+
+		factor = max(factor, minEaseFactor)
+
+		switch {
+		case schedule.IntervalDays >= 1 && !schedule.DueAt.IsZero():
+			cardType, queue = cardTypeReview, cardQueueReview
+			due = int64(schedule.DueAt.UTC().Sub(createdAt) / day)
+		case schedule.IntervalDays >= 1:
+			cardType, queue, due = cardTypeReview, cardQueueReview, today
+		default:
+			cardType, queue, left = cardTypeLearning, cardQueueLearning, learningLeft
+			due = now.Unix()
+
+			if !schedule.DueAt.IsZero() {
+				due = schedule.DueAt.Unix()
+			}
+		}
+
+		if schedule.Stability > 0 {
+			memoryState, _ := json.Marshal(map[string]float64{
+				"s": math.Round(schedule.Stability*10000) / 10000,
+				"d": math.Round(schedule.Difficulty*10000) / 10000,
+			})
+			data = string(memoryState)
+		}
+	}
+
+	if schedule != nil && schedule.Suspended {
+		queue = cardQueueSuspended
+	}
+
+	return []any{
+		id, noteID, deckID, ord, now.Unix(), -1, cardType, queue, due,
+		interval, factor, reps, lapses, left, 0, 0, 0, data,
+	}
+}
+
+func exportDeckInfo(id int64, name string, now time.Time) map[string]any {
+	return map[string]any{
+		"id":               id,
+		"name":             name,
+		"mod":              now.Unix(),
+		"usn":              -1,
+		"lrnToday":         []int{0, 0},
+		"revToday":         []int{0, 0},
+		"newToday":         []int{0, 0},
+		"timeToday":        []int{0, 0},
+		"collapsed":        false,
+		"browserCollapsed": false,
+		"desc":             "",
+		"dyn":              0,
+		"conf":             defaultDeckConfigID,
+		"extendNew":        0,
+		"extendRev":        0,
+	}
+}
+
+//nolint:mnd
+func exportDeckConfig(now time.Time) map[string]any {
+	return map[string]any{
+		"id":       defaultDeckConfigID,
+		"name":     "Default",
+		"mod":      now.Unix(),
+		"usn":      -1,
+		"maxTaken": 60,
+		"autoplay": true,
+		"timer":    0,
+		"replayq":  true,
+		"dyn":      false,
+		"new": map[string]any{
+			"bury":          false,
+			"delays":        []float64{1, 10},
+			"initialFactor": defaultEaseFactor,
+			"ints":          []int{1, 4, 0},
+			"order":         1,
+			"perDay":        20,
+		},
+		"lapse": map[string]any{
+			"delays":      []float64{10},
+			"leechAction": 1,
+			"leechFails":  8,
+			"minInt":      1,
+			"mult":        0,
+		},
+		"rev": map[string]any{
+			"bury":       false,
+			"ease4":      1.3,
+			"ivlFct":     1,
+			"maxIvl":     36500,
+			"perDay":     200,
+			"hardFactor": 1.2,
+		},
+	}
+}
+
+//nolint:mnd
+func exportNoteType(id int64, deckID int64, reversed bool, now time.Time) map[string]any {
+	name := "Basic"
+	templates := []exportTemplate{{
+		Name: "Card 1",
+		Ord:  0,
+		QFmt: "{{Front}}",
+		AFmt: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}",
+	}}
+	requirements := []any{[]any{0, "any", []int{0}}}
+
+	if reversed {
+		name = "Basic (and reversed card)"
+		templates = append(templates, exportTemplate{
+			Name: "Card 2",
+			Ord:  1,
+			QFmt: "{{Back}}",
+			AFmt: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Front}}",
+		})
+		requirements = append(requirements, []any{1, "any", []int{1}})
+	}
+
+	return map[string]any{
+		"id":    id,
+		"name":  name,
+		"type":  0,
+		"mod":   now.Unix(),
+		"usn":   -1,
+		"sortf": 0,
+		"did":   deckID,
+		"tmpls": templates,
+		"flds": []exportField{
+			{Name: "Front", Ord: 0, Font: "Arial", Size: 20, Media: []string{}},
+			{Name: "Back", Ord: 1, Font: "Arial", Size: 20, Media: []string{}},
+		},
+		"css":       cardTemplateCSS,
+		"latexPre":  latexPre,
+		"latexPost": latexPost,
+		"latexsvg":  false,
+		"req":       requirements,
+		"tags":      []string{},
+		"vers":      []any{},
+	}
+}
+
+// noteGUID is stable for the card, so Anki updates notes of a package exported again instead of duplicating them.
+func noteGUID(deckName string, card Card) string {
+	hash := fnv.New64a()
+
+	if card.ID != "" {
+		_, _ = io.WriteString(hash, card.ID)
+	} else {
+		_, _ = io.WriteString(hash, deckName+fieldSeparator+card.Term)
+	}
+
+	value := hash.Sum64()
+	alphabetSize := uint64(len(guidAlphabet))
+
+	var guid strings.Builder
+
+	for value > 0 {
+		guid.WriteByte(guidAlphabet[value%alphabetSize])
+		value /= alphabetSize
+	}
+
+	return guid.String()
+}
+
+// htmlField converts plain text to a note field, line breaks become <br>.
+func htmlField(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+// fieldChecksum is the first 8 hex digits of sha1 of the field, Anki finds duplicates by it.
+func fieldChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(field)) //nolint:gosec
+
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
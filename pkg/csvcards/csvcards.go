@@ -0,0 +1,518 @@
+// Package csvcards reads cards, pairs of terms and meanings, from csv files exported by spreadsheets
+// and from rows of spreadsheets themselves.
+//
+// The zero Dialect reads a comma-separated UTF-8 file without a header, terms are in the first column
+// and meanings are in the second one.
+package csvcards
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const (
+	DelimiterComma     = "comma"
+	DelimiterSemicolon = "semicolon"
+	DelimiterTab       = "tab"
+	// DelimiterAuto picks the delimiter which splits sample lines into the same number of columns.
+	DelimiterAuto = "auto"
+
+	// HeaderNone means the first row is a card.
+	HeaderNone = "none"
+	// HeaderPresent means the first row names the columns.
+	HeaderPresent = "present"
+	// HeaderAuto treats the first row as a header when it names the columns or looks like a header.
+	HeaderAuto = "auto"
+
+	EncodingUTF8        = "utf-8"
+	EncodingUTF16       = "utf-16"
+	EncodingUTF16LE     = "utf-16le"
+	EncodingUTF16BE     = "utf-16be"
+	EncodingWindows1251 = "windows-1251"
+	EncodingKOI8R       = "koi8-r"
+	// EncodingAuto detects the encoding by the byte order mark, falling back to UTF-8 for valid UTF-8 content
+	// and to one of cyrillic code pages otherwise.
+	EncodingAuto = "auto"
+
+	defaultTermColumn    = "1"
+	defaultMeaningColumn = "2"
+
+	delimiterSampleLines = 10
+
+	// recordSeparator joins fields of a spreadsheet row into the raw content of its row error.
+	recordSeparator = " | "
+)
+
+var ErrUnknownDialect = errors.New("unknown csv dialect")
+
+// headerNames are column names which make the first row look like a header in HeaderAuto mode.
+var headerNames = []string{
+	"term", "word", "front", "question", "meaning", "translation", "definition", "back", "answer",
+	"термин", "слово", "вопрос", "значение", "перевод", "определение", "ответ",
+}
+
+// Dialect describes a csv file. Columns are referred by numbers starting from 1 or by header names,
+// a column referred by name requires a header. Lenient reading skips malformed rows instead of failing.
+type Dialect struct {
+	Delimiter     string `json:"delimiter,omitempty"`
+	Header        string `json:"header,omitempty"`
+	TermColumn    string `json:"term_column,omitempty"`
+	MeaningColumn string `json:"meaning_column,omitempty"`
+	Swap          bool   `json:"swap,omitempty"`
+	LazyQuotes    bool   `json:"lazy_quotes,omitempty"`
+	Encoding      string `json:"encoding,omitempty"`
+	Lenient       bool   `json:"lenient,omitempty"`
+}
+
+type Card struct {
+	Term    string
+	Meaning string
+}
+
+type ColumnNotFoundError struct {
+	Column string
+}
+
+func (e *ColumnNotFoundError) Error() string {
+	return fmt.Sprintf("column \"%s\" is not found", e.Column)
+}
+
+// RowError describes a skipped row, Raw is the decoded row content.
+type RowError struct {
+	Line   int
+	Reason string
+	Raw    string
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+const (
+	reasonMissingColumns = "term or meaning column is missing"
+	reasonEmptyCard      = "term or meaning is empty"
+)
+
+type row struct {
+	record []string
+	line   int
+	raw    string
+}
+
+type Reader struct {
+	csvReader     *csv.Reader
+	content       []byte
+	records       [][]string
+	recordIndex   int
+	dialect       Dialect
+	termIndex     int
+	meaningIndex  int
+	headerSkipped bool
+	firstRow      *row
+}
+
+// NewReader decodes the whole file from the dialect's encoding, byte order marks are stripped.
+// Auto delimiter and encoding are detected by the file content.
+func NewReader(r io.Reader, dialect Dialect) (*Reader, error) {
+	rawContent, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect.Encoding == EncodingAuto {
+		dialect.Encoding = detectEncoding(rawContent)
+	}
+
+	enc, err := dialectEncoding(dialect.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	content, _, err := transform.Bytes(enc.NewDecoder(), rawContent)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect.Delimiter == DelimiterAuto {
+		dialect.Delimiter = detectDelimiter(content)
+	}
+
+	delimiter, err := dialectDelimiter(dialect.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect.TermColumn == "" {
+		dialect.TermColumn = defaultTermColumn
+	}
+
+	if dialect.MeaningColumn == "" {
+		dialect.MeaningColumn = defaultMeaningColumn
+	}
+
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.Comma = delimiter
+	csvReader.LazyQuotes = dialect.LazyQuotes
+	csvReader.FieldsPerRecord = -1
+
+	return &Reader{
+		csvReader: csvReader,
+		content:   content,
+		dialect:   dialect,
+	}, nil
+}
+
+// NewRecordsReader reads cards from spreadsheet rows, the row with index i is the line i+1.
+// Only header, columns and swap of the dialect are used.
+func NewRecordsReader(records [][]string, dialect Dialect) *Reader {
+	if dialect.TermColumn == "" {
+		dialect.TermColumn = defaultTermColumn
+	}
+
+	if dialect.MeaningColumn == "" {
+		dialect.MeaningColumn = defaultMeaningColumn
+	}
+
+	return &Reader{
+		records: records,
+		dialect: dialect,
+	}
+}
+
+// Dialect returns the dialect with detected delimiter and encoding.
+// The auto header is resolved to present or none once the first card has been read.
+func (r *Reader) Dialect() Dialect {
+	return r.dialect
+}
+
+// Progress returns the percentage of the content which has been read.
+func (r *Reader) Progress() int {
+	if r.csvReader == nil {
+		if len(r.records) == 0 {
+			return 100 //nolint:mnd
+		}
+
+		return r.recordIndex * 100 / len(r.records) //nolint:mnd
+	}
+
+	if len(r.content) == 0 {
+		return 100 //nolint:mnd
+	}
+
+	return int(r.csvReader.InputOffset() * 100 / int64(len(r.content))) //nolint:mnd
+}
+
+func dialectDelimiter(delimiter string) (rune, error) {
+	switch delimiter {
+	case "", DelimiterComma:
+		return ',', nil
+	case DelimiterSemicolon:
+		return ';', nil
+	case DelimiterTab:
+		return '\t', nil
+	default:
+		return 0, fmt.Errorf("%w: delimiter \"%s\"", ErrUnknownDialect, delimiter)
+	}
+}
+
+func dialectEncoding(name string) (encoding.Encoding, error) {
+	switch name {
+	case "", EncodingUTF8:
+		return unicode.UTF8BOM, nil
+	case EncodingUTF16, EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case EncodingWindows1251:
+		return charmap.Windows1251, nil
+	case EncodingKOI8R:
+		return charmap.KOI8R, nil
+	default:
+		return nil, fmt.Errorf("%w: encoding \"%s\"", ErrUnknownDialect, name)
+	}
+}
+
+// Read returns the next card. A row without a term or a meaning is skipped with RowError,
+// so is a malformed row in lenient mode, blank rows are skipped silently.
+// It returns io.EOF when there are no more cards.
+func (r *Reader) Read() (Card, error) {
+	if !r.headerSkipped {
+		if err := r.readHeader(); err != nil {
+			return Card{}, err
+		}
+	}
+
+	for {
+		row, err := r.nextRow()
+		if err != nil {
+			var parseErr *csv.ParseError
+
+			if r.dialect.Lenient && errors.As(err, &parseErr) {
+				return Card{}, &RowError{Line: row.line, Reason: parseErr.Err.Error(), Raw: row.raw}
+			}
+
+			return Card{}, err
+		}
+
+		if isBlank(row.record) {
+			continue
+		}
+
+		if len(row.record) <= max(r.termIndex, r.meaningIndex) {
+			return Card{}, &RowError{Line: row.line, Reason: reasonMissingColumns, Raw: row.raw}
+		}
+
+		card := Card{
+			Term:    strings.TrimSpace(row.record[r.termIndex]),
+			Meaning: strings.TrimSpace(row.record[r.meaningIndex]),
+		}
+
+		if r.dialect.Swap {
+			card.Term, card.Meaning = card.Meaning, card.Term
+		}
+
+		if card.Term == "" || card.Meaning == "" {
+			return Card{}, &RowError{Line: row.line, Reason: reasonEmptyCard, Raw: row.raw}
+		}
+
+		return card, nil
+	}
+}
+
+func (r *Reader) nextRow() (*row, error) {
+	if r.firstRow != nil {
+		firstRow := r.firstRow
+		r.firstRow = nil
+
+		return firstRow, nil
+	}
+
+	return r.readRow()
+}
+
+// readRow keeps the row's line and raw content, including malformed rows.
+func (r *Reader) readRow() (*row, error) {
+	if r.csvReader == nil {
+		return r.readRecord()
+	}
+
+	start := r.csvReader.InputOffset()
+	record, err := r.csvReader.Read()
+	raw := strings.Trim(string(r.content[start:r.csvReader.InputOffset()]), "\r\n")
+
+	if err != nil {
+		var parseErr *csv.ParseError
+
+		if errors.As(err, &parseErr) {
+			return &row{line: parseErr.StartLine, raw: raw}, err
+		}
+
+		return nil, err
+	}
+
+	line, _ := r.csvReader.FieldPos(0)
+
+	return &row{record: record, line: line, raw: raw}, nil
+}
+
+// readRecord returns the next spreadsheet row, blank rows are skipped like empty lines of csv.
+func (r *Reader) readRecord() (*row, error) {
+	for r.recordIndex < len(r.records) {
+		record := r.records[r.recordIndex]
+		r.recordIndex++
+
+		if !isBlank(record) {
+			return &row{record: record, line: r.recordIndex, raw: strings.Join(record, recordSeparator)}, nil
+		}
+	}
+
+	return nil, io.EOF
+}
+
+// readHeader resolves the columns and skips the header if there is one.
+func (r *Reader) readHeader() error {
+	r.headerSkipped = true
+
+	termIndex, termIsNumber := columnNumber(r.dialect.TermColumn)
+	meaningIndex, meaningIsNumber := columnNumber(r.dialect.MeaningColumn)
+	r.termIndex, r.meaningIndex = termIndex, meaningIndex
+
+	hasHeader := r.dialect.Header == HeaderPresent || r.dialect.Header == HeaderAuto
+	if !hasHeader && termIsNumber && meaningIsNumber {
+		return nil
+	}
+
+	header, err := r.readRow()
+	if err != nil {
+		return err
+	}
+
+	var termFound, meaningFound bool
+
+	if !termIsNumber {
+		r.termIndex, termFound = headerIndex(header.record, r.dialect.TermColumn)
+		if !termFound {
+			return &ColumnNotFoundError{Column: r.dialect.TermColumn}
+		}
+	}
+
+	if !meaningIsNumber {
+		r.meaningIndex, meaningFound = headerIndex(header.record, r.dialect.MeaningColumn)
+		if !meaningFound {
+			return &ColumnNotFoundError{Column: r.dialect.MeaningColumn}
+		}
+	}
+
+	if r.dialect.Header == HeaderAuto && termIsNumber && meaningIsNumber && !looksLikeHeader(header.record) {
+		r.firstRow = header
+		r.dialect.Header = HeaderNone
+	} else {
+		r.dialect.Header = HeaderPresent
+	}
+
+	return nil
+}
+
+// columnNumber converts a column number to an index, ok is false for column names.
+func columnNumber(column string) (int, bool) {
+	number, err := strconv.Atoi(column)
+	if err != nil || number < 1 {
+		return 0, false
+	}
+
+	return number - 1, true
+}
+
+func headerIndex(header []string, name string) (int, bool) {
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name)) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func looksLikeHeader(record []string) bool {
+	for _, column := range record {
+		for _, name := range headerNames {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isBlank(record []string) bool {
+	for _, column := range record {
+		if strings.TrimSpace(column) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func detectEncoding(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, []byte{0xEF, 0xBB, 0xBF}):
+		return EncodingUTF8
+	case bytes.HasPrefix(content, []byte{0xFF, 0xFE}):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(content, []byte{0xFE, 0xFF}):
+		return EncodingUTF16BE
+	case utf8.Valid(content):
+		return EncodingUTF8
+	}
+
+	// Lowercase letters prevail in a text. They are in the upper half of windows-1251 cyrillic letters
+	// and in the lower half of koi8-r ones.
+	var upperHalf, lowerHalf int
+
+	for _, b := range content {
+		switch {
+		case b >= 0xE0:
+			upperHalf++
+		case b >= 0xC0:
+			lowerHalf++
+		}
+	}
+
+	if lowerHalf > upperHalf {
+		return EncodingKOI8R
+	}
+
+	return EncodingWindows1251
+}
+
+// detectDelimiter prefers a delimiter which occurs the same number of times in each sample line,
+// the most frequent one wins, comma is the fallback.
+func detectDelimiter(content []byte) string {
+	lines := make([]string, 0, delimiterSampleLines)
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+
+		if len(lines) == delimiterSampleLines {
+			break
+		}
+	}
+
+	detected, detectedCount := DelimiterComma, 0
+
+	for _, name := range []string{DelimiterTab, DelimiterSemicolon, DelimiterComma} {
+		delimiter, _ := dialectDelimiter(name)
+		count := consistentCount(lines, delimiter)
+
+		if count > detectedCount {
+			detected, detectedCount = name, count
+		}
+	}
+
+	return detected
+}
+
+// consistentCount returns the number of unquoted delimiters in each line, or 0 if lines have different numbers.
+func consistentCount(lines []string, delimiter rune) int {
+	count := -1
+
+	for _, line := range lines {
+		var lineCount int
+
+		quoted := false
+
+		for _, char := range line {
+			switch char {
+			case '"':
+				quoted = !quoted
+			case delimiter:
+				if !quoted {
+					lineCount++
+				}
+			}
+		}
+
+		if count >= 0 && lineCount != count {
+			return 0
+		}
+
+		count = lineCount
+	}
+
+	return max(count, 0)
+}
@@ -0,0 +1,247 @@
+// Package pgnotify delivers notifications between processes sharing a Postgres database with NOTIFY and LISTEN.
+//
+// Each process listens on a dedicated connection and broadcasts received notifications to its subscribers.
+// Notifications are delivered at most once: they are lost while the listener reconnects,
+// and a subscriber which doesn't keep up with notifications misses them.
+package pgnotify
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	_defaultReconnectDelay = 5 * time.Second
+	_defaultBufferSize     = 64
+
+	unlistenTimeout = 5 * time.Second
+)
+
+var ErrHasBeenAlreadyClosed = errors.New("notifier has been already closed")
+
+type Option func(opts *options)
+
+type options struct {
+	reconnectDelay time.Duration
+	bufferSize     int
+	errorHandler   func(err error)
+}
+
+// WithReconnectDelay sets how long the listener waits before reconnecting after a failure.
+func WithReconnectDelay(delay time.Duration) Option {
+	return func(opts *options) {
+		opts.reconnectDelay = delay
+	}
+}
+
+// WithBufferSize sets how many notifications a subscriber may fall behind before it misses them.
+func WithBufferSize(size int) Option {
+	return func(opts *options) {
+		opts.bufferSize = size
+	}
+}
+
+// WithErrorHandler sets a handler of errors which happen in the listener.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(opts *options) {
+		opts.errorHandler = handler
+	}
+}
+
+type Notifier struct {
+	conn        *sql.DB
+	channel     string
+	opts        options
+	ctx         context.Context
+	cancel      context.CancelFunc
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+	closed      atomic.Bool
+	listenOnce  sync.Once
+	wg          sync.WaitGroup
+}
+
+func New(conn *sql.DB, channel string, opts ...Option) *Notifier {
+	notifierOptions := options{
+		reconnectDelay: _defaultReconnectDelay,
+		bufferSize:     _defaultBufferSize,
+		errorHandler:   func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(&notifierOptions)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Notifier{
+		conn:        conn,
+		channel:     channel,
+		opts:        notifierOptions,
+		ctx:         ctx,
+		cancel:      cancel,
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// Notify sends the payload to subscribers of every listening process.
+// Postgres limits the payload to 8000 bytes.
+func (n *Notifier) Notify(ctx context.Context, payload []byte) error {
+	if n.closed.Load() {
+		return ErrHasBeenAlreadyClosed
+	}
+
+	_, err := n.conn.ExecContext(ctx, `SELECT pg_notify($1, $2);`, n.channel, string(payload))
+
+	return err
+}
+
+// Subscribe returns a channel of received notifications and a function which unsubscribes from them.
+// The channel is closed when the subscriber unsubscribes or the notifier is closed.
+func (n *Notifier) Subscribe() (<-chan []byte, func()) {
+	notifications := make(chan []byte, n.opts.bufferSize)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed.Load() {
+		close(notifications)
+
+		return notifications, func() {}
+	}
+
+	n.subscribers[notifications] = struct{}{}
+
+	return notifications, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		if _, ok := n.subscribers[notifications]; ok {
+			delete(n.subscribers, notifications)
+			close(notifications)
+		}
+	}
+}
+
+// Listen starts receiving notifications in background.
+func (n *Notifier) Listen() {
+	if n.closed.Load() {
+		return
+	}
+
+	n.listenOnce.Do(func() {
+		n.wg.Add(1)
+
+		go n.listen()
+	})
+}
+
+// Close stops listening and closes subscribers' channels.
+func (n *Notifier) Close() error {
+	if n.closed.Swap(true) {
+		return nil
+	}
+
+	n.cancel()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for notifications := range n.subscribers {
+		delete(n.subscribers, notifications)
+		close(notifications)
+	}
+
+	return nil
+}
+
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+func (n *Notifier) listen() {
+	defer n.wg.Done()
+
+	for {
+		err := n.listenConn()
+		if n.ctx.Err() != nil {
+			return
+		}
+
+		n.opts.errorHandler(fmt.Errorf("channel %s listening failed: %w", n.channel, err))
+
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-time.After(n.opts.reconnectDelay):
+		}
+	}
+}
+
+// listenConn takes a connection from the pool and waits for notifications on it till a failure or closing.
+// The connection stops listening before it's returned to the pool.
+func (n *Notifier) listenConn() error {
+	conn, err := n.conn.Conn(n.ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unsupported driver connection %T", driverConn)
+		}
+
+		pgxConn := stdlibConn.Conn()
+
+		defer unlisten(pgxConn)
+
+		_, err := pgxConn.Exec(n.ctx, "LISTEN "+pgx.Identifier{n.channel}.Sanitize())
+		if err != nil {
+			return err
+		}
+
+		for {
+			notification, err := pgxConn.WaitForNotification(n.ctx)
+			if err != nil {
+				return err
+			}
+
+			n.broadcast([]byte(notification.Payload))
+		}
+	})
+}
+
+func unlisten(pgxConn *pgx.Conn) {
+	if pgxConn.IsClosed() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unlistenTimeout)
+	defer cancel()
+
+	_, _ = pgxConn.Exec(ctx, "UNLISTEN *")
+}
+
+// broadcast skips subscribers whose channels are full.
+func (n *Notifier) broadcast(payload []byte) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for notifications := range n.subscribers {
+		select {
+		case notifications <- payload:
+		default:
+		}
+	}
+}
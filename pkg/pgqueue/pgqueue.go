@@ -0,0 +1,469 @@
+// Package pgqueue implements a durable queue of works stored in the queue_jobs Postgres table.
+//
+// Works are claimed with SELECT ... FOR UPDATE SKIP LOCKED, so several processes can share a queue.
+// A claimed work is leased to its worker and the lease is prolonged with heartbeats while the work is running.
+// A work whose lease has expired, e.g. because its process crashed, becomes visible to other workers again,
+// so works must tolerate being done more than once.
+//
+// A failed work is retried with exponential backoff until it runs out of attempts or fails with an error
+// which is not retryable. Then it's moved to the dead-letter state and stays in the table until it's requeued.
+// A work which panics is moved to the dead-letter state right away, the panic doesn't reach the process.
+package pgqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	_defaultPollInterval   = time.Second
+	_defaultLeaseTimeout   = time.Minute
+	_defaultEnqueueTimeout = 5 * time.Second
+	_defaultMaxAttempts    = 5
+	_defaultBaseDelay      = 5 * time.Second
+	_defaultMaxDelay       = 10 * time.Minute
+
+	heartbeatsPerLease = 3
+	workerIDBytes      = 4
+)
+
+var ErrHasBeenAlreadyClosed = errors.New("queue has been already closed")
+
+// errLeaseLost is the cause of a work's context cancellation when another worker may be doing the work already.
+var errLeaseLost = errors.New("lease has been lost")
+
+// PanicError is the error of a work which has panicked, the work would most likely panic again,
+// so it isn't retried. The stack is passed to the error handler only.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("work has panicked: %v", e.Value)
+}
+
+// Work returns an error when it should be retried later.
+type Work interface {
+	Do(ctx context.Context) error
+}
+
+// DeadWork is implemented by works which should know that they have been moved to the dead-letter state.
+type DeadWork interface {
+	Dead(ctx context.Context, err error)
+}
+
+// RetryPolicy defines how failed works are retried.
+// A work is retried with exponential backoff and jitter while it has attempts and its error is retryable.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	IsRetryable func(err error) bool
+}
+
+// Backoff returns a random delay before the next attempt, it grows twice with each attempt up to MaxDelay.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	halfDelay := min(delay, p.MaxDelay) / 2 //nolint:mnd
+
+	return halfDelay + mathrand.N(halfDelay+1)
+}
+
+// Codec converts works to payloads stored in the queue and back.
+type Codec[W Work] interface {
+	Encode(work W) ([]byte, error)
+	Decode(payload []byte) (W, error)
+}
+
+type Option func(opts *options)
+
+type options struct {
+	pollInterval time.Duration
+	leaseTimeout time.Duration
+	retryPolicy  RetryPolicy
+	errorHandler func(err error)
+}
+
+// WithPollInterval sets how often idle workers look for new works.
+func WithPollInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.pollInterval = interval
+	}
+}
+
+// WithLeaseTimeout sets how long a claimed work stays invisible to other workers without heartbeats.
+func WithLeaseTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.leaseTimeout = timeout
+	}
+}
+
+// WithRetryPolicy sets how failed works are retried, by default any error is retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(opts *options) {
+		opts.retryPolicy = policy
+	}
+}
+
+// WithErrorHandler sets a handler of errors which happen in workers.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(opts *options) {
+		opts.errorHandler = handler
+	}
+}
+
+type Queue[W Work] struct {
+	conn          *sql.DB
+	name          string
+	codec         Codec[W]
+	workersAmount int
+	workerID      string
+	opts          options
+	doneChan      chan struct{}
+	closed        atomic.Bool
+	processOnce   sync.Once
+	wg            sync.WaitGroup
+}
+
+type claimedJob struct {
+	id       int64
+	attempts int
+	payload  []byte
+}
+
+func New[W Work](conn *sql.DB, name string, codec Codec[W], workersAmount int, opts ...Option) *Queue[W] {
+	queueOptions := options{
+		pollInterval: _defaultPollInterval,
+		leaseTimeout: _defaultLeaseTimeout,
+		retryPolicy: RetryPolicy{
+			MaxAttempts: _defaultMaxAttempts,
+			BaseDelay:   _defaultBaseDelay,
+			MaxDelay:    _defaultMaxDelay,
+		},
+		errorHandler: func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(&queueOptions)
+	}
+
+	if queueOptions.retryPolicy.IsRetryable == nil {
+		queueOptions.retryPolicy.IsRetryable = func(error) bool { return true }
+	}
+
+	return &Queue[W]{
+		conn:          conn,
+		name:          name,
+		codec:         codec,
+		workersAmount: workersAmount,
+		workerID:      newWorkerID(),
+		opts:          queueOptions,
+		doneChan:      make(chan struct{}),
+	}
+}
+
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	suffix := make([]byte, workerIDBytes)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// QueueWork stores the work in the queue, it will be done by any process which processes the queue.
+func (q *Queue[W]) QueueWork(work W) error {
+	if q.closed.Load() {
+		return ErrHasBeenAlreadyClosed
+	}
+
+	payload, err := q.codec.Encode(work)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _defaultEnqueueTimeout)
+	defer cancel()
+
+	_, err = q.conn.ExecContext(ctx, `
+		INSERT INTO queue_jobs (queue, payload)
+		VALUES ($1, $2);
+	`, q.name, payload)
+
+	return err
+}
+
+func (q *Queue[W]) ProcessQueue() {
+	if q.closed.Load() {
+		return
+	}
+
+	q.processOnce.Do(func() {
+		for range q.workersAmount {
+			q.wg.Add(1)
+			go q.worker()
+		}
+	})
+}
+
+// Close stops claiming new works, works in progress are done till the end.
+func (q *Queue[W]) Close() error {
+	hasBeenCanceled := q.closed.Swap(true)
+
+	if !hasBeenCanceled {
+		close(q.doneChan)
+	}
+
+	return nil
+}
+
+func (q *Queue[W]) Wait() {
+	q.wg.Wait()
+}
+
+func (q *Queue[W]) worker() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.opts.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if q.processNext() {
+			continue
+		}
+
+		select {
+		case <-q.doneChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNext does the next visible work and reports whether there was one.
+func (q *Queue[W]) processNext() bool {
+	if q.closed.Load() {
+		return false
+	}
+
+	job, err := q.claim()
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			q.opts.errorHandler(err)
+		}
+
+		return false
+	}
+
+	work, err := q.codec.Decode(job.payload)
+	if err != nil {
+		q.bury(nil, job, fmt.Errorf("queue %s job %d decoding: %w", q.name, job.id, err))
+
+		return true
+	}
+
+	if job.attempts > q.opts.retryPolicy.MaxAttempts {
+		q.bury(work, job, fmt.Errorf("queue %s job %d lease has expired on the last attempt", q.name, job.id))
+
+		return true
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	heartbeatDone := make(chan struct{})
+
+	go q.heartbeat(ctx, cancel, job, heartbeatDone)
+
+	err = doWork(ctx, work)
+	leaseLost := errors.Is(context.Cause(ctx), errLeaseLost)
+
+	cancel(nil)
+	<-heartbeatDone
+
+	var panicErr *PanicError
+
+	switch {
+	case leaseLost:
+		// the work belongs to another worker now, it's neither retried nor buried by this one
+		q.opts.errorHandler(fmt.Errorf("queue %s job %d attempt %d has been abandoned", q.name, job.id, job.attempts))
+	case err == nil:
+		q.complete(job)
+	case errors.As(err, &panicErr):
+		q.opts.errorHandler(fmt.Errorf("queue %s job %d %w:\n%s", q.name, job.id, err, panicErr.Stack))
+		q.bury(work, job, err)
+	case job.attempts < q.opts.retryPolicy.MaxAttempts && q.opts.retryPolicy.IsRetryable(err):
+		q.retry(job, err)
+	default:
+		q.bury(work, job, err)
+	}
+
+	return true
+}
+
+// doWork does the work and turns its panic into PanicError.
+func doWork(ctx context.Context, work Work) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{Value: value, Stack: debug.Stack()}
+		}
+	}()
+
+	return work.Do(ctx)
+}
+
+// claim leases the oldest queued work or a work whose lease has expired.
+func (q *Queue[W]) claim() (*claimedJob, error) {
+	var job claimedJob
+
+	err := q.conn.QueryRowContext(context.Background(), `
+		UPDATE queue_jobs
+		SET
+			status='running',
+			attempts=attempts + 1,
+			locked_by=$2,
+			locked_until=CURRENT_TIMESTAMP + make_interval(secs => $3),
+			updated_at=CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id
+			FROM queue_jobs
+			WHERE
+				queue=$1
+				AND (
+					(status='queued' AND run_at <= CURRENT_TIMESTAMP)
+					OR (status='running' AND locked_until < CURRENT_TIMESTAMP)
+				)
+			ORDER BY run_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, attempts, payload;
+	`, q.name, q.workerID, q.opts.leaseTimeout.Seconds()).Scan(&job.id, &job.attempts, &job.payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// heartbeat prolongs the lease while the work is running.
+// The work is canceled when the lease has been lost, since another worker may be doing it already.
+func (q *Queue[W]) heartbeat(
+	ctx context.Context,
+	cancel context.CancelCauseFunc,
+	job *claimedJob,
+	done chan struct{},
+) {
+	defer close(done)
+
+	ticker := time.NewTicker(q.opts.leaseTimeout / heartbeatsPerLease)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := q.conn.ExecContext(ctx, `
+			UPDATE queue_jobs
+			SET
+				locked_until=CURRENT_TIMESTAMP + make_interval(secs => $4),
+				updated_at=CURRENT_TIMESTAMP
+			WHERE id=$1 AND locked_by=$2 AND attempts=$3;
+		`, job.id, q.workerID, job.attempts, q.opts.leaseTimeout.Seconds())
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				q.opts.errorHandler(err)
+			}
+
+			continue
+		}
+
+		if affected, rowsErr := result.RowsAffected(); rowsErr == nil && affected == 0 {
+			q.opts.errorHandler(fmt.Errorf("queue %s job %d %w", q.name, job.id, errLeaseLost))
+			cancel(errLeaseLost)
+
+			return
+		}
+	}
+}
+
+// retry makes the work visible again after the backoff delay.
+func (q *Queue[W]) retry(job *claimedJob, err error) {
+	q.opts.errorHandler(fmt.Errorf("queue %s job %d attempt %d failed: %w", q.name, job.id, job.attempts, err))
+
+	_, execErr := q.conn.ExecContext(context.Background(), `
+		UPDATE queue_jobs
+		SET
+			status='queued',
+			run_at=CURRENT_TIMESTAMP + make_interval(secs => $4),
+			last_error=$5,
+			locked_by=NULL,
+			locked_until=NULL,
+			updated_at=CURRENT_TIMESTAMP
+		WHERE id=$1 AND locked_by=$2 AND attempts=$3;
+	`, job.id, q.workerID, job.attempts, q.opts.retryPolicy.Backoff(job.attempts).Seconds(), err.Error())
+	if execErr != nil {
+		q.opts.errorHandler(execErr)
+	}
+}
+
+// bury moves the work to the dead-letter state, the work is notified if it implements DeadWork.
+// A work which has been claimed by another worker meanwhile is left to that worker and isn't notified.
+func (q *Queue[W]) bury(work Work, job *claimedJob, err error) {
+	q.opts.errorHandler(fmt.Errorf("queue %s job %d is dead: %w", q.name, job.id, err))
+
+	result, execErr := q.conn.ExecContext(context.Background(), `
+		UPDATE queue_jobs
+		SET
+			status='dead',
+			last_error=$4,
+			locked_by=NULL,
+			locked_until=NULL,
+			updated_at=CURRENT_TIMESTAMP
+		WHERE id=$1 AND locked_by=$2 AND attempts=$3;
+	`, job.id, q.workerID, job.attempts, err.Error())
+	if execErr != nil {
+		q.opts.errorHandler(execErr)
+
+		return
+	}
+
+	if affected, rowsErr := result.RowsAffected(); rowsErr != nil || affected == 0 {
+		return
+	}
+
+	if deadWork, ok := work.(DeadWork); ok {
+		deadWork.Dead(context.Background(), err)
+	}
+}
+
+// complete removes the work from the queue unless it has been claimed by another worker.
+func (q *Queue[W]) complete(job *claimedJob) {
+	_, err := q.conn.ExecContext(context.Background(), `
+		DELETE FROM queue_jobs
+		WHERE id=$1 AND locked_by=$2 AND attempts=$3;
+	`, job.id, q.workerID, job.attempts)
+	if err != nil {
+		q.opts.errorHandler(err)
+	}
+}
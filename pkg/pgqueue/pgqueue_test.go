@@ -0,0 +1,57 @@
+package pgqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type workFunc func(ctx context.Context) error
+
+func (f workFunc) Do(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestDoWork(t *testing.T) {
+	t.Run("work error is returned", func(t *testing.T) {
+		workErr := errors.New("boom")
+
+		err := doWork(context.Background(), workFunc(func(context.Context) error {
+			return workErr
+		}))
+
+		assert.ErrorIs(t, err, workErr)
+	})
+
+	t.Run("panic is turned into error", func(t *testing.T) {
+		err := doWork(context.Background(), workFunc(func(context.Context) error {
+			var rows []string
+
+			_ = rows[1]
+
+			return nil
+		}))
+
+		var panicErr *PanicError
+
+		if assert.ErrorAs(t, err, &panicErr) {
+			assert.Contains(t, panicErr.Error(), "index out of range")
+			assert.Contains(t, string(panicErr.Stack), "TestDoWork")
+		}
+	})
+
+	t.Run("panic with error value", func(t *testing.T) {
+		panicValue := errors.New("boom")
+
+		err := doWork(context.Background(), workFunc(func(context.Context) error {
+			panic(panicValue)
+		}))
+
+		var panicErr *PanicError
+
+		assert.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, panicValue, panicErr.Value)
+	})
+}
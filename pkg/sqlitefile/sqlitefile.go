@@ -0,0 +1,556 @@
+// Package sqlitefile reads tables of a SQLite database file held in memory.
+//
+// It's a minimal read-only reader of the SQLite file format for importing data from other applications:
+// it walks table b-trees and decodes records, there is no SQL, indexes and rollback journals are ignored.
+// Only UTF-8 databases and tables with row ids are supported.
+//
+// Databases come from untrusted files, so every page is read at most once per table
+// and a read fails when it exceeds the limits of visited pages, rows or payload bytes.
+package sqlitefile
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	headerSize        = 100
+	maxPageSize       = 65536
+	schemaRootPage    = 1
+	encodingUTF8      = 1
+	maxBTreeDepth     = 64
+	maxVarintBytes    = 9
+	leafHeaderSize    = 8
+	interiorHeaderLen = 12
+
+	pageTypeInteriorTable = 0x05
+	pageTypeLeafTable     = 0x0D
+
+	// Read limits of a single table.
+	maxVisitedPages = 1 << 18
+	maxRows         = 1 << 20
+	maxPayloadBytes = 256 << 20
+)
+
+var (
+	ErrNotDatabase         = errors.New("not a sqlite database")
+	ErrUnsupportedEncoding = errors.New("only utf-8 sqlite databases are supported")
+	ErrCorrupted           = errors.New("sqlite database is corrupted")
+	ErrLimitExceeded       = errors.New("sqlite table exceeds read limits")
+)
+
+type TableNotFoundError struct {
+	Name string
+}
+
+func (e *TableNotFoundError) Error() string {
+	return fmt.Sprintf("table \"%s\" is not found", e.Name)
+}
+
+// Row maps column names to values: int64, float64, string, []byte or nil.
+type Row map[string]any
+
+// Int returns the integer value of the column, or 0 if it's not an integer.
+func (r Row) Int(column string) int64 {
+	value, _ := r[column].(int64)
+
+	return value
+}
+
+// Text returns the text value of the column, blobs are converted to text and other values are empty.
+func (r Row) Text(column string) string {
+	switch value := r[column].(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return ""
+	}
+}
+
+type DB struct {
+	data       []byte
+	pageSize   int
+	usableSize int
+	pagesCount int
+}
+
+type table struct {
+	rootPage int
+	columns  []string
+	// rowIDColumn is the INTEGER PRIMARY KEY column, it's stored as the row id instead of the record.
+	rowIDColumn int
+}
+
+func Open(data []byte) (*DB, error) {
+	if len(data) < headerSize || !bytes.HasPrefix(data, []byte("SQLite format 3\x00")) {
+		return nil, ErrNotDatabase
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = maxPageSize
+	}
+
+	if pageSize < 512 || pageSize > maxPageSize || pageSize&(pageSize-1) != 0 || len(data)%pageSize != 0 {
+		return nil, ErrCorrupted
+	}
+
+	if textEncoding := binary.BigEndian.Uint32(data[56:60]); textEncoding != encodingUTF8 && textEncoding != 0 {
+		return nil, ErrUnsupportedEncoding
+	}
+
+	return &DB{
+		data:       data,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(data[20]),
+		pagesCount: len(data) / pageSize,
+	}, nil
+}
+
+// HasTable tells whether the database has the table.
+func (db *DB) HasTable(ctx context.Context, name string) (bool, error) {
+	_, err := db.table(ctx, name)
+
+	var notFoundErr *TableNotFoundError
+
+	if errors.As(err, &notFoundErr) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Rows returns all rows of the table in row id order.
+// Reading stops with the context error when the context is done.
+func (db *DB) Rows(ctx context.Context, name string) ([]Row, error) {
+	t, err := db.table(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0)
+
+	err = db.newWalker(ctx).walk(t.rootPage, 0, func(rowID int64, values []any) {
+		row := make(Row, len(t.columns))
+
+		for i, column := range t.columns {
+			switch {
+			case i == t.rowIDColumn:
+				row[column] = rowID
+			case i < len(values):
+				row[column] = values[i]
+			default:
+				row[column] = nil
+			}
+		}
+
+		rows = append(rows, row)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// table finds the table in the schema table, whose columns are type, name, tbl_name, rootpage and sql.
+func (db *DB) table(ctx context.Context, name string) (*table, error) {
+	var (
+		found *table
+		err   error
+	)
+
+	walkErr := db.newWalker(ctx).walk(schemaRootPage, 0, func(_ int64, values []any) {
+		if found != nil || len(values) < 5 { //nolint:mnd
+			return
+		}
+
+		objectType, _ := values[0].(string)
+		objectName, _ := values[1].(string)
+		rootPage, _ := values[3].(int64)
+		createSQL, _ := values[4].(string)
+
+		if objectType != "table" || !strings.EqualFold(objectName, name) {
+			return
+		}
+
+		if rootPage < 1 || int(rootPage) > db.pagesCount {
+			err = ErrCorrupted
+
+			return
+		}
+
+		columns, rowIDColumn := parseColumns(createSQL)
+		found = &table{rootPage: int(rootPage), columns: columns, rowIDColumn: rowIDColumn}
+	})
+
+	switch {
+	case walkErr != nil:
+		return nil, walkErr
+	case err != nil:
+		return nil, err
+	case found == nil:
+		return nil, &TableNotFoundError{Name: name}
+	}
+
+	return found, nil
+}
+
+// walker reads pages of a single table b-tree and keeps track of the read limits.
+type walker struct {
+	db           *DB
+	ctx          context.Context
+	visited      map[int]bool
+	rows         int
+	payloadBytes int
+}
+
+func (db *DB) newWalker(ctx context.Context) *walker {
+	return &walker{
+		db:      db,
+		ctx:     ctx,
+		visited: make(map[int]bool),
+	}
+}
+
+// page returns the page by its number, a page which has been already read means a loop in the file.
+func (w *walker) page(pageNumber int) ([]byte, error) {
+	if err := w.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if pageNumber < 1 || pageNumber > w.db.pagesCount || w.visited[pageNumber] {
+		return nil, ErrCorrupted
+	}
+
+	if len(w.visited) >= maxVisitedPages {
+		return nil, ErrLimitExceeded
+	}
+
+	w.visited[pageNumber] = true
+
+	return w.db.data[(pageNumber-1)*w.db.pageSize : pageNumber*w.db.pageSize], nil
+}
+
+// walk visits records of the table b-tree in row id order.
+func (w *walker) walk(pageNumber int, depth int, visit func(rowID int64, values []any)) error {
+	if depth > maxBTreeDepth {
+		return ErrCorrupted
+	}
+
+	page, err := w.page(pageNumber)
+	if err != nil {
+		return err
+	}
+
+	headerOffset := 0
+	if pageNumber == 1 {
+		headerOffset = headerSize
+	}
+
+	header := page[headerOffset:]
+	cellsCount := int(binary.BigEndian.Uint16(header[3:5]))
+
+	switch header[0] {
+	case pageTypeLeafTable:
+		return w.walkLeaf(page, headerOffset+leafHeaderSize, cellsCount, visit)
+	case pageTypeInteriorTable:
+		pointers := headerOffset + interiorHeaderLen
+
+		for i := range cellsCount {
+			cellOffset, err := cellPointer(page, pointers, i)
+			if err != nil || cellOffset+4 > len(page) {
+				return ErrCorrupted
+			}
+
+			leftChild := int(binary.BigEndian.Uint32(page[cellOffset : cellOffset+4]))
+
+			if err = w.walk(leftChild, depth+1, visit); err != nil {
+				return err
+			}
+		}
+
+		return w.walk(int(binary.BigEndian.Uint32(header[8:12])), depth+1, visit)
+	default:
+		return ErrCorrupted
+	}
+}
+
+func (w *walker) walkLeaf(page []byte, pointers int, cellsCount int, visit func(rowID int64, values []any)) error {
+	for i := range cellsCount {
+		cellOffset, err := cellPointer(page, pointers, i)
+		if err != nil {
+			return err
+		}
+
+		payloadSize, n := readVarint(page[cellOffset:])
+		if n == 0 || payloadSize < 0 {
+			return ErrCorrupted
+		}
+
+		cellOffset += n
+
+		rowID, n := readVarint(page[cellOffset:])
+		if n == 0 {
+			return ErrCorrupted
+		}
+
+		cellOffset += n
+
+		if w.rows++; w.rows > maxRows {
+			return ErrLimitExceeded
+		}
+
+		payload, err := w.payload(page, cellOffset, payloadSize)
+		if err != nil {
+			return err
+		}
+
+		values, err := decodeRecord(payload)
+		if err != nil {
+			return err
+		}
+
+		visit(rowID, values)
+	}
+
+	return nil
+}
+
+func cellPointer(page []byte, pointers int, i int) (int, error) {
+	offset := pointers + i*2
+	if offset+2 > len(page) {
+		return 0, ErrCorrupted
+	}
+
+	cellOffset := int(binary.BigEndian.Uint16(page[offset : offset+2]))
+	if cellOffset >= len(page) {
+		return 0, ErrCorrupted
+	}
+
+	return cellOffset, nil
+}
+
+// payload collects the cell's payload, a large payload continues in a chain of overflow pages.
+func (w *walker) payload(page []byte, offset int, payloadSize int64) ([]byte, error) {
+	if payloadSize > int64(len(w.db.data)) {
+		return nil, ErrCorrupted
+	}
+
+	size := int(payloadSize)
+
+	// payloads of a well-formed table never exceed the file, since pages aren't shared
+	if w.payloadBytes += size; w.payloadBytes > min(maxPayloadBytes, len(w.db.data)) {
+		return nil, ErrLimitExceeded
+	}
+
+	usableSize := w.db.usableSize
+	maxLocal := usableSize - 35                            //nolint:mnd
+	minLocal := (usableSize-12)*32/255 - 23                //nolint:mnd
+	localSize := minLocal + (size-minLocal)%(usableSize-4) //nolint:mnd
+
+	if size <= maxLocal {
+		localSize = size
+	} else if localSize > maxLocal {
+		localSize = minLocal
+	}
+
+	if offset+localSize > len(page) {
+		return nil, ErrCorrupted
+	}
+
+	payload := make([]byte, 0, size)
+	payload = append(payload, page[offset:offset+localSize]...)
+
+	if localSize == size {
+		return payload, nil
+	}
+
+	if offset+localSize+4 > len(page) {
+		return nil, ErrCorrupted
+	}
+
+	overflowPage := int(binary.BigEndian.Uint32(page[offset+localSize:]))
+
+	for len(payload) < size {
+		overflow, err := w.page(overflowPage)
+		if err != nil {
+			return nil, err
+		}
+
+		chunkSize := min(size-len(payload), usableSize-4) //nolint:mnd
+
+		payload = append(payload, overflow[4:4+chunkSize]...)
+		overflowPage = int(binary.BigEndian.Uint32(overflow[:4]))
+	}
+
+	return payload, nil
+}
+
+// decodeRecord decodes values of the record format: a header of serial types followed by the values.
+func decodeRecord(payload []byte) ([]any, error) {
+	headerSize, n := readVarint(payload)
+	if n == 0 || headerSize < int64(n) || headerSize > int64(len(payload)) {
+		return nil, ErrCorrupted
+	}
+
+	header := payload[n:headerSize]
+	body := payload[headerSize:]
+	values := make([]any, 0)
+
+	for len(header) > 0 {
+		serialType, n := readVarint(header)
+		if n == 0 {
+			return nil, ErrCorrupted
+		}
+
+		header = header[n:]
+
+		value, size, err := decodeValue(serialType, body)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+		body = body[size:]
+	}
+
+	return values, nil
+}
+
+//nolint:mnd,cyclop
+func decodeValue(serialType int64, body []byte) (any, int, error) {
+	intSizes := map[int64]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 6, 6: 8}
+
+	switch {
+	case serialType == 0:
+		return nil, 0, nil
+	case serialType == 8:
+		return int64(0), 0, nil
+	case serialType == 9:
+		return int64(1), 0, nil
+	case serialType == 7:
+		if len(body) < 8 {
+			return nil, 0, ErrCorrupted
+		}
+
+		return math.Float64frombits(binary.BigEndian.Uint64(body[:8])), 8, nil
+	case intSizes[serialType] > 0:
+		size := intSizes[serialType]
+		if len(body) < size {
+			return nil, 0, ErrCorrupted
+		}
+
+		value := int64(int8(body[0]))
+		for _, b := range body[1:size] {
+			value = value<<8 | int64(b)
+		}
+
+		return value, size, nil
+	case serialType >= 12:
+		size := int((serialType - 12) / 2)
+		if size > len(body) {
+			return nil, 0, ErrCorrupted
+		}
+
+		if serialType%2 == 1 {
+			return string(body[:size]), size, nil
+		}
+
+		return bytes.Clone(body[:size]), size, nil
+	default:
+		return nil, 0, ErrCorrupted
+	}
+}
+
+// readVarint reads a big-endian variable-length integer, n is 0 when the buffer is too short.
+func readVarint(buf []byte) (int64, int) {
+	var value uint64
+
+	for i := range min(len(buf), maxVarintBytes) {
+		if i == maxVarintBytes-1 {
+			return int64(value<<8 | uint64(buf[i])), maxVarintBytes
+		}
+
+		value = value<<7 | uint64(buf[i]&0x7F)
+
+		if buf[i]&0x80 == 0 {
+			return int64(value), i + 1
+		}
+	}
+
+	return 0, 0
+}
+
+// parseColumns extracts column names from CREATE TABLE statement and finds the INTEGER PRIMARY KEY column.
+func parseColumns(createSQL string) ([]string, int) {
+	start := strings.Index(createSQL, "(")
+	end := strings.LastIndex(createSQL, ")")
+
+	if start < 0 || end < start {
+		return nil, -1
+	}
+
+	columns := make([]string, 0)
+	rowIDColumn := -1
+
+	for _, definition := range splitDefinitions(createSQL[start+1 : end]) {
+		fields := strings.Fields(definition)
+		if len(fields) == 0 || isTableConstraint(fields[0]) {
+			continue
+		}
+
+		if len(fields) > 1 && strings.EqualFold(fields[1], "INTEGER") &&
+			strings.Contains(strings.ToUpper(definition), "PRIMARY KEY") {
+			rowIDColumn = len(columns)
+		}
+
+		columns = append(columns, strings.Trim(fields[0], "\"`[]'"))
+	}
+
+	return columns, rowIDColumn
+}
+
+// splitDefinitions splits definitions by commas which are not inside parentheses or quotes.
+func splitDefinitions(definitions string) []string {
+	parts := make([]string, 0)
+	depth, start := 0, 0
+
+	var quote rune
+
+	for i, char := range definitions {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"' || char == '`':
+			quote = char
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case char == ',' && depth == 0:
+			parts = append(parts, definitions[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, definitions[start:])
+}
+
+func isTableConstraint(word string) bool {
+	switch strings.ToUpper(word) {
+	case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,239 @@
+package sqlitefile_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/llravell/simple-cards/pkg/sqlitefile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPageSize      = 4096
+	pageTypeInterior  = 0x05
+	pageTypeLeaf      = 0x0D
+	interiorHeaderLen = 12
+)
+
+var notesTable = &sqlitefile.Table{
+	Name: "notes",
+	SQL:  "CREATE TABLE notes (id integer primary key, flds text not null, mid integer)",
+}
+
+func writeNotes(t *testing.T, rows ...[]any) []byte {
+	t.Helper()
+
+	data, err := sqlitefile.Write([]*sqlitefile.Table{{Name: notesTable.Name, SQL: notesTable.SQL, Rows: rows}}, nil)
+	require.NoError(t, err)
+
+	return data
+}
+
+func page(data []byte, pageNumber int) []byte {
+	return data[(pageNumber-1)*testPageSize : pageNumber*testPageSize]
+}
+
+// interiorPage makes a table interior page whose cells and the rightmost pointer refer to the same child.
+func interiorPage(child int, cellsCount int) []byte {
+	p := make([]byte, testPageSize)
+	content := testPageSize
+
+	for i := range cellsCount {
+		cell := binary.BigEndian.AppendUint32(nil, uint32(child)) //nolint:gosec
+		cell = append(cell, byte(i+1))
+
+		content -= len(cell)
+		copy(p[content:], cell)
+		binary.BigEndian.PutUint16(p[interiorHeaderLen+i*2:], uint16(content)) //nolint:gosec
+	}
+
+	p[0] = pageTypeInterior
+	binary.BigEndian.PutUint16(p[3:], uint16(cellsCount)) //nolint:gosec
+	binary.BigEndian.PutUint16(p[5:], uint16(content))    //nolint:gosec
+	binary.BigEndian.PutUint32(p[8:], uint32(child))      //nolint:gosec
+
+	return p
+}
+
+func TestRows(t *testing.T) {
+	longText := strings.Repeat("long meaning ", 1000)
+	rows := make([][]any, 0, 500)
+
+	for i := range 500 {
+		rows = append(rows, []any{int64(i + 1), "term\x1fmeaning", int64(i % 3)})
+	}
+
+	rows = append(rows, []any{int64(1000), longText, nil})
+
+	db, err := sqlitefile.Open(writeNotes(t, rows...))
+	require.NoError(t, err)
+
+	got, err := db.Rows(context.Background(), "NOTES")
+	require.NoError(t, err)
+	require.Len(t, got, len(rows))
+
+	for i, row := range rows {
+		assert.Equal(t, row[0], got[i].Int("id"))
+		assert.Equal(t, row[1], got[i].Text("flds"))
+		assert.Equal(t, row[2], got[i]["mid"])
+	}
+}
+
+func TestHasTable(t *testing.T) {
+	db, err := sqlitefile.Open(writeNotes(t))
+	require.NoError(t, err)
+
+	ok, err := db.HasTable(context.Background(), "notes")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.HasTable(context.Background(), "cards")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = db.Rows(context.Background(), "cards")
+
+	var notFoundErr *sqlitefile.TableNotFoundError
+
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestOpen(t *testing.T) {
+	_, err := sqlitefile.Open([]byte("not a database"))
+	assert.ErrorIs(t, err, sqlitefile.ErrNotDatabase)
+
+	data := writeNotes(t)
+
+	_, err = sqlitefile.Open(data[:len(data)-1])
+	assert.ErrorIs(t, err, sqlitefile.ErrCorrupted)
+
+	utf16 := bytes.Clone(data)
+	binary.BigEndian.PutUint32(utf16[56:], 2)
+
+	_, err = sqlitefile.Open(utf16)
+	assert.ErrorIs(t, err, sqlitefile.ErrUnsupportedEncoding)
+}
+
+func TestRowsOfCanceledContext(t *testing.T) {
+	db, err := sqlitefile.Open(writeNotes(t, []any{int64(1), "term", int64(1)}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = db.Rows(ctx, "notes")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRowsOfLoopedBTree(t *testing.T) {
+	data := writeNotes(t)
+	require.Equal(t, byte(pageTypeLeaf), page(data, 2)[0], "the table root is expected on the second page")
+
+	t.Run("page refers to itself", func(t *testing.T) {
+		looped := bytes.Clone(data)
+		copy(page(looped, 2), interiorPage(2, 1))
+
+		db, err := sqlitefile.Open(looped)
+		require.NoError(t, err)
+
+		_, err = db.Rows(context.Background(), "notes")
+		assert.ErrorIs(t, err, sqlitefile.ErrCorrupted)
+	})
+
+	t.Run("chained pages refer to the next one many times", func(t *testing.T) {
+		const chainLength = 40
+
+		chained := bytes.Clone(data[:testPageSize])
+
+		for pageNumber := 2; pageNumber < 2+chainLength; pageNumber++ {
+			chained = append(chained, interiorPage(pageNumber+1, 3)...)
+		}
+
+		chained = append(chained, page(data, 2)...)
+
+		db, err := sqlitefile.Open(chained)
+		require.NoError(t, err)
+
+		done := make(chan error)
+
+		go func() {
+			_, err := db.Rows(context.Background(), "notes")
+			done <- err
+		}()
+
+		select {
+		case err = <-done:
+			assert.ErrorIs(t, err, sqlitefile.ErrCorrupted)
+		case <-time.After(5 * time.Second):
+			t.Fatal("rows reading hasn't finished")
+		}
+	})
+}
+
+func TestRowsOfLoopedOverflowChain(t *testing.T) {
+	data := writeNotes(t, []any{int64(1), strings.Repeat("x", 3*testPageSize), int64(1)})
+
+	// overflow pages are filled with the text after the next page number
+	overflowPages := make(map[int]int)
+
+	for pageNumber := 2; pageNumber <= len(data)/testPageSize; pageNumber++ {
+		p := page(data, pageNumber)
+		if bytes.Equal(p[4:20], bytes.Repeat([]byte("x"), 16)) {
+			overflowPages[pageNumber] = int(binary.BigEndian.Uint32(p[:4]))
+		}
+	}
+
+	require.GreaterOrEqual(t, len(overflowPages), 3)
+
+	for pageNumber, next := range overflowPages {
+		if next == 0 {
+			continue
+		}
+
+		looped := bytes.Clone(data)
+		binary.BigEndian.PutUint32(page(looped, pageNumber), uint32(pageNumber)) //nolint:gosec
+
+		db, err := sqlitefile.Open(looped)
+		require.NoError(t, err)
+
+		_, err = db.Rows(context.Background(), "notes")
+		assert.ErrorIs(t, err, sqlitefile.ErrCorrupted)
+	}
+}
+
+func FuzzRows(f *testing.F) {
+	f.Add([]byte("SQLite format 3\x00"))
+
+	for _, rows := range [][][]any{
+		nil,
+		{{int64(1), "term\x1fmeaning", int64(1)}, {int64(2), "", nil}},
+		{{int64(1), strings.Repeat("overflow", 1000), 1.5}},
+	} {
+		data, err := sqlitefile.Write([]*sqlitefile.Table{{Name: notesTable.Name, SQL: notesTable.SQL, Rows: rows}}, nil)
+		require.NoError(f, err)
+
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		db, err := sqlitefile.Open(data)
+		if err != nil {
+			return
+		}
+
+		_, err = db.Rows(context.Background(), "notes")
+
+		var notFoundErr *sqlitefile.TableNotFoundError
+
+		if err != nil && !errors.Is(err, sqlitefile.ErrCorrupted) && !errors.Is(err, sqlitefile.ErrLimitExceeded) &&
+			!errors.As(err, &notFoundErr) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,766 @@
+package sqlitefile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+const (
+	writePageSize = 4096
+	// nodeCapacity is the space for cells and their pointers,
+	// every page leaves room for the database header which only the first page has.
+	nodeCapacity = writePageSize - headerSize - interiorHeaderLen
+	// overflowChunkSize is the payload space of an overflow page after the next page number.
+	overflowChunkSize = writePageSize - 4
+
+	schemaFormat  = 4
+	sqliteVersion = 3045000
+
+	pageTypeInteriorIndex = 0x02
+	pageTypeLeafIndex     = 0x0A
+)
+
+var ErrDuplicateRowID = errors.New("duplicate row id")
+
+// Table is a table to write. Rows hold values in order of the table columns: nil, bool, int, int64, float64,
+// string or []byte. The INTEGER PRIMARY KEY column is the row id, rows of other tables get sequential row ids.
+type Table struct {
+	Name string
+	SQL  string
+	Rows [][]any
+}
+
+// Index is an index of a written table, its columns are taken from the CREATE INDEX statement.
+type Index struct {
+	Name  string
+	Table string
+	SQL   string
+}
+
+type tableCell struct {
+	rowID   int64
+	payload []byte
+}
+
+type writtenTable struct {
+	columns     []string
+	rowIDColumn int
+	cells       []tableCell
+}
+
+// node is a b-tree page whose cells are already encoded.
+type node struct {
+	pageType  byte
+	cells     [][]byte
+	rightPage int
+}
+
+type writer struct {
+	pages [][]byte
+}
+
+// Write builds a database file of the tables and their indexes.
+// The file is written at once, so it has no free pages and no journal.
+func Write(tables []*Table, indexes []*Index) ([]byte, error) {
+	w := &writer{}
+	w.allocate() // the first page is the root of the schema table
+
+	schemaRows := make([][]any, 0, len(tables)+len(indexes))
+	written := make(map[string]*writtenTable, len(tables))
+
+	for _, t := range tables {
+		columns, rowIDColumn := parseColumns(t.SQL)
+
+		cells, err := tableCells(t.Rows, rowIDColumn)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", t.Name, err)
+		}
+
+		rootPage := w.writeTableTree(cells, 0)
+		schemaRows = append(schemaRows, []any{"table", t.Name, t.Name, rootPage, t.SQL})
+		written[strings.ToLower(t.Name)] = &writtenTable{columns: columns, rowIDColumn: rowIDColumn, cells: cells}
+	}
+
+	for _, index := range indexes {
+		t, ok := written[strings.ToLower(index.Table)]
+		if !ok {
+			return nil, &TableNotFoundError{Name: index.Table}
+		}
+
+		keys, err := indexKeys(t, index.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", index.Name, err)
+		}
+
+		rootPage := w.writeIndexTree(keys)
+		schemaRows = append(schemaRows, []any{"index", index.Name, index.Table, rootPage, index.SQL})
+	}
+
+	schemaCells, err := tableCells(schemaRows, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	w.writeTableTree(schemaCells, schemaRootPage)
+
+	return w.bytes(), nil
+}
+
+// tableCells encodes rows as records ordered by row id.
+func tableCells(rows [][]any, rowIDColumn int) ([]tableCell, error) {
+	cells := make([]tableCell, 0, len(rows))
+
+	for i, row := range rows {
+		values := slices.Clone(row)
+		rowID := int64(i + 1)
+
+		if rowIDColumn >= 0 && rowIDColumn < len(values) {
+			id, ok := values[rowIDColumn].(int64)
+			if number, isInt := values[rowIDColumn].(int); isInt {
+				id, ok = int64(number), true
+			}
+
+			if !ok {
+				return nil, fmt.Errorf("row id %v is not an integer", values[rowIDColumn])
+			}
+
+			// the row id alias is stored as null in the record
+			rowID, values[rowIDColumn] = id, nil
+		}
+
+		payload, err := encodeRecord(values)
+		if err != nil {
+			return nil, err
+		}
+
+		cells = append(cells, tableCell{rowID: rowID, payload: payload})
+	}
+
+	slices.SortFunc(cells, func(a, b tableCell) int {
+		return compareValues(a.rowID, b.rowID)
+	})
+
+	for i := 1; i < len(cells); i++ {
+		if cells[i].rowID == cells[i-1].rowID {
+			return nil, fmt.Errorf("%w %d", ErrDuplicateRowID, cells[i].rowID)
+		}
+	}
+
+	return cells, nil
+}
+
+// indexKeys encodes index records, which are the indexed values followed by the row id, in the index order.
+func indexKeys(t *writtenTable, createSQL string) ([][]byte, error) {
+	start := strings.Index(createSQL, "(")
+	end := strings.LastIndex(createSQL, ")")
+
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("malformed index statement \"%s\"", createSQL)
+	}
+
+	indexColumns := make([]int, 0)
+
+	for _, definition := range splitDefinitions(createSQL[start+1 : end]) {
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := strings.Trim(fields[0], "\"`[]'")
+
+		column := slices.IndexFunc(t.columns, func(column string) bool {
+			return strings.EqualFold(column, name)
+		})
+		if column < 0 {
+			return nil, fmt.Errorf("unknown index column \"%s\"", name)
+		}
+
+		indexColumns = append(indexColumns, column)
+	}
+
+	keys := make([][]any, 0, len(t.cells))
+
+	for _, c := range t.cells {
+		values, err := decodeRecord(c.payload)
+		if err != nil {
+			return nil, err
+		}
+
+		key := make([]any, 0, len(indexColumns)+1)
+
+		for _, column := range indexColumns {
+			switch {
+			case column == t.rowIDColumn:
+				key = append(key, c.rowID)
+			case column < len(values):
+				key = append(key, values[column])
+			default:
+				key = append(key, nil)
+			}
+		}
+
+		keys = append(keys, append(key, c.rowID))
+	}
+
+	slices.SortFunc(keys, compareRecords)
+
+	payloads := make([][]byte, 0, len(keys))
+
+	for _, key := range keys {
+		payload, err := encodeRecord(key)
+		if err != nil {
+			return nil, err
+		}
+
+		payloads = append(payloads, payload)
+	}
+
+	return payloads, nil
+}
+
+// writeTableTree writes a b+tree of the cells, records are kept in leaves and interior pages only
+// have the largest row ids of their children. The root is written to rootPage unless it's 0.
+func (w *writer) writeTableTree(cells []tableCell, rootPage int) int {
+	type child struct {
+		page     int
+		maxRowID int64
+	}
+
+	groups := make([][]tableCell, 0)
+	group := make([]tableCell, 0)
+	used := 0
+
+	for _, c := range cells {
+		size := tableLeafCellSize(c) + 2 //nolint:mnd
+		if used+size > nodeCapacity && len(group) > 0 {
+			groups = append(groups, group)
+			group, used = make([]tableCell, 0), 0
+		}
+
+		group = append(group, c)
+		used += size
+	}
+
+	if len(groups) == 0 {
+		return w.writeRoot(rootPage, &node{pageType: pageTypeLeafTable, cells: w.tableLeafCells(group)})
+	}
+
+	children := make([]child, 0, len(groups)+1)
+
+	for _, leafCells := range append(groups, group) {
+		page := w.allocate()
+		w.writeNode(page, &node{pageType: pageTypeLeafTable, cells: w.tableLeafCells(leafCells)})
+		children = append(children, child{page: page, maxRowID: leafCells[len(leafCells)-1].rowID})
+	}
+
+	for {
+		// every child but the last one of a page has a cell, the last one is the right-most pointer
+		childGroups := make([][]child, 0)
+		childGroup := make([]child, 0)
+		used = 0
+
+		for i, c := range children {
+			if len(childGroup) > 0 {
+				size := tableInteriorCellSize(childGroup[len(childGroup)-1].maxRowID) + 2 //nolint:mnd
+				if used+size > nodeCapacity {
+					if i == len(children)-1 {
+						// the last page must have a cell too
+						childGroups = append(childGroups, childGroup[:len(childGroup)-1])
+						childGroup = childGroup[len(childGroup)-1:]
+					} else {
+						childGroups = append(childGroups, childGroup)
+						childGroup = make([]child, 0)
+					}
+
+					used = 0
+				} else {
+					used += size
+				}
+			}
+
+			childGroup = append(childGroup, c)
+		}
+
+		childGroups = append(childGroups, childGroup)
+		parents := make([]child, 0, len(childGroups))
+
+		for _, pageChildren := range childGroups {
+			interior := &node{pageType: pageTypeInteriorTable, rightPage: pageChildren[len(pageChildren)-1].page}
+
+			for _, c := range pageChildren[:len(pageChildren)-1] {
+				cellBytes := binary.BigEndian.AppendUint32(nil, uint32(c.page)) //nolint:gosec
+				interior.cells = append(interior.cells, appendVarint(cellBytes, c.maxRowID))
+			}
+
+			if len(childGroups) == 1 {
+				return w.writeRoot(rootPage, interior)
+			}
+
+			page := w.allocate()
+			w.writeNode(page, interior)
+			parents = append(parents, child{page: page, maxRowID: pageChildren[len(pageChildren)-1].maxRowID})
+		}
+
+		children = parents
+	}
+}
+
+// writeIndexTree writes a b-tree of the keys, interior pages hold keys which separate their children.
+func (w *writer) writeIndexTree(keys [][]byte) int {
+	leafGroups := make([][][]byte, 0)
+	separators := make([][]byte, 0)
+	group := make([][]byte, 0)
+	used := 0
+
+	for i, key := range keys {
+		size := indexCellSize(key, false) + 2 //nolint:mnd
+		if used+size <= nodeCapacity || len(group) == 0 {
+			group = append(group, key)
+			used += size
+
+			continue
+		}
+
+		if i == len(keys)-1 {
+			// the last leaf must have a key too, so the previous key separates the leaves
+			leafGroups = append(leafGroups, group[:len(group)-1])
+			separators = append(separators, group[len(group)-1])
+			group, used = [][]byte{key}, size
+
+			continue
+		}
+
+		leafGroups = append(leafGroups, group)
+		separators = append(separators, key)
+		group, used = make([][]byte, 0), 0
+	}
+
+	leafGroups = append(leafGroups, group)
+
+	if len(leafGroups) == 1 {
+		return w.writeRoot(0, &node{pageType: pageTypeLeafIndex, cells: w.indexCells(0, group)})
+	}
+
+	children := make([]int, 0, len(leafGroups))
+
+	for _, leafKeys := range leafGroups {
+		page := w.allocate()
+		w.writeNode(page, &node{pageType: pageTypeLeafIndex, cells: w.indexCells(0, leafKeys)})
+		children = append(children, page)
+	}
+
+	for {
+		parents := make([]int, 0)
+		parentSeparators := make([][]byte, 0)
+		interior := &node{pageType: pageTypeInteriorIndex}
+		cellChildren := make([]int, 0)
+		cellKeys := make([][]byte, 0)
+		used = 0
+
+		closePage := func(rightPage int) {
+			interior.rightPage = rightPage
+
+			for i, key := range cellKeys {
+				interior.cells = append(interior.cells, w.indexCells(cellChildren[i], [][]byte{key})...)
+			}
+
+			page := w.allocate()
+			w.writeNode(page, interior)
+			parents = append(parents, page)
+
+			interior = &node{pageType: pageTypeInteriorIndex}
+			cellChildren, cellKeys, used = make([]int, 0), make([][]byte, 0), 0
+		}
+
+		for i, separator := range separators {
+			size := indexCellSize(separator, true) + 2 //nolint:mnd
+			if used+size <= nodeCapacity || len(cellKeys) == 0 {
+				cellChildren = append(cellChildren, children[i])
+				cellKeys = append(cellKeys, separator)
+				used += size
+
+				continue
+			}
+
+			if i == len(separators)-1 {
+				// the last page must have a cell too, so the previous separator goes up
+				lastChild, lastKey := cellChildren[len(cellChildren)-1], cellKeys[len(cellKeys)-1]
+				cellChildren, cellKeys = cellChildren[:len(cellChildren)-1], cellKeys[:len(cellKeys)-1]
+
+				closePage(lastChild)
+				parentSeparators = append(parentSeparators, lastKey)
+
+				cellChildren, cellKeys, used = []int{children[i]}, [][]byte{separator}, size
+
+				continue
+			}
+
+			closePage(children[i])
+			parentSeparators = append(parentSeparators, separator)
+		}
+
+		if len(parents) == 0 {
+			interior.rightPage = children[len(children)-1]
+
+			for i, key := range cellKeys {
+				interior.cells = append(interior.cells, w.indexCells(cellChildren[i], [][]byte{key})...)
+			}
+
+			return w.writeRoot(0, interior)
+		}
+
+		closePage(children[len(children)-1])
+
+		children, separators = parents, parentSeparators
+	}
+}
+
+func (w *writer) allocate() int {
+	w.pages = append(w.pages, make([]byte, writePageSize))
+
+	return len(w.pages)
+}
+
+func (w *writer) writeRoot(rootPage int, root *node) int {
+	if rootPage == 0 {
+		rootPage = w.allocate()
+	}
+
+	w.writeNode(rootPage, root)
+
+	return rootPage
+}
+
+// writeNode lays out the page: the header, cell pointers and cells at the end of the page.
+func (w *writer) writeNode(pageNumber int, n *node) {
+	page := w.pages[pageNumber-1]
+
+	offset := 0
+	if pageNumber == 1 {
+		offset = headerSize
+	}
+
+	pointers := offset + leafHeaderSize
+	if n.pageType == pageTypeInteriorTable || n.pageType == pageTypeInteriorIndex {
+		pointers = offset + interiorHeaderLen
+		binary.BigEndian.PutUint32(page[offset+8:], uint32(n.rightPage)) //nolint:gosec
+	}
+
+	content := writePageSize
+
+	for i, cellBytes := range n.cells {
+		content -= len(cellBytes)
+		copy(page[content:], cellBytes)
+		binary.BigEndian.PutUint16(page[pointers+i*2:], uint16(content)) //nolint:gosec
+	}
+
+	page[offset] = n.pageType
+	binary.BigEndian.PutUint16(page[offset+3:], uint16(len(n.cells))) //nolint:gosec
+	binary.BigEndian.PutUint16(page[offset+5:], uint16(content))      //nolint:gosec
+}
+
+func (w *writer) tableLeafCells(cells []tableCell) [][]byte {
+	encoded := make([][]byte, 0, len(cells))
+
+	for _, c := range cells {
+		prefix := appendVarint(appendVarint(nil, int64(len(c.payload))), c.rowID)
+		encoded = append(encoded, w.payloadCell(prefix, c.payload, tableMaxLocal()))
+	}
+
+	return encoded
+}
+
+// indexCells encodes leaf cells, or interior cells pointing to leftChild unless it's 0.
+func (w *writer) indexCells(leftChild int, keys [][]byte) [][]byte {
+	encoded := make([][]byte, 0, len(keys))
+
+	for _, key := range keys {
+		var prefix []byte
+
+		if leftChild != 0 {
+			prefix = binary.BigEndian.AppendUint32(prefix, uint32(leftChild)) //nolint:gosec
+		}
+
+		prefix = appendVarint(prefix, int64(len(key)))
+		encoded = append(encoded, w.payloadCell(prefix, key, indexMaxLocal()))
+	}
+
+	return encoded
+}
+
+// payloadCell keeps the beginning of a large payload in the cell and writes the rest to overflow pages.
+func (w *writer) payloadCell(prefix []byte, payload []byte, maxLocal int) []byte {
+	local := localPayloadSize(len(payload), maxLocal)
+	cellBytes := append(prefix, payload[:local]...)
+
+	if local == len(payload) {
+		return cellBytes
+	}
+
+	rest := payload[local:]
+	previousPage := 0
+
+	for len(rest) > 0 {
+		page := w.allocate()
+
+		if previousPage == 0 {
+			cellBytes = binary.BigEndian.AppendUint32(cellBytes, uint32(page)) //nolint:gosec
+		} else {
+			binary.BigEndian.PutUint32(w.pages[previousPage-1], uint32(page)) //nolint:gosec
+		}
+
+		chunkSize := min(len(rest), overflowChunkSize)
+		copy(w.pages[page-1][4:], rest[:chunkSize])
+		rest, previousPage = rest[chunkSize:], page
+	}
+
+	return cellBytes
+}
+
+// bytes fills the database header and joins the pages.
+func (w *writer) bytes() []byte {
+	header := w.pages[0]
+
+	copy(header, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(header[16:], writePageSize)
+
+	header[18], header[19] = 1, 1                   // legacy file format versions
+	header[21], header[22], header[23] = 64, 32, 32 // payload fractions
+
+	binary.BigEndian.PutUint32(header[24:], 1)                    // file change counter
+	binary.BigEndian.PutUint32(header[28:], uint32(len(w.pages))) //nolint:gosec
+	binary.BigEndian.PutUint32(header[40:], 1)                    // schema cookie
+	binary.BigEndian.PutUint32(header[44:], schemaFormat)
+	binary.BigEndian.PutUint32(header[56:], encodingUTF8)
+	binary.BigEndian.PutUint32(header[92:], 1) // the change counter the version is valid for
+	binary.BigEndian.PutUint32(header[96:], sqliteVersion)
+
+	return bytes.Join(w.pages, nil)
+}
+
+func tableMaxLocal() int {
+	return writePageSize - 35 //nolint:mnd
+}
+
+func indexMaxLocal() int {
+	return (writePageSize-12)*64/255 - 23 //nolint:mnd
+}
+
+// localPayloadSize is how much of the payload is kept in the cell.
+func localPayloadSize(size int, maxLocal int) int {
+	minLocal := (writePageSize-12)*32/255 - 23                  //nolint:mnd
+	localSize := minLocal + (size-minLocal)%(overflowChunkSize) //nolint:mnd
+
+	switch {
+	case size <= maxLocal:
+		return size
+	case localSize <= maxLocal:
+		return localSize
+	default:
+		return minLocal
+	}
+}
+
+func tableLeafCellSize(c tableCell) int {
+	return payloadCellSize(varintLen(int64(len(c.payload)))+varintLen(c.rowID), len(c.payload), tableMaxLocal())
+}
+
+func tableInteriorCellSize(rowID int64) int {
+	return 4 + varintLen(rowID) //nolint:mnd
+}
+
+func indexCellSize(key []byte, interior bool) int {
+	prefixSize := varintLen(int64(len(key)))
+	if interior {
+		prefixSize += 4
+	}
+
+	return payloadCellSize(prefixSize, len(key), indexMaxLocal())
+}
+
+func payloadCellSize(prefixSize int, size int, maxLocal int) int {
+	local := localPayloadSize(size, maxLocal)
+	if local < size {
+		return prefixSize + local + 4 //nolint:mnd
+	}
+
+	return prefixSize + local
+}
+
+// encodeRecord encodes values of the record format: a header of serial types followed by the values.
+func encodeRecord(values []any) ([]byte, error) {
+	header := make([]byte, 0, len(values))
+	body := make([]byte, 0)
+
+	for _, value := range values {
+		serialType, data, err := encodeValue(value)
+		if err != nil {
+			return nil, err
+		}
+
+		header = appendVarint(header, serialType)
+		body = append(body, data...)
+	}
+
+	// the header size counts its own varint
+	sizeLen := 1
+	for varintLen(int64(len(header)+sizeLen)) > sizeLen {
+		sizeLen++
+	}
+
+	record := appendVarint(make([]byte, 0, sizeLen+len(header)+len(body)), int64(len(header)+sizeLen))
+	record = append(record, header...)
+
+	return append(record, body...), nil
+}
+
+//nolint:mnd,cyclop
+func encodeValue(value any) (int64, []byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil, nil
+	case bool:
+		if v {
+			return 9, nil, nil
+		}
+
+		return 8, nil, nil
+	case int:
+		return encodeValue(int64(v))
+	case int64:
+		switch {
+		case v == 0:
+			return 8, nil, nil
+		case v == 1:
+			return 9, nil, nil
+		case v >= math.MinInt8 && v <= math.MaxInt8:
+			return 1, []byte{byte(v)}, nil
+		case v >= math.MinInt16 && v <= math.MaxInt16:
+			return 2, binary.BigEndian.AppendUint16(nil, uint16(v)), nil
+		case v >= -1<<23 && v < 1<<23:
+			return 3, binary.BigEndian.AppendUint32(nil, uint32(v))[1:], nil
+		case v >= math.MinInt32 && v <= math.MaxInt32:
+			return 4, binary.BigEndian.AppendUint32(nil, uint32(v)), nil
+		case v >= -1<<47 && v < 1<<47:
+			return 5, binary.BigEndian.AppendUint64(nil, uint64(v))[2:], nil
+		default:
+			return 6, binary.BigEndian.AppendUint64(nil, uint64(v)), nil
+		}
+	case float64:
+		return 7, binary.BigEndian.AppendUint64(nil, math.Float64bits(v)), nil
+	case string:
+		return int64(len(v))*2 + 13, []byte(v), nil
+	case []byte:
+		return int64(len(v))*2 + 12, v, nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// appendVarint appends a big-endian variable-length integer, the ninth byte has all 8 bits.
+func appendVarint(buf []byte, value int64) []byte {
+	unsigned := uint64(value) //nolint:gosec
+
+	if unsigned>>56 != 0 {
+		var encoded [maxVarintBytes]byte
+
+		encoded[maxVarintBytes-1] = byte(unsigned)
+		unsigned >>= 8
+
+		for i := maxVarintBytes - 2; i >= 0; i-- {
+			encoded[i] = byte(unsigned&0x7F) | 0x80
+			unsigned >>= 7
+		}
+
+		return append(buf, encoded[:]...)
+	}
+
+	groups := make([]byte, 0, maxVarintBytes)
+
+	for {
+		groups = append(groups, byte(unsigned&0x7F)|0x80)
+		unsigned >>= 7
+
+		if unsigned == 0 {
+			break
+		}
+	}
+
+	groups[0] &= 0x7F
+	slices.Reverse(groups)
+
+	return append(buf, groups...)
+}
+
+func varintLen(value int64) int {
+	return len(appendVarint(nil, value))
+}
+
+// compareRecords orders records like sqlite does with the binary collation.
+func compareRecords(a, b []any) int {
+	for i := range min(len(a), len(b)) {
+		if result := compareValues(a[i], b[i]); result != 0 {
+			return result
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// compareValues orders nulls first, then numbers, texts and blobs.
+func compareValues(a, b any) int {
+	aClass, bClass := valueClass(a), valueClass(b)
+	if aClass != bClass {
+		return aClass - bClass
+	}
+
+	switch aValue := a.(type) {
+	case int64:
+		if bValue, ok := b.(int64); ok {
+			return compareNumbers(aValue, bValue)
+		}
+
+		return compareNumbers(float64(aValue), b.(float64)) //nolint:forcetypeassert
+	case float64:
+		if bValue, ok := b.(int64); ok {
+			return compareNumbers(aValue, float64(bValue))
+		}
+
+		return compareNumbers(aValue, b.(float64)) //nolint:forcetypeassert
+	case string:
+		return strings.Compare(aValue, b.(string)) //nolint:forcetypeassert
+	case []byte:
+		return bytes.Compare(aValue, b.([]byte)) //nolint:forcetypeassert
+	default:
+		return 0
+	}
+}
+
+func valueClass(value any) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case int64, float64:
+		return 1
+	case string:
+		return 2 //nolint:mnd
+	default:
+		return 3 //nolint:mnd
+	}
+}
+
+func compareNumbers[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,146 @@
+package srs
+
+import (
+	"math"
+	"time"
+)
+
+type fsrsRating int
+
+const (
+	fsrsAgain fsrsRating = iota + 1
+	fsrsHard
+	fsrsGood
+	fsrsEasy
+)
+
+const (
+	fsrsDecay         = -0.5
+	fsrsFactor        = 19.0 / 81.0
+	fsrsMinDifficulty = 1
+	fsrsMaxDifficulty = 10
+	fsrsMaxInterval   = 36500
+)
+
+// Default FSRS-4.5 model weights.
+var defaultFSRSWeights = [...]float64{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031, 1.6474,
+	0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+// FSRS schedules reviews with the Free Spaced Repetition Scheduler v4.5 algorithm,
+// so that a card is due when its recall probability drops to the desired retention.
+type FSRS struct {
+	weights          [len(defaultFSRSWeights)]float64
+	desiredRetention float64
+}
+
+func NewFSRS(desiredRetention float64) *FSRS {
+	return &FSRS{
+		weights:          defaultFSRSWeights,
+		desiredRetention: desiredRetention,
+	}
+}
+
+// toFSRSRating maps SM-2 grades to FSRS ratings: failed grades are "again",
+// 3 is "hard", 4 is "good" and 5 is "easy".
+func toFSRSRating(grade Grade) fsrsRating {
+	switch {
+	case grade < PassingGrade:
+		return fsrsAgain
+	case grade == PassingGrade:
+		return fsrsHard
+	case grade >= MaxGrade:
+		return fsrsEasy
+	default:
+		return fsrsGood
+	}
+}
+
+func (f *FSRS) Review(state State, grade Grade, reviewedAt time.Time) State {
+	rating := toFSRSRating(grade)
+
+	switch {
+	case state.Stability > 0:
+		f.reviewMemory(&state, rating, reviewedAt)
+	case state.Interval > 0:
+		// Card has been reviewed by another algorithm, so its interval is the best stability estimation.
+		state.Stability = float64(state.Interval)
+		state.Difficulty = f.initDifficulty(fsrsGood)
+		f.reviewMemory(&state, rating, reviewedAt)
+	default:
+		state.Stability = f.weights[rating-1]
+		state.Difficulty = f.initDifficulty(rating)
+	}
+
+	if rating == fsrsAgain {
+		state.Repetitions = 0
+		state.Lapses++
+	} else {
+		state.Repetitions++
+	}
+
+	state.Interval = f.nextInterval(state.Stability)
+	state.LastReview = reviewedAt
+	state.Due = daysAfter(reviewedAt, state.Interval)
+
+	return state
+}
+
+func (f *FSRS) reviewMemory(state *State, rating fsrsRating, reviewedAt time.Time) {
+	retrievability := math.Pow(1+fsrsFactor*daysBetween(state.LastReview, reviewedAt)/state.Stability, fsrsDecay)
+
+	if rating == fsrsAgain {
+		state.Stability = f.forgetStability(state.Difficulty, state.Stability, retrievability)
+	} else {
+		state.Stability = f.recallStability(state.Difficulty, state.Stability, retrievability, rating)
+	}
+
+	state.Difficulty = f.nextDifficulty(state.Difficulty, rating)
+}
+
+func (f *FSRS) initDifficulty(rating fsrsRating) float64 {
+	return clampDifficulty(f.weights[4] - float64(rating-fsrsGood)*f.weights[5])
+}
+
+func (f *FSRS) nextDifficulty(difficulty float64, rating fsrsRating) float64 {
+	nextDifficulty := difficulty - f.weights[6]*float64(rating-fsrsGood)
+
+	return clampDifficulty(f.weights[7]*f.initDifficulty(fsrsGood) + (1-f.weights[7])*nextDifficulty)
+}
+
+func (f *FSRS) recallStability(difficulty, stability, retrievability float64, rating fsrsRating) float64 {
+	hardPenalty, easyBonus := 1.0, 1.0
+
+	if rating == fsrsHard {
+		hardPenalty = f.weights[15]
+	}
+
+	if rating == fsrsEasy {
+		easyBonus = f.weights[16]
+	}
+
+	return stability * (1 + math.Exp(f.weights[8])*
+		(11-difficulty)*
+		math.Pow(stability, -f.weights[9])*
+		(math.Exp((1-retrievability)*f.weights[10])-1)*
+		hardPenalty*
+		easyBonus)
+}
+
+func (f *FSRS) forgetStability(difficulty, stability, retrievability float64) float64 {
+	return f.weights[11] *
+		math.Pow(difficulty, -f.weights[12]) *
+		(math.Pow(stability+1, f.weights[13]) - 1) *
+		math.Exp((1-retrievability)*f.weights[14])
+}
+
+func (f *FSRS) nextInterval(stability float64) int {
+	interval := stability / fsrsFactor * (math.Pow(f.desiredRetention, 1/fsrsDecay) - 1)
+
+	return min(max(int(math.Round(interval)), 1), fsrsMaxInterval)
+}
+
+func clampDifficulty(difficulty float64) float64 {
+	return min(max(difficulty, fsrsMinDifficulty), fsrsMaxDifficulty)
+}
@@ -0,0 +1,52 @@
+package srs
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	sm2InitialEaseFactor = 2.5
+	sm2MinEaseFactor     = 1.3
+	sm2FirstInterval     = 1
+	sm2SecondInterval    = 6
+)
+
+// SM2 schedules reviews with the SuperMemo 2 algorithm.
+type SM2 struct{}
+
+func NewSM2() *SM2 {
+	return &SM2{}
+}
+
+func (s *SM2) Review(state State, grade Grade, reviewedAt time.Time) State {
+	grade = min(max(grade, MinGrade), MaxGrade)
+
+	if state.EaseFactor == 0 {
+		state.EaseFactor = sm2InitialEaseFactor
+	}
+
+	if grade >= PassingGrade {
+		switch state.Repetitions {
+		case 0:
+			state.Interval = sm2FirstInterval
+		case 1:
+			state.Interval = sm2SecondInterval
+		default:
+			state.Interval = int(math.Round(float64(state.Interval) * state.EaseFactor))
+		}
+
+		state.Repetitions++
+	} else {
+		state.Repetitions = 0
+		state.Interval = sm2FirstInterval
+		state.Lapses++
+	}
+
+	quality := float64(MaxGrade - grade)
+	state.EaseFactor = max(state.EaseFactor+0.1-quality*(0.08+quality*0.02), sm2MinEaseFactor)
+	state.LastReview = reviewedAt
+	state.Due = daysAfter(reviewedAt, state.Interval)
+
+	return state
+}
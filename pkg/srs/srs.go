@@ -0,0 +1,36 @@
+// Package srs implements spaced repetition scheduling algorithms.
+package srs
+
+import "time"
+
+// Grade is a recall quality from 0 (complete blackout) to 5 (perfect response).
+type Grade int
+
+const (
+	MinGrade     Grade = 0
+	MaxGrade     Grade = 5
+	PassingGrade Grade = 3
+)
+
+const hoursPerDay = 24
+
+// State is a review state of a single card.
+// EaseFactor is used by SM-2 only, Stability and Difficulty are used by FSRS only.
+type State struct {
+	EaseFactor  float64
+	Stability   float64
+	Difficulty  float64
+	Interval    int
+	Repetitions int
+	Lapses      int
+	Due         time.Time
+	LastReview  time.Time
+}
+
+func daysAfter(t time.Time, days int) time.Time {
+	return t.Add(time.Duration(days) * hoursPerDay * time.Hour)
+}
+
+func daysBetween(from time.Time, to time.Time) float64 {
+	return max(to.Sub(from).Hours()/hoursPerDay, 0)
+}
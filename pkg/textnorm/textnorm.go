@@ -0,0 +1,142 @@
+// Package textnorm compares typed answers with expected ones regardless of insignificant differences:
+// letter case, diacritics, punctuation, extra spaces and articles of the text's language.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// articles are stripped from the beginning of normalized texts of the language.
+var articles = map[string][]string{
+	"en": {"the", "an", "a"},
+	"de": {"der", "die", "das", "den", "dem", "des", "ein", "eine", "einen", "einem", "einer"},
+	"fr": {"le", "la", "les", "l", "un", "une"},
+	"es": {"el", "la", "los", "las", "un", "una"},
+}
+
+// alternativesSeparators always separate acceptable variants of a single answer,
+// a comma separates them only when it's followed by a space and not by a digit like in "1, 5".
+const alternativesSeparators = ";/"
+
+var folder = cases.Fold()
+
+// Normalize brings text to a canonical form: NFC, case folding, no diacritics except for "й",
+// "ё" replaced with "е", no punctuation and single spaces between words. Leading articles are removed
+// for the known language only, texts of an empty or unknown language keep them.
+func Normalize(text string, language string) string {
+	text = folder.String(norm.NFC.String(text))
+
+	var builder strings.Builder
+
+	for _, r := range text {
+		switch {
+		case r == 'й':
+			builder.WriteRune(r)
+		case r == 'ё':
+			builder.WriteRune('е')
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			writeWithoutMarks(&builder, r)
+		default:
+			builder.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(builder.String())
+
+	for len(words) > 1 && isArticle(words[0], language) {
+		words = words[1:]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Alternatives splits text into normalized acceptable variants, empty variants are skipped.
+func Alternatives(text string, language string) []string {
+	parts := splitAlternatives(text)
+	alternatives := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if normalized := Normalize(part, language); normalized != "" {
+			alternatives = append(alternatives, normalized)
+		}
+	}
+
+	return alternatives
+}
+
+// Levenshtein returns the edit distance between texts in runes.
+func Levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+func writeWithoutMarks(builder *strings.Builder, r rune) {
+	for _, decomposed := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, decomposed) {
+			builder.WriteRune(decomposed)
+		}
+	}
+}
+
+func splitAlternatives(text string) []string {
+	parts := make([]string, 0, 1)
+	start := 0
+
+	for i, r := range text {
+		if strings.ContainsRune(alternativesSeparators, r) || (r == ',' && isSeparatingComma(text[i+1:])) {
+			parts = append(parts, text[start:i])
+			start = i + utf8.RuneLen(r)
+		}
+	}
+
+	return append(parts, text[start:])
+}
+
+func isSeparatingComma(rest string) bool {
+	trimmed := strings.TrimLeft(rest, " ")
+	if len(trimmed) == len(rest) {
+		return false
+	}
+
+	next, _ := utf8.DecodeRuneInString(trimmed)
+
+	return !unicode.IsDigit(next)
+}
+
+func isArticle(word string, language string) bool {
+	for _, article := range articles[language] {
+		if word == article {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,234 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxSheetNameLength = 31
+	defaultSheetName   = "Sheet"
+
+	// wrappedStyle is the cell format which wraps lines of multiline texts.
+	wrappedStyle = 1
+
+	xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	contentTypesXML = xmlHeader +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`%s</Types>`
+
+	sheetContentTypeXML = `<Override PartName="/xl/worksheets/sheet%d.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`
+
+	packageRelsXML = xmlHeader +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+		`Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookXML = xmlHeader +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>%s</sheets></workbook>`
+
+	workbookSheetXML = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`
+
+	workbookRelsXML = xmlHeader +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`%s<Relationship Id="rId%d" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" ` +
+		`Target="styles.xml"/>` +
+		`</Relationships>`
+
+	sheetRelXML = `<Relationship Id="rId%d" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" ` +
+		`Target="worksheets/sheet%d.xml"/>`
+
+	stylesXML = xmlHeader +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill>` +
+		`<fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0" applyAlignment="1">` +
+		`<alignment vertical="top" wrapText="1"/></xf></cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`
+
+	worksheetStartXML = xmlHeader +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	worksheetEndXML = `</sheetData></worksheet>`
+)
+
+// sheetNameReplacer replaces characters which Excel doesn't allow in sheet names.
+var sheetNameReplacer = strings.NewReplacer(
+	"[", "_", "]", "_", ":", "_", "*", "_", "?", "_", "/", "_", "\\", "_",
+)
+
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// Write writes the sheets as a workbook with texts in inline strings.
+// Sheet names are made valid for Excel: forbidden characters are replaced, long names are cut
+// and repeated names get numbers.
+func Write(w io.Writer, sheets []*Sheet) error {
+	archive := zip.NewWriter(w)
+
+	names := sheetNames(sheets)
+
+	var contentTypes, workbookSheets, workbookRels strings.Builder
+
+	for i, name := range names {
+		number := i + 1
+
+		fmt.Fprintf(&contentTypes, sheetContentTypeXML, number)
+		fmt.Fprintf(&workbookSheets, workbookSheetXML, escape(name), number, number)
+		fmt.Fprintf(&workbookRels, sheetRelXML, number, number)
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(contentTypesXML, contentTypes.String())},
+		{"_rels/.rels", packageRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, workbookSheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(workbookRelsXML, workbookRels.String(), len(sheets)+1)},
+		{"xl/styles.xml", stylesXML},
+	}
+
+	for _, part := range parts {
+		if err := writePart(archive, part.name, part.content); err != nil {
+			return err
+		}
+	}
+
+	for i, sheet := range sheets {
+		if err := writeSheet(archive, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.Rows); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func writePart(archive *zip.Writer, name string, content string) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(part, content)
+
+	return err
+}
+
+func writeSheet(archive *zip.Writer, name string, rows [][]string) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	var sheet strings.Builder
+
+	sheet.WriteString(worksheetStartXML)
+
+	for i, row := range rows {
+		rowNumber := strconv.Itoa(i + 1)
+
+		sheet.WriteString(`<row r="` + rowNumber + `">`)
+
+		for column, text := range row {
+			if text == "" {
+				continue
+			}
+
+			sheet.WriteString(`<c r="` + columnName(column) + rowNumber + `" t="inlineStr"`)
+
+			if strings.Contains(text, "\n") {
+				sheet.WriteString(` s="` + strconv.Itoa(wrappedStyle) + `"`)
+			}
+
+			sheet.WriteString(`><is><t xml:space="preserve">` + escape(text) + `</t></is></c>`)
+		}
+
+		sheet.WriteString(`</row>`)
+	}
+
+	sheet.WriteString(worksheetEndXML)
+
+	_, err = io.WriteString(part, sheet.String())
+
+	return err
+}
+
+// sheetNames makes names valid and unique, names are compared case-insensitively like Excel does.
+func sheetNames(sheets []*Sheet) []string {
+	names := make([]string, 0, len(sheets))
+	used := make(map[string]bool, len(sheets))
+
+	for _, sheet := range sheets {
+		base := strings.Trim(sheetNameReplacer.Replace(strings.TrimSpace(sheet.Name)), "'")
+		if base == "" {
+			base = defaultSheetName
+		}
+
+		name := cutName(base, "")
+
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = cutName(base, fmt.Sprintf(" (%d)", n))
+		}
+
+		used[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// cutName cuts the name, so it fits the sheet name limit together with the suffix.
+func cutName(name string, suffix string) string {
+	limit := maxSheetNameLength - utf8.RuneCountInString(suffix)
+
+	if utf8.RuneCountInString(name) > limit {
+		name = string([]rune(name)[:limit])
+	}
+
+	return name + suffix
+}
+
+// columnName converts a column index to letters: A, B, ..., Z, AA, AB and so on.
+func columnName(column int) string {
+	name := ""
+
+	for column++; column > 0; column = (column - 1) / 26 {
+		name = string(rune('A'+(column-1)%26)) + name
+	}
+
+	return name
+}
+
+func escape(text string) string {
+	var escaped strings.Builder
+
+	_ = xml.EscapeText(&escaped, []byte(text))
+
+	return escaped.String()
+}
@@ -0,0 +1,548 @@
+// Package xlsx reads and writes cell texts of Excel workbooks, zip archives of SpreadsheetML parts.
+//
+// Cells are read as they are stored: numbers keep their shortest decimal form, booleans become TRUE or FALSE,
+// dates are serial numbers and formulas are replaced with their cached values. Styles are ignored.
+// Written workbooks keep all cells as text, so terms like "007" or "1/2" are not converted by Excel.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxPartSize limits every unpacked xml part of the workbook.
+	MaxPartSize = 64 << 20
+
+	// Limits of Excel sheets, maxCells limits cells of all rows which are read from a sheet.
+	maxRows    = 1 << 20
+	maxColumns = 1 << 14
+	maxCells   = 1 << 22
+
+	relsPath = "_rels/.rels"
+
+	relTypeOfficeDocument = "/officeDocument"
+	relTypeWorksheet      = "/worksheet"
+	relTypeSharedStrings  = "/sharedStrings"
+)
+
+var (
+	ErrNotWorkbook      = errors.New("not an xlsx workbook")
+	ErrWorkbookTooLarge = errors.New("xlsx workbook is too large")
+	ErrCorrupted        = errors.New("xlsx workbook is corrupted")
+)
+
+type SheetNotFoundError struct {
+	Sheet string
+}
+
+func (e *SheetNotFoundError) Error() string {
+	return fmt.Sprintf("sheet \"%s\" is not found", e.Sheet)
+}
+
+type Workbook struct {
+	files         map[string]*zip.File
+	sheets        []sheetInfo
+	sharedStrings []string
+}
+
+type sheetInfo struct {
+	name string
+	path string
+}
+
+type relationship struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+type relationships struct {
+	Items []relationship `xml:"Relationship"`
+}
+
+type workbookSheet struct {
+	Name  string     `xml:"name,attr"`
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+type workbookPart struct {
+	Sheets []workbookSheet `xml:"sheets>sheet"`
+}
+
+// Open reads the workbook structure and its shared strings, sheets are read on demand.
+func Open(r io.ReaderAt, size int64) (*Workbook, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrNotWorkbook
+	}
+
+	wb := &Workbook{files: make(map[string]*zip.File, len(archive.File))}
+
+	for _, file := range archive.File {
+		wb.files[strings.TrimPrefix(file.Name, "/")] = file
+	}
+
+	var packageRels relationships
+
+	if err = wb.decodePart(relsPath, &packageRels); err != nil {
+		return nil, err
+	}
+
+	workbookPath, ok := findRelationship(packageRels, "", relTypeOfficeDocument)
+	if !ok {
+		return nil, ErrNotWorkbook
+	}
+
+	var workbook workbookPart
+
+	if err = wb.decodePart(workbookPath, &workbook); err != nil {
+		return nil, err
+	}
+
+	var workbookRels relationships
+
+	workbookRelsPath := path.Join(path.Dir(workbookPath), "_rels", path.Base(workbookPath)+".rels")
+
+	if err = wb.decodePart(workbookRelsPath, &workbookRels); err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]string, len(workbookRels.Items))
+
+	for _, rel := range workbookRels.Items {
+		if strings.HasSuffix(rel.Type, relTypeWorksheet) {
+			targets[rel.ID] = resolveTarget(workbookPath, rel.Target)
+		}
+	}
+
+	for _, sheet := range workbook.Sheets {
+		if sheetPath, ok := targets[relationshipID(sheet)]; ok {
+			wb.sheets = append(wb.sheets, sheetInfo{name: sheet.Name, path: sheetPath})
+		}
+	}
+
+	if sharedStringsPath, ok := findRelationship(workbookRels, workbookPath, relTypeSharedStrings); ok {
+		if wb.sharedStrings, err = wb.readSharedStrings(sharedStringsPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return wb, nil
+}
+
+// SheetNames returns names of worksheets in the workbook order.
+func (wb *Workbook) SheetNames() []string {
+	names := make([]string, 0, len(wb.sheets))
+
+	for _, sheet := range wb.sheets {
+		names = append(names, sheet.name)
+	}
+
+	return names
+}
+
+// HasSheet tells whether the sheet referred like in Rows exists.
+func (wb *Workbook) HasSheet(sheet string) bool {
+	_, ok := wb.findSheet(sheet)
+
+	return ok
+}
+
+// Rows reads cell texts of the sheet referred by name or by number starting from 1, the empty sheet is the first one.
+// The row with index i is the sheet's row i+1, rows without cells are nil.
+func (wb *Workbook) Rows(sheet string) ([][]string, error) {
+	info, ok := wb.findSheet(sheet)
+	if !ok {
+		return nil, &SheetNotFoundError{Sheet: sheet}
+	}
+
+	part, err := wb.openPart(info.path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer part.Close()
+
+	return wb.readRows(xml.NewDecoder(part))
+}
+
+func (wb *Workbook) findSheet(sheet string) (sheetInfo, bool) {
+	if len(wb.sheets) == 0 {
+		return sheetInfo{}, false
+	}
+
+	if sheet == "" {
+		return wb.sheets[0], true
+	}
+
+	for _, info := range wb.sheets {
+		if strings.EqualFold(strings.TrimSpace(info.name), strings.TrimSpace(sheet)) {
+			return info, true
+		}
+	}
+
+	number, err := strconv.Atoi(sheet)
+	if err != nil || number < 1 || number > len(wb.sheets) {
+		return sheetInfo{}, false
+	}
+
+	return wb.sheets[number-1], true
+}
+
+// readRows walks rows of the sheet data, cells are placed by their references.
+//
+//nolint:cyclop,funlen
+func (wb *Workbook) readRows(decoder *xml.Decoder) ([][]string, error) {
+	rows := make([][]string, 0)
+	cellsCount := 0
+
+	var (
+		row       []string
+		rowNumber int
+		column    int
+		cell      *cellValue
+	)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		if err != nil {
+			return nil, wrapPartError(err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch {
+			case element.Name.Local == "row":
+				rowNumber, err = rowReference(attr(element, "r"), rowNumber)
+				if err != nil {
+					return nil, err
+				}
+
+				row, column = nil, 0
+			case element.Name.Local == "c":
+				column, err = cellColumn(attr(element, "r"), column)
+				if err != nil {
+					return nil, err
+				}
+
+				cell = &cellValue{cellType: attr(element, "t")}
+			case cell != nil:
+				cell.start(element.Name.Local)
+			}
+		case xml.CharData:
+			if cell != nil {
+				cell.appendText(element)
+			}
+		case xml.EndElement:
+			switch {
+			case element.Name.Local == "c" && cell != nil:
+				text, err := cell.text(wb.sharedStrings)
+				if err != nil {
+					return nil, err
+				}
+
+				if text != "" {
+					if column >= len(row) {
+						cellsCount += column + 1 - len(row)
+						if cellsCount > maxCells {
+							return nil, ErrWorkbookTooLarge
+						}
+
+						row = append(row, make([]string, column+1-len(row))...)
+					}
+
+					row[column] = text
+				}
+
+				cell = nil
+				column++
+			case element.Name.Local == "row":
+				if row != nil {
+					if rowNumber > len(rows) {
+						rows = append(rows, make([][]string, rowNumber-len(rows))...)
+					}
+
+					rows[rowNumber-1] = row
+				}
+			case cell != nil:
+				cell.end(element.Name.Local)
+			}
+		}
+	}
+}
+
+// cellValue collects the value of a cell, either a v element or t elements of an inline string.
+// Phonetic runs of inline strings are skipped.
+type cellValue struct {
+	cellType string
+	value    strings.Builder
+	inText   bool
+	phonetic int
+}
+
+func (c *cellValue) start(name string) {
+	switch name {
+	case "v", "t":
+		c.inText = c.phonetic == 0
+	case "rPh":
+		c.phonetic++
+	}
+}
+
+func (c *cellValue) end(name string) {
+	switch name {
+	case "v", "t":
+		c.inText = false
+	case "rPh":
+		c.phonetic--
+	}
+}
+
+func (c *cellValue) appendText(text []byte) {
+	if c.inText {
+		c.value.Write(text)
+	}
+}
+
+func (c *cellValue) text(sharedStrings []string) (string, error) {
+	value := c.value.String()
+	if value == "" {
+		return "", nil
+	}
+
+	switch c.cellType {
+	case "s":
+		index, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || index < 0 || index >= len(sharedStrings) {
+			return "", ErrCorrupted
+		}
+
+		return sharedStrings[index], nil
+	case "b":
+		if strings.TrimSpace(value) == "1" {
+			return "TRUE", nil
+		}
+
+		return "FALSE", nil
+	case "", "n":
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return value, nil //nolint:nilerr
+		}
+
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	default:
+		return value, nil
+	}
+}
+
+// readSharedStrings reads texts of the shared strings table, rich text runs are joined.
+func (wb *Workbook) readSharedStrings(partPath string) ([]string, error) {
+	part, err := wb.openPart(partPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer part.Close()
+
+	decoder := xml.NewDecoder(part)
+	sharedStrings := make([]string, 0)
+
+	var item *cellValue
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return sharedStrings, nil
+		}
+
+		if err != nil {
+			return nil, wrapPartError(err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Local == "si" {
+				item = &cellValue{}
+			} else if item != nil {
+				item.start(element.Name.Local)
+			}
+		case xml.CharData:
+			if item != nil {
+				item.appendText(element)
+			}
+		case xml.EndElement:
+			if element.Name.Local == "si" && item != nil {
+				sharedStrings = append(sharedStrings, item.value.String())
+				item = nil
+			} else if item != nil {
+				item.end(element.Name.Local)
+			}
+		}
+	}
+}
+
+func (wb *Workbook) openPart(partPath string) (io.ReadCloser, error) {
+	file, ok := wb.files[partPath]
+	if !ok {
+		return nil, ErrNotWorkbook
+	}
+
+	if file.UncompressedSize64 > MaxPartSize {
+		return nil, ErrWorkbookTooLarge
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+
+	return &limitedPart{reader: reader}, nil
+}
+
+func (wb *Workbook) decodePart(partPath string, v any) error {
+	part, err := wb.openPart(partPath)
+	if err != nil {
+		return err
+	}
+
+	defer part.Close()
+
+	if err = xml.NewDecoder(part).Decode(v); err != nil {
+		return wrapPartError(err)
+	}
+
+	return nil
+}
+
+// limitedPart fails reading of a part which turns out to be larger than MaxPartSize despite its declared size.
+type limitedPart struct {
+	reader io.ReadCloser
+	read   int64
+}
+
+func (p *limitedPart) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.read += int64(n)
+
+	if p.read > MaxPartSize {
+		return n, ErrWorkbookTooLarge
+	}
+
+	return n, err
+}
+
+func (p *limitedPart) Close() error {
+	return p.reader.Close()
+}
+
+func wrapPartError(err error) error {
+	if errors.Is(err, ErrWorkbookTooLarge) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrCorrupted, err)
+}
+
+func findRelationship(rels relationships, sourcePath string, relType string) (string, bool) {
+	for _, rel := range rels.Items {
+		if strings.HasSuffix(rel.Type, relType) {
+			return resolveTarget(sourcePath, rel.Target), true
+		}
+	}
+
+	return "", false
+}
+
+// resolveTarget resolves the relationship target relative to the source part, absolute targets start with a slash.
+func resolveTarget(sourcePath string, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+
+	return path.Join(path.Dir(sourcePath), target)
+}
+
+// relationshipID returns the r:id attribute, its namespace differs in transitional and strict workbooks.
+func relationshipID(sheet workbookSheet) string {
+	for _, a := range sheet.Attrs {
+		if a.Name.Local == "id" && a.Name.Space != "" {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name && a.Name.Space == "" {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// rowReference returns the row number, a row without reference follows the previous one.
+func rowReference(reference string, previous int) (int, error) {
+	if reference == "" {
+		if previous >= maxRows {
+			return 0, ErrCorrupted
+		}
+
+		return previous + 1, nil
+	}
+
+	number, err := strconv.Atoi(reference)
+	if err != nil || number < 1 || number > maxRows {
+		return 0, ErrCorrupted
+	}
+
+	return number, nil
+}
+
+// cellColumn returns the column index of a cell reference like "B2", a cell without reference
+// takes the next column.
+func cellColumn(reference string, next int) (int, error) {
+	if reference == "" {
+		if next >= maxColumns {
+			return 0, ErrCorrupted
+		}
+
+		return next, nil
+	}
+
+	column := 0
+	letters := 0
+
+	for _, char := range strings.ToUpper(reference) {
+		if char < 'A' || char > 'Z' {
+			break
+		}
+
+		column = column*26 + int(char-'A') + 1
+		letters++
+
+		if column > maxColumns {
+			return 0, ErrCorrupted
+		}
+	}
+
+	if letters == 0 {
+		return 0, ErrCorrupted
+	}
+
+	return column - 1, nil
+}